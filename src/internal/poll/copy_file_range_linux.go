@@ -18,7 +18,10 @@ var isKernelVersionGE53 = sync.OnceValue(func() bool {
 	return major > 5 || (major == 5 && minor >= 3)
 })
 
-const maxCopyFileRangeRound = 1 << 30
+// maxCopyFileRangeRound is the maximum number of bytes requested from
+// a single copy_file_range(2) call. It is a variable so that tests can
+// force short rounds and exercise the retry loop below.
+var maxCopyFileRangeRound = 1 << 30
 
 // CopyFileRange copies at most remain bytes of data from src to dst, using
 // the copy_file_range system call. dst and src must refer to regular files.
@@ -29,8 +32,8 @@ func CopyFileRange(dst, src *FD, remain int64) (written int64, handled bool, err
 
 	for remain > 0 {
 		max := remain
-		if max > maxCopyFileRangeRound {
-			max = maxCopyFileRangeRound
+		if max > int64(maxCopyFileRangeRound) {
+			max = int64(maxCopyFileRangeRound)
 		}
 		n, err := copyFileRange(dst, src, int(max))
 		switch err {
@@ -0,0 +1,68 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package poll_test
+
+import (
+	"bytes"
+	"internal/poll"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// TestCopyFileRangeShortRounds checks that CopyFileRange keeps calling
+// copy_file_range(2) until the requested amount has been transferred
+// when each individual call moves fewer bytes than requested.
+func TestCopyFileRangeShortRounds(t *testing.T) {
+	const round = 4096
+	old := *poll.MaxCopyFileRangeRound
+	*poll.MaxCopyFileRangeRound = round
+	defer func() { *poll.MaxCopyFileRangeRound = old }()
+
+	dir := t.TempDir()
+	data := bytes.Repeat([]byte("0123456789abcdef"), 10*round/16+3)
+	srcName := filepath.Join(dir, "src")
+	if err := os.WriteFile(srcName, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	src := openPollFD(t, srcName, syscall.O_RDONLY)
+	dst := openPollFD(t, filepath.Join(dir, "dst"), syscall.O_RDWR|syscall.O_CREAT|syscall.O_TRUNC)
+
+	written, handled, err := poll.CopyFileRange(dst, src, int64(len(data)))
+	if !handled {
+		t.Skipf("copy_file_range not supported: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != int64(len(data)) {
+		t.Fatalf("wrote %d bytes, want %d", written, len(data))
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "dst"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("destination contents differ from source")
+	}
+}
+
+func openPollFD(t *testing.T, name string, mode int) *poll.FD {
+	t.Helper()
+	fd, err := syscall.Open(name, mode|syscall.O_CLOEXEC, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pfd := &poll.FD{Sysfd: fd, IsStream: true, ZeroReadIsEOF: true}
+	if err := pfd.Init("file", false); err != nil {
+		syscall.Close(fd)
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pfd.Close() })
+	return pfd
+}
@@ -13,6 +13,8 @@ var (
 	PutPipe     = putPipe
 	NewPipe     = newPipe
 	DestroyPipe = destroyPipe
+
+	MaxCopyFileRangeRound = &maxCopyFileRangeRound
 )
 
 func GetPipeFds(p *SplicePipe) (int, int) {
@@ -0,0 +1,20 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build freebsd || linux || netbsd
+
+package poll
+
+import "internal/syscall/unix"
+
+// Fdatasync wraps unix.Fdatasync.
+func (fd *FD) Fdatasync() error {
+	if err := fd.incref(); err != nil {
+		return err
+	}
+	defer fd.decref()
+	return ignoringEINTR(func() error {
+		return unix.Fdatasync(fd.Sysfd)
+	})
+}
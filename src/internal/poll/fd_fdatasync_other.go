@@ -0,0 +1,12 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || (js && wasm) || openbsd || solaris || wasip1 || windows
+
+package poll
+
+// Fdatasync is like Fsync on systems without fdatasync.
+func (fd *FD) Fdatasync() error {
+	return fd.Fsync()
+}
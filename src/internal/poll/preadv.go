@@ -0,0 +1,67 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build freebsd || linux
+
+package poll
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+)
+
+// Preadv wraps the preadv system call. Like Pread, it makes a single
+// successful call and does not use the poller.
+func (fd *FD) Preadv(v [][]byte, off int64) (int64, error) {
+	// Call incref, not readLock, because since preadv specifies the
+	// offset it is independent from other reads.
+	if err := fd.incref(); err != nil {
+		return 0, err
+	}
+	defer fd.decref()
+	iovecs := readIovecs(v, fd.IsStream)
+	if len(iovecs) == 0 {
+		return 0, nil
+	}
+	var (
+		n   uintptr
+		err error
+	)
+	for {
+		n, err = unix.Preadv(fd.Sysfd, iovecs, off)
+		if err != syscall.EINTR {
+			break
+		}
+	}
+	if err != nil {
+		n = 0
+	}
+	return int64(n), fd.eofError(int(n), err)
+}
+
+// Pwritev wraps the pwritev system call. Like Pwrite, it makes a single
+// successful call and does not use the poller.
+func (fd *FD) Pwritev(v [][]byte, off int64) (int64, error) {
+	// Call incref, not writeLock, because since pwritev specifies the
+	// offset it is independent from other writes.
+	if err := fd.incref(); err != nil {
+		return 0, err
+	}
+	defer fd.decref()
+	iovecs := readIovecs(v, fd.IsStream)
+	if len(iovecs) == 0 {
+		return 0, nil
+	}
+	var (
+		n   uintptr
+		err error
+	)
+	for {
+		n, err = unix.Pwritev(fd.Sysfd, iovecs, off)
+		if err != syscall.EINTR {
+			break
+		}
+	}
+	return int64(n), err
+}
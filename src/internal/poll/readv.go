@@ -0,0 +1,70 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package poll
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+)
+
+// Readv wraps the readv system call. Like Read, it makes a single
+// successful call, so it may fill fewer bytes than len(v) holds.
+func (fd *FD) Readv(v [][]byte) (int64, error) {
+	if err := fd.readLock(); err != nil {
+		return 0, err
+	}
+	defer fd.readUnlock()
+
+	iovecs := readIovecs(v, fd.IsStream)
+	if len(iovecs) == 0 {
+		// As in Read, return immediately for a zero byte read
+		// rather than reporting io.EOF.
+		return 0, nil
+	}
+	if err := fd.pd.prepareRead(fd.isFile); err != nil {
+		return 0, err
+	}
+	for {
+		n, err := unix.Readv(fd.Sysfd, iovecs)
+		if err != nil {
+			n = 0
+			if err == syscall.EINTR {
+				continue
+			}
+			if err == syscall.EAGAIN && fd.pd.pollable() {
+				if err = fd.pd.waitRead(fd.isFile); err == nil {
+					continue
+				}
+			}
+		}
+		return int64(n), fd.eofError(int(n), err)
+	}
+}
+
+// readIovecs returns iovecs describing the non-empty buffers in v,
+// capped at maxIovecs entries and, for streams, at maxRW bytes.
+func readIovecs(v [][]byte, isStream bool) []syscall.Iovec {
+	maxVec := maxIovecs()
+	iovecs := make([]syscall.Iovec, 0, min(len(v), maxVec))
+	var total int
+	for _, chunk := range v {
+		if len(chunk) == 0 {
+			continue
+		}
+		iovecs = append(iovecs, newIovecWithBase(&chunk[0]))
+		if isStream && total+len(chunk) > maxRW {
+			iovecs[len(iovecs)-1].SetLen(maxRW - total)
+			break
+		}
+		iovecs[len(iovecs)-1].SetLen(len(chunk))
+		total += len(chunk)
+		if len(iovecs) == maxVec {
+			break
+		}
+	}
+	return iovecs
+}
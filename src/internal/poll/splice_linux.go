@@ -30,7 +30,7 @@ const (
 // splice system call to minimize copies of data from and to userspace.
 //
 // Splice gets a pipe buffer from the pool or creates a new one if needed, to serve as a buffer for the data transfer.
-// src and dst must both be stream-oriented sockets.
+// src and dst must each be either a stream-oriented socket or a regular file.
 func Splice(dst, src *FD, remain int64) (written int64, handled bool, err error) {
 	p, err := getPipe()
 	if err != nil {
@@ -26,15 +26,6 @@ func (fd *FD) Writev(v *[][]byte) (int64, error) {
 	if fd.iovecs != nil {
 		iovecs = *fd.iovecs
 	}
-	// TODO: read from sysconf(_SC_IOV_MAX)? The Linux default is
-	// 1024 and this seems conservative enough for now. Darwin's
-	// UIO_MAXIOV also seems to be 1024.
-	maxVec := 1024
-	if runtime.GOOS == "aix" || runtime.GOOS == "solaris" {
-		// IOV_MAX is set to XOPEN_IOV_MAX on AIX and Solaris.
-		maxVec = 16
-	}
-
 	var n int64
 	var err error
 	for len(*v) > 0 {
@@ -49,7 +40,7 @@ func (fd *FD) Writev(v *[][]byte) (int64, error) {
 				break // continue chunk on next writev
 			}
 			iovecs[len(iovecs)-1].SetLen(len(chunk))
-			if len(iovecs) == maxVec {
+			if len(iovecs) == maxIovecs() {
 				break
 			}
 		}
@@ -88,3 +79,16 @@ func (fd *FD) Writev(v *[][]byte) (int64, error) {
 	}
 	return n, err
 }
+
+// maxIovecs returns the maximum number of iovecs passed to a single
+// readv or writev call.
+func maxIovecs() int {
+	// TODO: read from sysconf(_SC_IOV_MAX)? The Linux default is
+	// 1024 and this seems conservative enough for now. Darwin's
+	// UIO_MAXIOV also seems to be 1024.
+	if runtime.GOOS == "aix" || runtime.GOOS == "solaris" {
+		// IOV_MAX is set to XOPEN_IOV_MAX on AIX and Solaris.
+		return 16
+	}
+	return 1024
+}
@@ -23,3 +23,21 @@ TEXT ·libc_getgrnam_r_trampoline(SB),NOSPLIT,$0-0; JMP libc_getgrnam_r(SB)
 TEXT ·libc_getgrgid_r_trampoline(SB),NOSPLIT,$0-0; JMP libc_getgrgid_r(SB)
 TEXT ·libc_sysconf_trampoline(SB),NOSPLIT,$0-0; JMP libc_sysconf(SB)
 TEXT ·libc_faccessat_trampoline(SB),NOSPLIT,$0-0; JMP libc_faccessat(SB)
+TEXT ·libc_getxattr_trampoline(SB),NOSPLIT,$0-0; JMP libc_getxattr(SB)
+TEXT ·libc_fgetxattr_trampoline(SB),NOSPLIT,$0-0; JMP libc_fgetxattr(SB)
+TEXT ·libc_setxattr_trampoline(SB),NOSPLIT,$0-0; JMP libc_setxattr(SB)
+TEXT ·libc_fsetxattr_trampoline(SB),NOSPLIT,$0-0; JMP libc_fsetxattr(SB)
+TEXT ·libc_listxattr_trampoline(SB),NOSPLIT,$0-0; JMP libc_listxattr(SB)
+TEXT ·libc_flistxattr_trampoline(SB),NOSPLIT,$0-0; JMP libc_flistxattr(SB)
+TEXT ·libc_removexattr_trampoline(SB),NOSPLIT,$0-0; JMP libc_removexattr(SB)
+TEXT ·libc_fremovexattr_trampoline(SB),NOSPLIT,$0-0; JMP libc_fremovexattr(SB)
+TEXT ·libc_fcntl_trampoline(SB),NOSPLIT,$0-0; JMP libc_fcntl(SB)
+TEXT ·libc_mkdirat_trampoline(SB),NOSPLIT,$0-0; JMP libc_mkdirat(SB)
+TEXT ·libc_readlinkat_trampoline(SB),NOSPLIT,$0-0; JMP libc_readlinkat(SB)
+TEXT ·libc_setattrlist_trampoline(SB),NOSPLIT,$0-0; JMP libc_setattrlist(SB)
+TEXT ·libc_readv_trampoline(SB),NOSPLIT,$0-0; JMP libc_readv(SB)
+TEXT ·libc_msync_trampoline(SB),NOSPLIT,$0-0; JMP libc_msync(SB)
+TEXT ·libc_fchmodat_trampoline(SB),NOSPLIT,$0-0; JMP libc_fchmodat(SB)
+TEXT ·libc_fchownat_trampoline(SB),NOSPLIT,$0-0; JMP libc_fchownat(SB)
+TEXT ·libc_linkat_trampoline(SB),NOSPLIT,$0-0; JMP libc_linkat(SB)
+TEXT ·libc_fclonefileat_trampoline(SB),NOSPLIT,$0-0; JMP libc_fclonefileat(SB)
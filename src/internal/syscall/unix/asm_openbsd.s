@@ -8,3 +8,17 @@
 
 TEXT ·libc_faccessat_trampoline(SB),NOSPLIT,$0-0
         JMP	libc_faccessat(SB)
+TEXT ·libc_mkdirat_trampoline(SB),NOSPLIT,$0-0
+        JMP	libc_mkdirat(SB)
+TEXT ·libc_readlinkat_trampoline(SB),NOSPLIT,$0-0
+        JMP	libc_readlinkat(SB)
+TEXT ·libc_readv_trampoline(SB),NOSPLIT,$0-0
+        JMP	libc_readv(SB)
+TEXT ·libc_msync_trampoline(SB),NOSPLIT,$0-0
+        JMP	libc_msync(SB)
+TEXT ·libc_fchmodat_trampoline(SB),NOSPLIT,$0-0
+        JMP	libc_fchmodat(SB)
+TEXT ·libc_fchownat_trampoline(SB),NOSPLIT,$0-0
+        JMP	libc_fchownat(SB)
+TEXT ·libc_linkat_trampoline(SB),NOSPLIT,$0-0
+        JMP	libc_linkat(SB)
@@ -11,13 +11,21 @@ import (
 	"unsafe"
 )
 
+//go:linkname procFchmodat libc_fchmodat
+//go:linkname procFchownat libc_fchownat
 //go:linkname procFstatat libc_fstatat
+//go:linkname procMkdirat libc_mkdirat
 //go:linkname procOpenat libc_openat
+//go:linkname procReadlinkat libc_readlinkat
 //go:linkname procUnlinkat libc_unlinkat
 
 var (
+	procFchmodat,
+	procFchownat,
 	procFstatat,
+	procMkdirat,
 	procOpenat,
+	procReadlinkat,
 	procUnlinkat uintptr
 )
 
@@ -62,3 +70,54 @@ func Fstatat(dirfd int, path string, stat *syscall.Stat_t, flags int) error {
 
 	return nil
 }
+
+func Mkdirat(dirfd int, path string, mode uint32) error {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return err
+	}
+
+	_, _, errno := syscall6(uintptr(unsafe.Pointer(&procMkdirat)), 3, uintptr(dirfd), uintptr(unsafe.Pointer(p)), uintptr(mode), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+func Readlinkat(dirfd int, path string, buf []byte) (int, error) {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	n, _, errno := syscall6(uintptr(unsafe.Pointer(&procReadlinkat)), 4, uintptr(dirfd), uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(unsafe.SliceData(buf))), uintptr(len(buf)), 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+
+	return int(n), nil
+}
+
+func Fchmodat(dirfd int, path string, mode uint32, flags int) error {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall6(uintptr(unsafe.Pointer(&procFchmodat)), 4, uintptr(dirfd), uintptr(unsafe.Pointer(p)), uintptr(mode), uintptr(flags), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func Fchownat(dirfd int, path string, uid, gid int, flags int) error {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall6(uintptr(unsafe.Pointer(&procFchownat)), 5, uintptr(dirfd), uintptr(unsafe.Pointer(p)), uintptr(uid), uintptr(gid), uintptr(flags), 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ const (
 	AT_FDCWD            = -0x2
 	AT_REMOVEDIR        = 0x80
 	AT_SYMLINK_NOFOLLOW = 0x0020
+	AT_SYMLINK_FOLLOW   = 0x0040
 
 	UTIME_OMIT = -0x2
 )
@@ -7,14 +7,22 @@ package unix
 import "syscall"
 
 const (
-	unlinkatTrap uintptr = syscall.SYS_UNLINKAT
-	openatTrap   uintptr = syscall.SYS_OPENAT
-	fstatatTrap  uintptr = syscall.SYS_FSTATAT
+	unlinkatTrap   uintptr = syscall.SYS_UNLINKAT
+	openatTrap     uintptr = syscall.SYS_OPENAT
+	fstatatTrap    uintptr = syscall.SYS_FSTATAT
+	mkdiratTrap    uintptr = syscall.SYS_MKDIRAT
+	readlinkatTrap uintptr = syscall.SYS_READLINKAT
+	fchmodatTrap   uintptr = syscall.SYS_FCHMODAT
+	fchownatTrap   uintptr = syscall.SYS_FCHOWNAT
+	linkatTrap     uintptr = syscall.SYS_LINKAT
+
+	openatFlags = 0
 
 	AT_EACCESS          = 0x4
 	AT_FDCWD            = 0xfffafdcd
 	AT_REMOVEDIR        = 0x2
 	AT_SYMLINK_NOFOLLOW = 0x1
+	AT_SYMLINK_FOLLOW   = 0x8
 
 	UTIME_OMIT = -0x2
 )
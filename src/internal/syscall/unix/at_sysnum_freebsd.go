@@ -11,10 +11,20 @@ const (
 	AT_FDCWD            = -0x64
 	AT_REMOVEDIR        = 0x800
 	AT_SYMLINK_NOFOLLOW = 0x200
+	AT_SYMLINK_FOLLOW   = 0x400
 
 	UTIME_OMIT = -0x2
 
 	unlinkatTrap       uintptr = syscall.SYS_UNLINKAT
 	openatTrap         uintptr = syscall.SYS_OPENAT
+	mkdiratTrap        uintptr = syscall.SYS_MKDIRAT
+	readlinkatTrap     uintptr = syscall.SYS_READLINKAT
+	fchmodatTrap       uintptr = syscall.SYS_FCHMODAT
+	fchownatTrap       uintptr = syscall.SYS_FCHOWNAT
+	linkatTrap         uintptr = syscall.SYS_LINKAT
 	posixFallocateTrap uintptr = syscall.SYS_POSIX_FALLOCATE
+	posixFadviseTrap   uintptr = syscall.SYS_POSIX_FADVISE
+	fdatasyncTrap      uintptr = 550 // not in package syscall, which predates FreeBSD 11.1
+
+	openatFlags = 0
 )
@@ -8,12 +8,21 @@ import "syscall"
 
 const unlinkatTrap uintptr = syscall.SYS_UNLINKAT
 const openatTrap uintptr = syscall.SYS_OPENAT
+const mkdiratTrap uintptr = syscall.SYS_MKDIRAT
+const readlinkatTrap uintptr = syscall.SYS_READLINKAT
+const fchownatTrap uintptr = syscall.SYS_FCHOWNAT
+const linkatTrap uintptr = syscall.SYS_LINKAT
+
+// openatFlags are always passed to openat, as glibc does.
+const openatFlags = syscall.O_LARGEFILE
 
 const (
 	AT_EACCESS          = 0x200
 	AT_FDCWD            = -0x64
 	AT_REMOVEDIR        = 0x200
 	AT_SYMLINK_NOFOLLOW = 0x100
+	AT_SYMLINK_FOLLOW   = 0x400
+	AT_EMPTY_PATH       = 0x1000
 
 	UTIME_OMIT = 0x3ffffffe
 )
@@ -9,12 +9,21 @@ import "syscall"
 const unlinkatTrap uintptr = syscall.SYS_UNLINKAT
 const openatTrap uintptr = syscall.SYS_OPENAT
 const fstatatTrap uintptr = syscall.SYS_FSTATAT
+const mkdiratTrap uintptr = syscall.SYS_MKDIRAT
+const readlinkatTrap uintptr = syscall.SYS_READLINKAT
+const fchmodatTrap uintptr = syscall.SYS_FCHMODAT
+const fchownatTrap uintptr = syscall.SYS_FCHOWNAT
+const linkatTrap uintptr = syscall.SYS_LINKAT
+
+const openatFlags = 0
+const fdatasyncTrap uintptr = syscall.SYS_FDATASYNC
 
 const (
 	AT_EACCESS          = 0x100
 	AT_FDCWD            = -0x64
 	AT_REMOVEDIR        = 0x800
 	AT_SYMLINK_NOFOLLOW = 0x200
+	AT_SYMLINK_FOLLOW   = 0x400
 
 	UTIME_OMIT = (1 << 30) - 2
 )
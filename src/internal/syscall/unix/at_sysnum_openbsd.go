@@ -9,12 +9,20 @@ import "syscall"
 const unlinkatTrap uintptr = syscall.SYS_UNLINKAT
 const openatTrap uintptr = syscall.SYS_OPENAT
 const fstatatTrap uintptr = syscall.SYS_FSTATAT
+const mkdiratTrap uintptr = syscall.SYS_MKDIRAT
+const readlinkatTrap uintptr = syscall.SYS_READLINKAT
+const fchmodatTrap uintptr = syscall.SYS_FCHMODAT
+const fchownatTrap uintptr = syscall.SYS_FCHOWNAT
+const linkatTrap uintptr = syscall.SYS_LINKAT
+
+const openatFlags = 0
 
 const (
 	AT_EACCESS          = 0x1
 	AT_FDCWD            = -0x64
 	AT_REMOVEDIR        = 0x08
 	AT_SYMLINK_NOFOLLOW = 0x02
+	AT_SYMLINK_FOLLOW   = 0x04
 
 	UTIME_OMIT = -0x1
 )
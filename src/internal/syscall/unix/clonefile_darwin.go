@@ -0,0 +1,35 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"internal/abi"
+	"syscall"
+	"unsafe"
+)
+
+// Flags for Fclonefileat, from <sys/clonefile.h>.
+const (
+	CLONE_NOFOLLOW    = 0x0001
+	CLONE_NOOWNERCOPY = 0x0002
+)
+
+func libc_fclonefileat_trampoline()
+
+//go:cgo_import_dynamic libc_fclonefileat fclonefileat "/usr/lib/libSystem.B.dylib"
+
+// Fclonefileat creates dst, relative to dstdirfd, as a copy-on-write
+// clone of the file open as srcfd. dst must not exist.
+func Fclonefileat(srcfd, dstdirfd int, dst string, flags int) error {
+	p, err := syscall.BytePtrFromString(dst)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall_syscall6(abi.FuncPCABI0(libc_fclonefileat_trampoline), uintptr(srcfd), uintptr(dstdirfd), uintptr(unsafe.Pointer(p)), uintptr(flags), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"internal/abi"
+	"unsafe"
+)
+
+const (
+	F_RDADVISE = 0x2c
+	F_RDAHEAD  = 0x2d
+)
+
+func libc_fcntl_trampoline()
+
+//go:cgo_import_dynamic libc_fcntl fcntl "/usr/lib/libSystem.B.dylib"
+
+// RdAdvise issues an advisory read of count bytes at off using F_RDADVISE.
+func RdAdvise(fd int, off int64, count int32) error {
+	ra := struct {
+		off   int64
+		count int32
+	}{off, count}
+	_, _, errno := syscall_syscall(abi.FuncPCABI0(libc_fcntl_trampoline), uintptr(fd), F_RDADVISE, uintptr(unsafe.Pointer(&ra)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// RdAhead turns read-ahead on or off using F_RDAHEAD.
+func RdAhead(fd int, on bool) error {
+	var arg uintptr
+	if on {
+		arg = 1
+	}
+	_, _, errno := syscall_syscall(abi.FuncPCABI0(libc_fcntl_trampoline), uintptr(fd), F_RDAHEAD, arg)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
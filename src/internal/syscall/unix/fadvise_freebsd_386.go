@@ -0,0 +1,17 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import "syscall"
+
+func PosixFadvise(fd int, off int64, size int64, advice int) error {
+	// Like posix_fallocate, posix_fadvise returns an error on failure
+	// without setting errno.
+	r1, _, _ := syscall.Syscall6(posixFadviseTrap, uintptr(fd), uintptr(off), uintptr(off>>32), uintptr(size), uintptr(size>>32), uintptr(advice))
+	if r1 != 0 {
+		return syscall.Errno(r1)
+	}
+	return nil
+}
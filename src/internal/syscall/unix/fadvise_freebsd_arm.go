@@ -0,0 +1,20 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import "syscall"
+
+func PosixFadvise(fd int, off int64, size int64, advice int) error {
+	// Like posix_fallocate, posix_fadvise returns an error on failure
+	// without setting errno.
+	//
+	// The padding 0 argument is needed for the double-word alignment
+	// of off; see PosixFallocate.
+	r1, _, _ := syscall.Syscall9(posixFadviseTrap, uintptr(fd), 0, uintptr(off), uintptr(off>>32), uintptr(size), uintptr(size>>32), uintptr(advice), 0, 0)
+	if r1 != 0 {
+		return syscall.Errno(r1)
+	}
+	return nil
+}
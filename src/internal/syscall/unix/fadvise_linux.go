@@ -0,0 +1,12 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+const (
+	POSIX_FADV_NORMAL     = 0x0
+	POSIX_FADV_RANDOM     = 0x1
+	POSIX_FADV_SEQUENTIAL = 0x2
+	POSIX_FADV_WILLNEED   = 0x3
+)
@@ -0,0 +1,17 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import "syscall"
+
+func PosixFadvise(fd int, off int64, size int64, advice int) error {
+	// arm_fadvise64_64 takes advice second so that the 64-bit
+	// arguments fall on even register pairs.
+	_, _, errno := syscall.Syscall6(syscall.SYS_ARM_FADVISE64_64, uintptr(fd), uintptr(advice), uintptr(off), uintptr(off>>32), uintptr(size), uintptr(size>>32))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
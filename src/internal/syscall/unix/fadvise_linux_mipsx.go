@@ -0,0 +1,28 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && (mips || mipsle)
+
+package unix
+
+import (
+	"internal/goarch"
+	"syscall"
+)
+
+func PosixFadvise(fd int, off int64, size int64, advice int) error {
+	// The o32 ABI passes 64-bit arguments in aligned register pairs,
+	// in memory order, so a padding argument follows fd.
+	offLo, offHi := uintptr(off), uintptr(off>>32)
+	sizeLo, sizeHi := uintptr(size), uintptr(size>>32)
+	if goarch.BigEndian {
+		offLo, offHi = offHi, offLo
+		sizeLo, sizeHi = sizeHi, sizeLo
+	}
+	_, _, errno := syscall.Syscall9(syscall.SYS_FADVISE64, uintptr(fd), 0, offLo, offHi, sizeLo, sizeHi, uintptr(advice), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
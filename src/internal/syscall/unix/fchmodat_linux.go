@@ -0,0 +1,14 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import "syscall"
+
+// Fchmodat is syscall.Fchmodat, which uses fchmodat2 when flags are
+// given, as fchmodat ignores them. Without fchmodat2 (before Linux 6.6),
+// AT_SYMLINK_NOFOLLOW fails with EOPNOTSUPP.
+func Fchmodat(dirfd int, path string, mode uint32, flags int) error {
+	return syscall.Fchmodat(dirfd, path, mode, flags)
+}
@@ -0,0 +1,17 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build freebsd || netbsd
+
+package unix
+
+import "syscall"
+
+func Fdatasync(fd int) error {
+	_, _, errno := syscall.Syscall(fdatasyncTrap, uintptr(fd), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import "syscall"
+
+// Ficlone makes the file open as dstfd share the contents of the file
+// open as srcfd, using the FICLONE ioctl. The two files must be on the
+// same file system, and the file system must support reflinks.
+func Ficlone(dstfd, srcfd int) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(dstfd), FICLONE, uintptr(srcfd))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && (mips || mipsle || mips64 || mips64le || ppc64 || ppc64le)
+
+package unix
+
+// FICLONE is _IOW(0x94, 9, int), with the direction encoded as on
+// MIPS and PowerPC.
+const FICLONE = 0x80049409
@@ -0,0 +1,13 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build (unix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd) || wasip1 || (js && wasm)
+
+package unix
+
+// IsTerminal reports whether fd refers to a terminal.
+// It is not implemented on this system, and always reports false.
+func IsTerminal(fd int) bool {
+	return false
+}
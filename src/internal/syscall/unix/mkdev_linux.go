@@ -0,0 +1,15 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+// Mkdev returns a device number in the encoding used by glibc
+// from its major and minor numbers.
+func Mkdev(major, minor uint32) uint64 {
+	majorH := uint64(major >> 12)
+	majorL := uint64(major & 0xfff)
+	minorH := uint64(minor >> 8)
+	minorL := uint64(minor & 0xff)
+	return (majorH << 44) | (minorH << 20) | (majorL << 8) | minorL
+}
@@ -0,0 +1,11 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix && !netbsd
+
+package unix
+
+import "syscall"
+
+const MS_SYNC = syscall.MS_SYNC
@@ -0,0 +1,23 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || solaris
+
+package unix
+
+import (
+	"unsafe"
+)
+
+//go:linkname procMsync libc_msync
+
+var procMsync uintptr
+
+func Msync(b []byte, flags int) error {
+	_, _, errno := syscall6(uintptr(unsafe.Pointer(&procMsync)), 3, uintptr(unsafe.Pointer(unsafe.SliceData(b))), uintptr(len(b)), uintptr(flags), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+// NetBSD renamed msync to __msync13, which is missing from package syscall.
+const msyncTrap uintptr = 277
+
+// MS_SYNC is missing from package syscall on netbsd/arm.
+const MS_SYNC = 0x4
@@ -0,0 +1,24 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build openbsd && !mips64
+
+package unix
+
+import (
+	"internal/abi"
+	"unsafe"
+)
+
+func libc_msync_trampoline()
+
+//go:cgo_import_dynamic libc_msync msync "libc.so"
+
+func Msync(b []byte, flags int) error {
+	_, _, errno := syscall_syscall6(abi.FuncPCABI0(libc_msync_trampoline), uintptr(unsafe.Pointer(unsafe.SliceData(b))), uintptr(len(b)), uintptr(flags), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
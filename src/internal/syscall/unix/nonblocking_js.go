@@ -6,6 +6,10 @@
 
 package unix
 
+// NonblockFlag is the open flag that requests non-blocking mode. It is 0 here, as files
+// cannot be opened in non-blocking mode.
+const NonblockFlag = 0
+
 func IsNonblock(fd int) (nonblocking bool, err error) {
 	return false, nil
 }
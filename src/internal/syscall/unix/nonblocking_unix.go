@@ -8,6 +8,9 @@ package unix
 
 import "syscall"
 
+// NonblockFlag is the open flag that requests non-blocking mode.
+const NonblockFlag = syscall.O_NONBLOCK
+
 func IsNonblock(fd int) (nonblocking bool, err error) {
 	flag, e1 := Fcntl(fd, syscall.F_GETFL, 0)
 	if e1 != nil {
@@ -11,6 +11,10 @@ import (
 	_ "unsafe" // for go:linkname
 )
 
+// NonblockFlag is the open flag that requests non-blocking mode. It is 0 here, as files
+// cannot be opened in non-blocking mode.
+const NonblockFlag = 0
+
 func IsNonblock(fd int) (nonblocking bool, err error) {
 	flags, e1 := fd_fdstat_get_flags(fd)
 	if e1 != nil {
@@ -0,0 +1,45 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// O_PATH opens a file only as a location in the file system. It has the
+// same value on all architectures, but is missing from package syscall
+// on some of them.
+const O_PATH = 0x200000
+
+// Resolve flags for Openat2, from linux/openat2.h.
+const (
+	RESOLVE_NO_XDEV       = 0x01
+	RESOLVE_NO_MAGICLINKS = 0x02
+	RESOLVE_NO_SYMLINKS   = 0x04
+	RESOLVE_BENEATH       = 0x08
+	RESOLVE_IN_ROOT       = 0x10
+	RESOLVE_CACHED        = 0x20
+)
+
+// OpenHow is the struct open_how from linux/openat2.h.
+type OpenHow struct {
+	Flags   uint64
+	Mode    uint64
+	Resolve uint64
+}
+
+// Openat2 calls the openat2 system call, available since Linux 5.6.
+func Openat2(dirfd int, path string, how *OpenHow) (int, error) {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	fd, _, errno := syscall.Syscall6(openat2Trap, uintptr(dirfd), uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(how)), unsafe.Sizeof(*how), 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(fd), nil
+}
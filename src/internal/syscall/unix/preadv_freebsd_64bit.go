@@ -0,0 +1,28 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build freebsd && (amd64 || arm64 || riscv64)
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func Preadv(fd int, iovecs []syscall.Iovec, offset int64) (uintptr, error) {
+	n, _, errno := syscall.Syscall6(syscall.SYS_PREADV, uintptr(fd), uintptr(unsafe.Pointer(unsafe.SliceData(iovecs))), uintptr(len(iovecs)), uintptr(offset), 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return n, nil
+}
+
+func Pwritev(fd int, iovecs []syscall.Iovec, offset int64) (uintptr, error) {
+	n, _, errno := syscall.Syscall6(syscall.SYS_PWRITEV, uintptr(fd), uintptr(unsafe.Pointer(unsafe.SliceData(iovecs))), uintptr(len(iovecs)), uintptr(offset), 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return n, nil
+}
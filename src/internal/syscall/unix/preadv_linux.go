@@ -0,0 +1,35 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// offs2lohi splits offs into the two words that preadv and pwritev
+// expect on every architecture.
+func offs2lohi(offs int64) (lo, hi uintptr) {
+	const longBits = unsafe.Sizeof(uintptr(0)) * 8
+	return uintptr(offs), uintptr(uint64(offs) >> (longBits - 1) >> 1)
+}
+
+func Preadv(fd int, iovecs []syscall.Iovec, offset int64) (uintptr, error) {
+	lo, hi := offs2lohi(offset)
+	n, _, errno := syscall.Syscall6(syscall.SYS_PREADV, uintptr(fd), uintptr(unsafe.Pointer(unsafe.SliceData(iovecs))), uintptr(len(iovecs)), lo, hi, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return n, nil
+}
+
+func Pwritev(fd int, iovecs []syscall.Iovec, offset int64) (uintptr, error) {
+	lo, hi := offs2lohi(offset)
+	n, _, errno := syscall.Syscall6(syscall.SYS_PWRITEV, uintptr(fd), uintptr(unsafe.Pointer(unsafe.SliceData(iovecs))), uintptr(len(iovecs)), lo, hi, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return n, nil
+}
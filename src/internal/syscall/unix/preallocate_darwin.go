@@ -0,0 +1,21 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"internal/abi"
+	"syscall"
+	"unsafe"
+)
+
+// Preallocate allocates storage for fd as described by store
+// using F_PREALLOCATE.
+func Preallocate(fd int, store *syscall.Fstore_t) error {
+	_, _, errno := syscall_syscall(abi.FuncPCABI0(libc_fcntl_trampoline), uintptr(fd), syscall.F_PREALLOCATE, uintptr(unsafe.Pointer(store)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build openbsd && !mips64
+
+package unix
+
+import (
+	"internal/abi"
+	"syscall"
+	"unsafe"
+)
+
+func libc_readv_trampoline()
+
+//go:cgo_import_dynamic libc_readv readv "libc.so"
+
+func Readv(fd int, iovecs []syscall.Iovec) (uintptr, error) {
+	n, _, errno := syscall_syscall6(abi.FuncPCABI0(libc_readv_trampoline), uintptr(fd), uintptr(unsafe.Pointer(unsafe.SliceData(iovecs))), uintptr(len(iovecs)), 0, 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return n, nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"internal/abi"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	ATTR_BIT_MAP_COUNT = 5
+	ATTR_CMN_CRTIME    = 0x200
+	FSOPT_NOFOLLOW     = 0x1
+)
+
+// Attrlist is the struct attrlist from sys/attr.h.
+type Attrlist struct {
+	Bitmapcount uint16
+	Reserved    uint16
+	Commonattr  uint32
+	Volattr     uint32
+	Dirattr     uint32
+	Fileattr    uint32
+	Forkattr    uint32
+}
+
+func libc_setattrlist_trampoline()
+
+//go:cgo_import_dynamic libc_setattrlist setattrlist "/usr/lib/libSystem.B.dylib"
+
+func Setattrlist(path string, attrlist *Attrlist, attrBuf unsafe.Pointer, attrBufSize uintptr, options uint32) error {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall_syscall6(abi.FuncPCABI0(libc_setattrlist_trampoline), uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(attrlist)), uintptr(attrBuf), attrBufSize, uintptr(options), 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// Statvfs_t represents the fields of a struct statvfs defined in
+// <sys/statvfs.h>. The fields declared as unsigned long in C are
+// uintptr, so that the layout matches on both 32-bit and 64-bit systems.
+type Statvfs_t struct {
+	Flag        uintptr
+	Bsize       uintptr
+	Frsize      uintptr
+	Iosize      uintptr
+	Blocks      uint64
+	Bfree       uint64
+	Bavail      uint64
+	Bresvd      uint64
+	Files       uint64
+	Ffree       uint64
+	Favail      uint64
+	Fresvd      uint64
+	Syncreads   uint64
+	Syncwrites  uint64
+	Asyncreads  uint64
+	Asyncwrites uint64
+	Fsidx       [2]int32
+	Fsid        uintptr
+	Namemax     uintptr
+	Owner       uint32
+	Spare       [4]uint32
+	Fstypename  [32]byte
+	Mntonname   [1024]byte
+	Mntfromname [1024]byte
+	_           [4]byte // padding to the alignment of the C struct
+}
+
+// ST_WAIT makes statvfs1 and fstatvfs1 refresh the statistics from the
+// file system, as statvfs and fstatvfs in the C library do.
+const ST_WAIT = 0x1
+
+func Statvfs(path string, buf *Statvfs_t) error {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_STATVFS1, uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(buf)), ST_WAIT)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func Fstatvfs(fd int, buf *Statvfs_t) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_FSTATVFS1, uintptr(fd), uintptr(unsafe.Pointer(buf)), ST_WAIT)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
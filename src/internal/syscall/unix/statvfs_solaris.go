@@ -0,0 +1,56 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+//go:linkname procStatvfs libc_statvfs
+//go:linkname procFstatvfs libc_fstatvfs
+
+var (
+	procStatvfs,
+	procFstatvfs uintptr
+)
+
+// Statvfs_t represents the fields of a struct statvfs defined in
+// <sys/statvfs.h>.
+type Statvfs_t struct {
+	Bsize    uint64
+	Frsize   uint64
+	Blocks   uint64
+	Bfree    uint64
+	Bavail   uint64
+	Files    uint64
+	Ffree    uint64
+	Favail   uint64
+	Fsid     uint64
+	Basetype [16]int8
+	Flag     uint64
+	Namemax  uint64
+	Fstr     [32]int8
+}
+
+func Statvfs(path string, buf *Statvfs_t) error {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall6(uintptr(unsafe.Pointer(&procStatvfs)), 2, uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(buf)), 0, 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func Fstatvfs(fd int, buf *Statvfs_t) error {
+	_, _, errno := syscall6(uintptr(unsafe.Pointer(&procFstatvfs)), 2, uintptr(fd), uintptr(unsafe.Pointer(buf)), 0, 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const (
+	AT_NO_AUTOMOUNT = 0x800
+
+	STATX_TYPE        = 0x1
+	STATX_MODE        = 0x2
+	STATX_NLINK       = 0x4
+	STATX_UID         = 0x8
+	STATX_GID         = 0x10
+	STATX_ATIME       = 0x20
+	STATX_MTIME       = 0x40
+	STATX_CTIME       = 0x80
+	STATX_INO         = 0x100
+	STATX_SIZE        = 0x200
+	STATX_BLOCKS      = 0x400
+	STATX_BASIC_STATS = 0x7ff
+	STATX_BTIME       = 0x800
+)
+
+type StatxTimestamp struct {
+	Sec  int64
+	Nsec uint32
+	_    int32
+}
+
+// Statx_t is the struct statx from linux/stat.h.
+type Statx_t struct {
+	Mask            uint32
+	Blksize         uint32
+	Attributes      uint64
+	Nlink           uint32
+	Uid             uint32
+	Gid             uint32
+	Mode            uint16
+	_               [1]uint16
+	Ino             uint64
+	Size            uint64
+	Blocks          uint64
+	Attributes_mask uint64
+	Atime           StatxTimestamp
+	Btime           StatxTimestamp
+	Ctime           StatxTimestamp
+	Mtime           StatxTimestamp
+	Rdev_major      uint32
+	Rdev_minor      uint32
+	Dev_major       uint32
+	Dev_minor       uint32
+	_               [14]uint64
+}
+
+// Statx calls the statx system call, available since Linux 4.11.
+func Statx(dirfd int, path string, flags int, mask int, stat *Statx_t) error {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall.Syscall6(statxTrap, uintptr(dirfd), uintptr(unsafe.Pointer(p)), uintptr(flags), uintptr(mask), uintptr(unsafe.Pointer(stat)), 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
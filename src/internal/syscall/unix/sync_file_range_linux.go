@@ -0,0 +1,13 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && !arm
+
+package unix
+
+import "syscall"
+
+func SyncFileRange(fd int, off int64, n int64, flags int) error {
+	return syscall.SyncFileRange(fd, off, n, flags)
+}
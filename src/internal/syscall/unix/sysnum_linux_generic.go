@@ -15,4 +15,6 @@ const (
 	copyFileRangeTrap   uintptr = 285
 	pidfdSendSignalTrap uintptr = 424
 	pidfdOpenTrap       uintptr = 434
+	openat2Trap         uintptr = 437
+	statxTrap           uintptr = 291
 )
@@ -11,4 +11,6 @@ const (
 	copyFileRangeTrap   uintptr = 5320
 	pidfdSendSignalTrap uintptr = 5424
 	pidfdOpenTrap       uintptr = 5434
+	openat2Trap         uintptr = 5437
+	statxTrap           uintptr = 5326
 )
@@ -11,4 +11,6 @@ const (
 	copyFileRangeTrap   uintptr = 379
 	pidfdSendSignalTrap uintptr = 424
 	pidfdOpenTrap       uintptr = 434
+	openat2Trap         uintptr = 437
+	statxTrap           uintptr = 383
 )
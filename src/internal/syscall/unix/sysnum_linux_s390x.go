@@ -9,4 +9,6 @@ const (
 	copyFileRangeTrap   uintptr = 375
 	pidfdSendSignalTrap uintptr = 424
 	pidfdOpenTrap       uintptr = 434
+	openat2Trap         uintptr = 437
+	statxTrap           uintptr = 379
 )
@@ -0,0 +1,11 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && !mips && !mipsle && !mips64 && !mips64le
+
+package unix
+
+import "syscall"
+
+const O_TMPFILE = 0x400000 | syscall.O_DIRECTORY
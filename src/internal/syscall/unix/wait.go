@@ -0,0 +1,11 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build (unix && !solaris) || (js && wasm) || wasip1
+
+package unix
+
+// WNOHANG makes wait4 return immediately if no child has exited.
+// Package syscall does not define it on every Unix system.
+const WNOHANG = 0x1
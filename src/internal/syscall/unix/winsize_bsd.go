@@ -0,0 +1,22 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// GetWinsize returns the size of the terminal fd refers to,
+// with the TIOCGWINSZ ioctl.
+func GetWinsize(fd int) (*Winsize, error) {
+	var ws Winsize
+	if err := ioctlPtr(fd, syscall.TIOCGWINSZ, unsafe.Pointer(&ws)); err != nil {
+		return nil, err
+	}
+	return &ws, nil
+}
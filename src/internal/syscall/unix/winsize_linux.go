@@ -0,0 +1,21 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// GetWinsize returns the size of the terminal fd refers to,
+// with the TIOCGWINSZ ioctl.
+func GetWinsize(fd int) (*Winsize, error) {
+	var ws Winsize
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return nil, errno
+	}
+	return &ws, nil
+}
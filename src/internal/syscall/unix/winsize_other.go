@@ -0,0 +1,15 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build (unix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd) || wasip1 || (js && wasm)
+
+package unix
+
+import "syscall"
+
+// GetWinsize returns the size of the terminal fd refers to.
+// It is not implemented on this system, and always returns ENOTSUP.
+func GetWinsize(fd int) (*Winsize, error) {
+	return nil, syscall.ENOTSUP
+}
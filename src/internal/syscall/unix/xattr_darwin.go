@@ -0,0 +1,161 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"internal/abi"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	XATTR_CREATE  = 0x2
+	XATTR_REPLACE = 0x4
+)
+
+func libc_getxattr_trampoline()
+
+//go:cgo_import_dynamic libc_getxattr getxattr "/usr/lib/libSystem.B.dylib"
+
+func Getxattr(path, attr string, dest []byte) (int, error) {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	a, err := syscall.BytePtrFromString(attr)
+	if err != nil {
+		return 0, err
+	}
+	r1, _, errno := syscall_syscall6(abi.FuncPCABI0(libc_getxattr_trampoline), uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(a)), uintptr(bufPtr(dest)), uintptr(len(dest)), 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(r1), nil
+}
+
+func libc_fgetxattr_trampoline()
+
+//go:cgo_import_dynamic libc_fgetxattr fgetxattr "/usr/lib/libSystem.B.dylib"
+
+func Fgetxattr(fd int, attr string, dest []byte) (int, error) {
+	a, err := syscall.BytePtrFromString(attr)
+	if err != nil {
+		return 0, err
+	}
+	r1, _, errno := syscall_syscall6(abi.FuncPCABI0(libc_fgetxattr_trampoline), uintptr(fd), uintptr(unsafe.Pointer(a)), uintptr(bufPtr(dest)), uintptr(len(dest)), 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(r1), nil
+}
+
+func libc_setxattr_trampoline()
+
+//go:cgo_import_dynamic libc_setxattr setxattr "/usr/lib/libSystem.B.dylib"
+
+func Setxattr(path, attr string, data []byte, flags int) error {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return err
+	}
+	a, err := syscall.BytePtrFromString(attr)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall_syscall6(abi.FuncPCABI0(libc_setxattr_trampoline), uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(a)), uintptr(bufPtr(data)), uintptr(len(data)), 0, uintptr(flags))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func libc_fsetxattr_trampoline()
+
+//go:cgo_import_dynamic libc_fsetxattr fsetxattr "/usr/lib/libSystem.B.dylib"
+
+func Fsetxattr(fd int, attr string, data []byte, flags int) error {
+	a, err := syscall.BytePtrFromString(attr)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall_syscall6(abi.FuncPCABI0(libc_fsetxattr_trampoline), uintptr(fd), uintptr(unsafe.Pointer(a)), uintptr(bufPtr(data)), uintptr(len(data)), 0, uintptr(flags))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func libc_listxattr_trampoline()
+
+//go:cgo_import_dynamic libc_listxattr listxattr "/usr/lib/libSystem.B.dylib"
+
+func Listxattr(path string, dest []byte) (int, error) {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	r1, _, errno := syscall_syscall6(abi.FuncPCABI0(libc_listxattr_trampoline), uintptr(unsafe.Pointer(p)), uintptr(bufPtr(dest)), uintptr(len(dest)), 0, 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(r1), nil
+}
+
+func libc_flistxattr_trampoline()
+
+//go:cgo_import_dynamic libc_flistxattr flistxattr "/usr/lib/libSystem.B.dylib"
+
+func Flistxattr(fd int, dest []byte) (int, error) {
+	r1, _, errno := syscall_syscall6(abi.FuncPCABI0(libc_flistxattr_trampoline), uintptr(fd), uintptr(bufPtr(dest)), uintptr(len(dest)), 0, 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(r1), nil
+}
+
+func libc_removexattr_trampoline()
+
+//go:cgo_import_dynamic libc_removexattr removexattr "/usr/lib/libSystem.B.dylib"
+
+func Removexattr(path, attr string) error {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return err
+	}
+	a, err := syscall.BytePtrFromString(attr)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall_syscall6(abi.FuncPCABI0(libc_removexattr_trampoline), uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(a)), 0, 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func libc_fremovexattr_trampoline()
+
+//go:cgo_import_dynamic libc_fremovexattr fremovexattr "/usr/lib/libSystem.B.dylib"
+
+func Fremovexattr(fd int, attr string) error {
+	a, err := syscall.BytePtrFromString(attr)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall_syscall6(abi.FuncPCABI0(libc_fremovexattr_trampoline), uintptr(fd), uintptr(unsafe.Pointer(a)), 0, 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// bufPtr returns a pointer to the first byte of b, or nil if b is empty,
+// so that a zero-length buffer asks the system for the required size.
+func bufPtr(b []byte) unsafe.Pointer {
+	if len(b) == 0 {
+		return nil
+	}
+	return unsafe.Pointer(&b[0])
+}
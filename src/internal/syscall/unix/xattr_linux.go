@@ -0,0 +1,84 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const (
+	XATTR_CREATE  = 0x1
+	XATTR_REPLACE = 0x2
+)
+
+func Getxattr(path, attr string, dest []byte) (int, error) {
+	return syscall.Getxattr(path, attr, dest)
+}
+
+func Setxattr(path, attr string, data []byte, flags int) error {
+	return syscall.Setxattr(path, attr, data, flags)
+}
+
+func Listxattr(path string, dest []byte) (int, error) {
+	return syscall.Listxattr(path, dest)
+}
+
+func Removexattr(path, attr string) error {
+	return syscall.Removexattr(path, attr)
+}
+
+func Fgetxattr(fd int, attr string, dest []byte) (int, error) {
+	p, err := syscall.BytePtrFromString(attr)
+	if err != nil {
+		return 0, err
+	}
+	r1, _, errno := syscall.Syscall6(syscall.SYS_FGETXATTR, uintptr(fd), uintptr(unsafe.Pointer(p)), uintptr(bufPtr(dest)), uintptr(len(dest)), 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(r1), nil
+}
+
+func Fsetxattr(fd int, attr string, data []byte, flags int) error {
+	p, err := syscall.BytePtrFromString(attr)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall.Syscall6(syscall.SYS_FSETXATTR, uintptr(fd), uintptr(unsafe.Pointer(p)), uintptr(bufPtr(data)), uintptr(len(data)), uintptr(flags), 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func Flistxattr(fd int, dest []byte) (int, error) {
+	r1, _, errno := syscall.Syscall(syscall.SYS_FLISTXATTR, uintptr(fd), uintptr(bufPtr(dest)), uintptr(len(dest)))
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(r1), nil
+}
+
+func Fremovexattr(fd int, attr string) error {
+	p, err := syscall.BytePtrFromString(attr)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_FREMOVEXATTR, uintptr(fd), uintptr(unsafe.Pointer(p)), 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// bufPtr returns a pointer to the first byte of b, or nil if b is empty,
+// so that a zero-length buffer asks the kernel for the required size.
+func bufPtr(b []byte) unsafe.Pointer {
+	if len(b) == 0 {
+		return nil
+	}
+	return unsafe.Pointer(&b[0])
+}
@@ -16,6 +16,7 @@ const (
 	IO_REPARSE_TAG_MOUNT_POINT = 0xA0000003
 	IO_REPARSE_TAG_DEDUP       = 0x80000013
 	IO_REPARSE_TAG_AF_UNIX     = 0x80000023
+	IO_REPARSE_TAG_APPEXECLINK = 0x8000001B
 
 	SYMLINK_FLAG_RELATIVE = 1
 )
@@ -92,3 +93,34 @@ func (rb *MountPointReparseBuffer) Path() string {
 	n2 := (rb.SubstituteNameOffset + rb.SubstituteNameLength) / 2
 	return syscall.UTF16ToString((*[0xffff]uint16)(unsafe.Pointer(&rb.PathBuffer[0]))[n1:n2:n2])
 }
+
+// AppExecLinkReparseBuffer is the undocumented layout of the reparse data
+// of an app execution alias, as found in %LOCALAPPDATA%\Microsoft\WindowsApps.
+type AppExecLinkReparseBuffer struct {
+	Version uint32
+	// StringList holds NUL-terminated strings: the package ID,
+	// the application user model ID and the target executable.
+	StringList [1]uint16
+}
+
+// Path returns the target executable stored in rb, given the
+// ReparseDataLength of the REPARSE_DATA_BUFFER containing it.
+// It returns "" if the data is malformed.
+func (rb *AppExecLinkReparseBuffer) Path(dataLength uint16) string {
+	if dataLength < 4 {
+		return ""
+	}
+	n := int(dataLength-4) / 2
+	list := unsafe.Slice(&rb.StringList[0], n)
+	for i := 0; i < 2; i++ {
+		j := 0
+		for j < len(list) && list[j] != 0 {
+			j++
+		}
+		if j == len(list) {
+			return ""
+		}
+		list = list[j+1:]
+	}
+	return syscall.UTF16ToString(list)
+}
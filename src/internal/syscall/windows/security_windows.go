@@ -60,6 +60,14 @@ func AdjustTokenPrivileges(token syscall.Token, disableAllPrivileges bool, newst
 //sys DuplicateTokenEx(hExistingToken syscall.Token, dwDesiredAccess uint32, lpTokenAttributes *syscall.SecurityAttributes, impersonationLevel uint32, tokenType TokenType, phNewToken *syscall.Token) (err error) = advapi32.DuplicateTokenEx
 //sys SetTokenInformation(tokenHandle syscall.Token, tokenInformationClass uint32, tokenInformation uintptr, tokenInformationLength uint32) (err error) = advapi32.SetTokenInformation
 
+// Flags for CreateRestrictedToken.
+const (
+	DISABLE_MAX_PRIVILEGE = 0x1
+)
+
+//sys CreateRestrictedToken(existingToken syscall.Token, flags uint32, disableSidCount uint32, sidsToDisable *SID_AND_ATTRIBUTES, deletePrivilegeCount uint32, privilegesToDelete *LUID_AND_ATTRIBUTES, restrictedSidCount uint32, sidsToRestrict *SID_AND_ATTRIBUTES, newTokenHandle *syscall.Token) (err error) = advapi32.CreateRestrictedToken
+//sys SetThreadToken(thread *syscall.Handle, token syscall.Token) (err error) = advapi32.SetThreadToken
+
 type SID_AND_ATTRIBUTES struct {
 	Sid        *syscall.SID
 	Attributes uint32
@@ -132,3 +140,101 @@ type UserInfo4 struct {
 //
 //go:linkname GetSystemDirectory
 func GetSystemDirectory() string // Implemented in runtime package.
+
+const (
+	SE_FILE_OBJECT = 1
+
+	OWNER_SECURITY_INFORMATION          = 0x00000001
+	GROUP_SECURITY_INFORMATION          = 0x00000002
+	DACL_SECURITY_INFORMATION           = 0x00000004
+	PROTECTED_DACL_SECURITY_INFORMATION = 0x80000000
+)
+
+// File access rights, from winnt.h.
+const (
+	FILE_READ_DATA        = 0x00000001
+	FILE_WRITE_DATA       = 0x00000002
+	FILE_APPEND_DATA      = 0x00000004
+	FILE_READ_EA          = 0x00000008
+	FILE_WRITE_EA         = 0x00000010
+	FILE_EXECUTE          = 0x00000020
+	FILE_READ_ATTRIBUTES  = 0x00000080
+	FILE_WRITE_ATTRIBUTES = 0x00000100
+	DELETE                = 0x00010000
+	READ_CONTROL          = 0x00020000
+	WRITE_DAC             = 0x00040000
+	WRITE_OWNER           = 0x00080000
+	SYNCHRONIZE           = 0x00100000
+
+	STANDARD_RIGHTS_READ    = READ_CONTROL
+	STANDARD_RIGHTS_WRITE   = READ_CONTROL
+	STANDARD_RIGHTS_EXECUTE = READ_CONTROL
+
+	FILE_GENERIC_READ    = STANDARD_RIGHTS_READ | FILE_READ_DATA | FILE_READ_ATTRIBUTES | FILE_READ_EA | SYNCHRONIZE
+	FILE_GENERIC_WRITE   = STANDARD_RIGHTS_WRITE | FILE_WRITE_DATA | FILE_WRITE_ATTRIBUTES | FILE_WRITE_EA | FILE_APPEND_DATA | SYNCHRONIZE
+	FILE_GENERIC_EXECUTE = STANDARD_RIGHTS_EXECUTE | FILE_READ_ATTRIBUTES | FILE_EXECUTE | SYNCHRONIZE
+)
+
+type ACL struct {
+	AclRevision byte
+	Sbz1        byte
+	AclSize     uint16
+	AceCount    uint16
+	Sbz2        uint16
+}
+
+type ACE_HEADER struct {
+	AceType  byte
+	AceFlags byte
+	AceSize  uint16
+}
+
+// ACCESS_ALLOWED_ACE is also the layout of an ACCESS_DENIED_ACE.
+type ACCESS_ALLOWED_ACE struct {
+	Header   ACE_HEADER
+	Mask     uint32
+	SidStart uint32
+}
+
+// Sid returns the SID that ace applies to.
+func (ace *ACCESS_ALLOWED_ACE) Sid() *syscall.SID {
+	return (*syscall.SID)(unsafe.Pointer(&ace.SidStart))
+}
+
+const (
+	ACCESS_ALLOWED_ACE_TYPE = 0
+	ACCESS_DENIED_ACE_TYPE  = 1
+
+	INHERIT_ONLY_ACE = 0x08
+)
+
+type TRUSTEE struct {
+	MultipleTrustee          *TRUSTEE
+	MultipleTrusteeOperation uint32
+	TrusteeForm              uint32
+	TrusteeType              uint32
+	TrusteeValue             uintptr
+}
+
+type EXPLICIT_ACCESS struct {
+	AccessPermissions uint32
+	AccessMode        uint32
+	Inheritance       uint32
+	Trustee           TRUSTEE
+}
+
+const (
+	TRUSTEE_IS_SID     = 0
+	TRUSTEE_IS_UNKNOWN = 0
+
+	GRANT_ACCESS = 1
+	DENY_ACCESS  = 3
+
+	NO_INHERITANCE = 0
+)
+
+//sys	GetNamedSecurityInfo(objectName *uint16, objectType uint32, securityInformation uint32, owner **syscall.SID, group **syscall.SID, dacl **ACL, sacl **ACL, sd *syscall.Handle) (ret error) = advapi32.GetNamedSecurityInfoW
+//sys	SetNamedSecurityInfo(objectName *uint16, objectType uint32, securityInformation uint32, owner *syscall.SID, group *syscall.SID, dacl *ACL, sacl *ACL) (ret error) = advapi32.SetNamedSecurityInfoW
+//sys	SetEntriesInAcl(countExplicitEntries uint32, explicitEntries *EXPLICIT_ACCESS, oldACL *ACL, newACL **ACL) (ret error) = advapi32.SetEntriesInAclW
+//sys	GetAce(acl *ACL, aceIndex uint32, ace **ACCESS_ALLOWED_ACE) (err error) = advapi32.GetAce
+//sys	EqualSid(sid1 *syscall.SID, sid2 *syscall.SID) (isEqual bool) = advapi32.EqualSid
@@ -0,0 +1,72 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fs_test
+
+import (
+	"errors"
+	. "io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+// linkMapFS is a MapFS in which entries with ModeSymlink
+// are symbolic links whose Data is the link target.
+type linkMapFS struct {
+	fstest.MapFS
+}
+
+func (fsys linkMapFS) ReadLink(name string) (string, error) {
+	f, ok := fsys.MapFS[name]
+	if !ok || f.Mode&ModeSymlink == 0 {
+		return "", &PathError{Op: "readlink", Path: name, Err: ErrInvalid}
+	}
+	return string(f.Data), nil
+}
+
+func (fsys linkMapFS) Lstat(name string) (FileInfo, error) {
+	return Stat(fsys.MapFS, name)
+}
+
+func TestReadLink(t *testing.T) {
+	fsys := linkMapFS{fstest.MapFS{
+		"file": {Data: []byte("data")},
+		"link": {Data: []byte("file"), Mode: ModeSymlink},
+	}}
+	if target, err := ReadLink(fsys, "link"); err != nil || target != "file" {
+		t.Errorf("ReadLink(link) = %q, %v; want %q, nil", target, err, "file")
+	}
+	if _, err := ReadLink(fsys, "file"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("ReadLink(file): got %v, want ErrInvalid", err)
+	}
+
+	// A file system without ReadLink has no symbolic links to read.
+	var pe *PathError
+	if _, err := ReadLink(fsys.MapFS, "link"); !errors.As(err, &pe) || pe.Op != "readlink" || pe.Err != ErrInvalid {
+		t.Errorf("ReadLink on MapFS: got %v, want readlink PathError with ErrInvalid", err)
+	}
+}
+
+func TestLstat(t *testing.T) {
+	fsys := linkMapFS{fstest.MapFS{
+		"file": {Data: []byte("data")},
+		"link": {Data: []byte("file"), Mode: ModeSymlink},
+	}}
+	info, err := Lstat(fsys, "link")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&ModeSymlink == 0 {
+		t.Errorf("Lstat(link).Mode() = %v; want a symlink", info.Mode())
+	}
+
+	// Without ReadLinkFS, Lstat falls back to Stat.
+	info, err = Lstat(testFsys, "hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name() != "hello.txt" || info.Mode() != 0456 {
+		t.Errorf("Lstat(hello.txt) = %s, %v; want hello.txt, %v", info.Name(), info.Mode(), FileMode(0456))
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+
+package os
+
+func chmodACL(name string, mode FileMode) error {
+	return chmod(name, mode)
+}
+
+func statACL(name string) (FileInfo, error) {
+	return Stat(name)
+}
@@ -0,0 +1,217 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/windows"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	// aclAnyoneRights are granted to every class, so that anyone
+	// allowed to see the file can read its attributes and ACL.
+	aclAnyoneRights = windows.READ_CONTROL | windows.FILE_READ_ATTRIBUTES | windows.SYNCHRONIZE
+
+	// aclOwnerRights are granted to the owner whatever the mode, so
+	// that it can change the mode back and delete the file.
+	aclOwnerRights = aclAnyoneRights | windows.FILE_WRITE_ATTRIBUTES |
+		windows.DELETE | windows.WRITE_DAC | windows.WRITE_OWNER
+)
+
+// everyoneSID returns the SID of the Everyone group.
+func everyoneSID() (*syscall.SID, error) {
+	return syscall.StringToSid("S-1-1-0")
+}
+
+// aclAccess returns the rights to grant for the rwx bits of one class
+// of permission bits, and the narrower rights to deny for them, which
+// exclude the rights shared with the other bits.
+func aclAccess(rwx FileMode) (grant, deny uint32) {
+	if rwx&4 != 0 {
+		grant |= windows.FILE_GENERIC_READ
+		deny |= windows.FILE_READ_DATA | windows.FILE_READ_EA
+	}
+	if rwx&2 != 0 {
+		grant |= windows.FILE_GENERIC_WRITE
+		deny |= windows.FILE_WRITE_DATA | windows.FILE_APPEND_DATA | windows.FILE_WRITE_EA
+	}
+	if rwx&1 != 0 {
+		grant |= windows.FILE_GENERIC_EXECUTE
+		deny |= windows.FILE_EXECUTE
+	}
+	return grant, deny
+}
+
+// aclMode returns the rwx bits for the access rights in mask.
+func aclMode(mask uint32) FileMode {
+	if mask&syscall.GENERIC_ALL != 0 {
+		return 7
+	}
+	var rwx FileMode
+	if mask&(windows.FILE_READ_DATA|syscall.GENERIC_READ) != 0 {
+		rwx |= 4
+	}
+	if mask&(windows.FILE_WRITE_DATA|syscall.GENERIC_WRITE) != 0 {
+		rwx |= 2
+	}
+	if mask&(windows.FILE_EXECUTE|syscall.GENERIC_EXECUTE) != 0 {
+		rwx |= 1
+	}
+	return rwx
+}
+
+// fileSecurity returns the owner, primary group and, if withDACL is
+// set, the DACL of the named file. The returned handle must be freed
+// with syscall.LocalFree once they are no longer used.
+func fileSecurity(name string, withDACL bool) (owner, group *syscall.SID, dacl *windows.ACL, sd syscall.Handle, err error) {
+	p, err := syscall.UTF16PtrFromString(fixLongPath(name))
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+	info := uint32(windows.OWNER_SECURITY_INFORMATION | windows.GROUP_SECURITY_INFORMATION)
+	pdacl := (**windows.ACL)(nil)
+	if withDACL {
+		info |= windows.DACL_SECURITY_INFORMATION
+		pdacl = &dacl
+	}
+	err = windows.GetNamedSecurityInfo(p, windows.SE_FILE_OBJECT, info, &owner, &group, pdacl, nil, &sd)
+	return owner, group, dacl, sd, err
+}
+
+func chmodACL(name string, mode FileMode) error {
+	// Set or clear the read-only attribute as Chmod does.
+	if err := chmod(name, mode); err != nil {
+		return err
+	}
+
+	owner, group, _, sd, err := fileSecurity(name, false)
+	if err != nil {
+		return &PathError{Op: "chmod", Path: name, Err: err}
+	}
+	defer syscall.LocalFree(sd)
+	everyone, err := everyoneSID()
+	if err != nil {
+		return &PathError{Op: "chmod", Path: name, Err: err}
+	}
+
+	perm := mode.Perm()
+	u, g, o := perm>>6&7, perm>>3&7, perm&7
+	sameGroup := group == nil || windows.EqualSid(owner, group)
+
+	var entries []windows.EXPLICIT_ACCESS
+	add := func(sid *syscall.SID, rights, access uint32) {
+		if rights == 0 {
+			return
+		}
+		entries = append(entries, windows.EXPLICIT_ACCESS{
+			AccessPermissions: rights,
+			AccessMode:        access,
+			Inheritance:       windows.NO_INHERITANCE,
+			Trustee: windows.TRUSTEE{
+				TrusteeForm:  windows.TRUSTEE_IS_SID,
+				TrusteeType:  windows.TRUSTEE_IS_UNKNOWN,
+				TrusteeValue: uintptr(unsafe.Pointer(sid)),
+			},
+		})
+	}
+	grantU, _ := aclAccess(u)
+	grantG, _ := aclAccess(g)
+	grantO, _ := aclAccess(o)
+	if sameGroup {
+		_, denyU := aclAccess(o &^ u)
+		add(owner, denyU, windows.DENY_ACCESS)
+	} else {
+		_, denyU := aclAccess((g | o) &^ u)
+		_, denyG := aclAccess(o &^ g)
+		add(owner, denyU, windows.DENY_ACCESS)
+		add(group, denyG, windows.DENY_ACCESS)
+	}
+	add(owner, grantU|aclOwnerRights, windows.GRANT_ACCESS)
+	if !sameGroup {
+		add(group, grantG|aclAnyoneRights, windows.GRANT_ACCESS)
+	}
+	add(everyone, grantO|aclAnyoneRights, windows.GRANT_ACCESS)
+
+	var acl *windows.ACL
+	if err := windows.SetEntriesInAcl(uint32(len(entries)), &entries[0], nil, &acl); err != nil {
+		return &PathError{Op: "chmod", Path: name, Err: err}
+	}
+	defer syscall.LocalFree(syscall.Handle(unsafe.Pointer(acl)))
+	runtime.KeepAlive(everyone)
+
+	p, err := syscall.UTF16PtrFromString(fixLongPath(name))
+	if err != nil {
+		return &PathError{Op: "chmod", Path: name, Err: err}
+	}
+	err = windows.SetNamedSecurityInfo(p, windows.SE_FILE_OBJECT,
+		windows.DACL_SECURITY_INFORMATION|windows.PROTECTED_DACL_SECURITY_INFORMATION,
+		nil, nil, acl, nil)
+	if err != nil {
+		return &PathError{Op: "chmod", Path: name, Err: err}
+	}
+	return nil
+}
+
+func statACL(name string) (FileInfo, error) {
+	fi, err := Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	owner, group, dacl, sd, err := fileSecurity(name, true)
+	if err != nil {
+		return nil, &PathError{Op: "stat", Path: name, Err: err}
+	}
+	defer syscall.LocalFree(sd)
+	if dacl == nil {
+		// No access control on this file system.
+		return fi, nil
+	}
+	everyone, err := everyoneSID()
+	if err != nil {
+		return nil, &PathError{Op: "stat", Path: name, Err: err}
+	}
+
+	// Evaluate the entries in order, as Windows does: an entry only
+	// grants or denies the rights not decided by an earlier entry.
+	classes := [3]*syscall.SID{owner, group, nil}
+	var allowed, denied [3]uint32
+	for i := range uint32(dacl.AceCount) {
+		var ace *windows.ACCESS_ALLOWED_ACE
+		if err := windows.GetAce(dacl, i, &ace); err != nil {
+			return nil, &PathError{Op: "stat", Path: name, Err: err}
+		}
+		if ace.Header.AceFlags&windows.INHERIT_ONLY_ACE != 0 {
+			continue
+		}
+		var rights *[3]uint32
+		switch ace.Header.AceType {
+		case windows.ACCESS_ALLOWED_ACE_TYPE:
+			rights = &allowed
+		case windows.ACCESS_DENIED_ACE_TYPE:
+			rights = &denied
+		default:
+			continue
+		}
+		sid := ace.Sid()
+		isEveryone := windows.EqualSid(sid, everyone)
+		for c, csid := range classes {
+			if isEveryone || csid != nil && windows.EqualSid(sid, csid) {
+				rights[c] |= ace.Mask &^ (allowed[c] | denied[c])
+			}
+		}
+	}
+	runtime.KeepAlive(everyone)
+
+	fs := fi.(*fileStat)
+	perm := aclMode(allowed[0])<<6 | aclMode(allowed[1])<<3 | aclMode(allowed[2])
+	if fs.FileAttributes&syscall.FILE_ATTRIBUTE_READONLY != 0 {
+		perm &^= 0o222
+	}
+	fs.aclPerm = perm
+	fs.hasACLPerm = true
+	return fs, nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+)
+
+func (f *File) allocate(offset, length int64) error {
+	var st syscall.Stat_t
+	if err := f.pfd.Fstat(&st); err != nil {
+		return err
+	}
+	end := offset + length
+	// F_PEOFPOSMODE allocates from the end of the storage already
+	// allocated to the file, which may be past its logical end.
+	if allocated := st.Blocks * 512; end > allocated {
+		store := &syscall.Fstore_t{
+			Flags:   syscall.F_ALLOCATECONTIG | syscall.F_ALLOCATEALL,
+			Posmode: syscall.F_PEOFPOSMODE,
+			Length:  end - allocated,
+		}
+		err := ignoringEINTR(func() error {
+			return f.preallocate(store)
+		})
+		if err != nil {
+			// Contiguous space may not be available;
+			// settle for any space.
+			store.Flags = syscall.F_ALLOCATEALL
+			err = ignoringEINTR(func() error {
+				return f.preallocate(store)
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// F_PREALLOCATE never changes the size of the file.
+	if end > st.Size {
+		return f.pfd.Ftruncate(end)
+	}
+	return nil
+}
+
+func (f *File) preallocate(store *syscall.Fstore_t) error {
+	var err error
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		err = unix.Preallocate(int(fd), store)
+	})
+	if cerr != nil {
+		return cerr
+	}
+	return err
+}
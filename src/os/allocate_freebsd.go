@@ -0,0 +1,24 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+)
+
+func (f *File) allocate(offset, length int64) error {
+	var err error
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		err = ignoringEINTR(func() error {
+			return unix.PosixFallocate(int(fd), offset, length)
+		})
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		return cerr
+	}
+	return err
+}
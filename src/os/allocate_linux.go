@@ -0,0 +1,24 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"runtime"
+	"syscall"
+)
+
+func (f *File) allocate(offset, length int64) error {
+	var err error
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		err = ignoringEINTR(func() error {
+			return syscall.Fallocate(int(fd), 0, offset, length)
+		})
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		return cerr
+	}
+	return err
+}
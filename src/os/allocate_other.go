@@ -0,0 +1,13 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !darwin && !freebsd && !linux && !windows
+
+package os
+
+import "errors"
+
+func (f *File) allocate(offset, length int64) error {
+	return errors.ErrUnsupported
+}
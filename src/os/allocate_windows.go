@@ -0,0 +1,21 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "syscall"
+
+func (f *File) allocate(offset, length int64) error {
+	var d syscall.ByHandleFileInformation
+	if err := f.pfd.GetFileInformationByHandle(&d); err != nil {
+		return err
+	}
+	size := int64(d.FileSizeHigh)<<32 | int64(d.FileSizeLow)
+	// Extending the end of the file makes NTFS allocate the new
+	// clusters, without writing or zeroing them.
+	if end := offset + length; end > size {
+		return f.pfd.Ftruncate(end)
+	}
+	return nil
+}
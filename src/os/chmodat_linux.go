@@ -0,0 +1,45 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/itoa"
+	"internal/syscall/unix"
+	"syscall"
+)
+
+// chmodAtNofollow changes the mode of name relative to dirfd without
+// following a symbolic link, for kernels before 6.6, whose fchmodat
+// does not take AT_SYMLINK_NOFOLLOW. Like the C library, it opens name
+// with O_PATH|O_NOFOLLOW and changes the mode through /proc/self/fd,
+// so the file changed is the one checked, whatever is at name by then.
+func chmodAtNofollow(dirfd int, name string, mode uint32) error {
+	var fd int
+	err := ignoringEINTR(func() (err error) {
+		fd, err = unix.Openat(dirfd, name, unix.O_PATH|syscall.O_NOFOLLOW|syscall.O_CLOEXEC, 0)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(fd)
+
+	var st syscall.Stat_t
+	if err := syscall.Fstat(fd, &st); err != nil {
+		return err
+	}
+	if st.Mode&syscall.S_IFMT == syscall.S_IFLNK {
+		// Linux does not support changing the mode of a link.
+		return syscall.EOPNOTSUPP
+	}
+	err = ignoringEINTR(func() error {
+		return syscall.Chmod("/proc/self/fd/"+itoa.Itoa(fd), mode)
+	})
+	if err == syscall.ENOENT {
+		// /proc is not mounted.
+		return syscall.EOPNOTSUPP
+	}
+	return err
+}
@@ -0,0 +1,46 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	. "os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// TestChmodAtNofollow tests the fallback ChmodAt uses on kernels
+// without fchmodat2, which the test kernel may well not need.
+func TestChmodAtNofollow(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := WriteFile(target, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Symlink("target", filepath.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+	d, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	if err := ChmodAtNofollow(int(d.Fd()), "target", 0o640); err != nil {
+		t.Fatalf("chmodAtNofollow(target): %v", err)
+	}
+	if err := ChmodAtNofollow(int(d.Fd()), "link", 0o600); err != syscall.EOPNOTSUPP {
+		t.Errorf("chmodAtNofollow(link) = %v, want EOPNOTSUPP", err)
+	}
+	fi, err := Stat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fi.Mode().Perm(); got != 0o640 {
+		t.Errorf("mode of target = %v, want %v", got, FileMode(0o640))
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix && !linux
+
+package os
+
+import "syscall"
+
+// chmodAtNofollow is only needed on Linux, where fchmodat may reject
+// AT_SYMLINK_NOFOLLOW.
+func chmodAtNofollow(dirfd int, name string, mode uint32) error {
+	return syscall.EOPNOTSUPP
+}
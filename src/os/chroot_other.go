@@ -0,0 +1,13 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !unix
+
+package os
+
+import "errors"
+
+func withChroot(dir string, fn func() error) error {
+	return &PathError{Op: "chroot", Path: dir, Err: errors.ErrUnsupported}
+}
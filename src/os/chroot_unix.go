@@ -0,0 +1,64 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package os
+
+import (
+	"sync"
+	"syscall"
+)
+
+// chrootMu is held while WithChroot runs.
+var chrootMu sync.Mutex
+
+func withChroot(dir string, fn func() error) (err error) {
+	chrootMu.Lock()
+	defer chrootMu.Unlock()
+
+	// Keep the current root and working directory open,
+	// so that we can return to them from inside dir.
+	root, err := openDirNolog("/")
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+	wd, err := openDirNolog(".")
+	if err != nil {
+		return err
+	}
+	defer wd.Close()
+
+	if err := ignoringEINTR(func() error {
+		return syscall.Chroot(dir)
+	}); err != nil {
+		return &PathError{Op: "chroot", Path: dir, Err: err}
+	}
+	clearGetwdCache()
+	defer func() {
+		if rerr := restoreRoot(root, wd); rerr != nil && err == nil {
+			err = rerr
+		}
+	}()
+	if err := syscall.Chdir("/"); err != nil {
+		return &PathError{Op: "chdir", Path: dir, Err: err}
+	}
+	return fn()
+}
+
+// restoreRoot makes root the root directory and wd
+// the working directory again.
+func restoreRoot(root, wd *File) error {
+	defer clearGetwdCache()
+	if err := root.Chdir(); err != nil {
+		return err
+	}
+	if err := ignoringEINTR(func() error {
+		return syscall.Chroot(".")
+	}); err != nil {
+		return &PathError{Op: "chroot", Path: root.name, Err: err}
+	}
+	return wd.Chdir()
+}
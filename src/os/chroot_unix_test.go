@@ -0,0 +1,51 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package os_test
+
+import (
+	. "os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithChroot(t *testing.T) {
+	// Not parallel: the root directory is shared by the whole process.
+	dir := t.TempDir()
+	if err := WriteFile(filepath.Join(dir, "marker"), []byte("inside"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = WithChroot(dir, func() error {
+		if b, err := ReadFile("/marker"); err != nil || string(b) != "inside" {
+			t.Errorf(`ReadFile("/marker") inside new root = %q, %v; want "inside", nil`, b, err)
+		}
+		if _, err := Stat(dir); !IsNotExist(err) {
+			t.Errorf("Stat(%q) inside new root = %v, want not-exist error", dir, err)
+		}
+		if got, err := Getwd(); err != nil || got != "/" {
+			t.Errorf(`Getwd inside new root = %q, %v; want "/", nil`, got, err)
+		}
+		return nil
+	})
+	if IsPermission(err) {
+		t.Skipf("skipping: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("WithChroot: %v", err)
+	}
+
+	if _, err := Stat(filepath.Join(dir, "marker")); err != nil {
+		t.Errorf("Stat of marker after WithChroot: %v", err)
+	}
+	if got, err := Getwd(); err != nil || got != wd {
+		t.Errorf("Getwd after WithChroot = %q, %v; want %q, nil", got, err, wd)
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+func setBirthtime(name string, btime time.Time) error {
+	attrs := unix.Attrlist{
+		Bitmapcount: unix.ATTR_BIT_MAP_COUNT,
+		Commonattr:  unix.ATTR_CMN_CRTIME,
+	}
+	ts := syscall.NsecToTimespec(btime.UnixNano())
+	err := ignoringEINTR(func() error {
+		return unix.Setattrlist(name, &attrs, unsafe.Pointer(&ts), unsafe.Sizeof(ts), 0)
+	})
+	if err == syscall.ENOTSUP {
+		// The file system does not record birth times.
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,28 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"syscall"
+	"time"
+)
+
+func setBirthtime(name string, btime time.Time) error {
+	pathp, err := syscall.UTF16PtrFromString(fixLongPath(name))
+	if err != nil {
+		return err
+	}
+	// Share every kind of access, so that the file being open elsewhere,
+	// or renamed or deleted meanwhile, does not make this open fail.
+	h, err := syscall.CreateFile(pathp,
+		syscall.FILE_WRITE_ATTRIBUTES, syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE, nil,
+		syscall.OPEN_EXISTING, syscall.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(h)
+	c := syscall.NsecToFiletime(btime.UnixNano())
+	return syscall.SetFileTime(h, &c, nil, nil)
+}
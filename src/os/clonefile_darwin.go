@@ -0,0 +1,57 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/filepathlite"
+	"internal/syscall/unix"
+	"runtime"
+)
+
+// cloneFile tries to create dst as a copy-on-write clone of src, which
+// takes constant time on file systems that support it, such as APFS.
+// It reports whether it handled the copy; if not, which includes the
+// cases where src and dst are on different or unsuitable file systems,
+// the caller should copy the data itself.
+func cloneFile(dst string, src *File, mode FileMode) (bool, error) {
+	// fclonefileat cannot replace an existing file, so clone to a
+	// temporary name next to dst and rename it into place.
+	// Anything but a regular file at dst is left to the copy.
+	name := dst
+	if fi, err := Lstat(dst); err == nil {
+		if !fi.Mode().IsRegular() {
+			return false, nil
+		}
+		name = joinPath(filepathlite.Dir(dst), "."+filepathlite.Base(dst)+".clone"+nextRandom())
+	} else if !IsNotExist(err) {
+		return false, nil
+	}
+
+	var e error
+	cerr := src.pfd.RawControl(func(fd uintptr) {
+		e = ignoringEINTR(func() error {
+			// The clone belongs to the caller, as a new file would.
+			return unix.Fclonefileat(int(fd), unix.AT_FDCWD, name, unix.CLONE_NOOWNERCOPY)
+		})
+	})
+	runtime.KeepAlive(src)
+	if cerr != nil || e != nil {
+		// Typically ENOTSUP or EXDEV. Let the copy report any
+		// error that is not about cloning.
+		return false, nil
+	}
+
+	if err := Chmod(name, mode); err != nil {
+		Remove(name)
+		return true, err
+	}
+	if name != dst {
+		if err := Rename(name, dst); err != nil {
+			Remove(name)
+			return true, err
+		}
+	}
+	return true, nil
+}
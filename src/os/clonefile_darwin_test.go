@@ -0,0 +1,82 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	"bytes"
+	. "os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCopyFileClone(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	data := bytes.Repeat([]byte("clonefile\n"), 4<<20) // 40 MB
+	if err := WriteFile(src, data, 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(name string) {
+		t.Helper()
+		got, err := ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("%s does not match src", name)
+		}
+		fi, err := Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode().Perm() != 0o640 {
+			t.Errorf("%s has mode %v, want %v", name, fi.Mode().Perm(), FileMode(0o640))
+		}
+	}
+
+	// A new destination.
+	dst := filepath.Join(dir, "dst")
+	start := time.Now()
+	if err := CopyFile(dst, src); err != nil {
+		t.Fatal(err)
+	}
+	elapsed := time.Since(start)
+	check(dst)
+	// A clone takes the same short time regardless of the file size,
+	// but only on APFS; byte copying is used elsewhere, so only log.
+	t.Logf("copied %d bytes in %v", len(data), elapsed)
+
+	// An existing destination is replaced.
+	if err := WriteFile(dst, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := CopyFile(dst, src); err != nil {
+		t.Fatal(err)
+	}
+	check(dst)
+
+	// The clone is independent of src.
+	if err := WriteFile(src, []byte("changed"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	check(dst)
+
+	// No temporary files are left behind.
+	entries, err := ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("directory contains %q, want only src and dst", names)
+	}
+}
@@ -62,6 +62,16 @@ func TestLargeCopyViaNetwork(t *testing.T) {
 	}()
 	wg.Wait()
 
+	if runtime.GOOS == "linux" {
+		// Both directions have a kernel fast path on Linux.
+		if got := src.CopyStats(); got.Method != "sendfile" || got.Bytes != size {
+			t.Errorf("src.CopyStats() = %+v, want sendfile of %d bytes", got, size)
+		}
+		if got := dst.CopyStats(); got.Method != "splice" || got.Bytes != size {
+			t.Errorf("dst.CopyStats() = %+v, want splice of %d bytes", got, size)
+		}
+	}
+
 	if _, err := dst.Seek(0, 0); err != nil {
 		t.Fatal(err)
 	}
@@ -70,6 +80,39 @@ func TestLargeCopyViaNetwork(t *testing.T) {
 	}
 }
 
+func TestCopyStatsGeneric(t *testing.T) {
+	f, err := os.Create(t.TempDir() + "/f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if got := f.CopyStats(); got != (os.CopyStats{}) {
+		t.Errorf("CopyStats before any copy = %+v, want zero value", got)
+	}
+
+	// A plain io.Reader offers no fast path, so ReadFrom must fall
+	// back to the user space loop.
+	const data = "hello, world"
+	if _, err := f.ReadFrom(struct{ io.Reader }{bytes.NewReader([]byte(data))}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := f.CopyStats(), (os.CopyStats{Method: "generic", Bytes: int64(len(data))}); got != want {
+		t.Errorf("CopyStats after ReadFrom = %+v, want %+v", got, want)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, f); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := f.CopyStats(), (os.CopyStats{Method: "generic", Bytes: int64(len(data))}); got != want {
+		t.Errorf("CopyStats after WriteTo = %+v, want %+v", got, want)
+	}
+}
+
 func compareReaders(a, b io.Reader) error {
 	bufa := make([]byte, 4096)
 	bufb := make([]byte, 4096)
@@ -9,7 +9,9 @@ import (
 	"internal/filepathlite"
 	"io"
 	"io/fs"
+	"iter"
 	"slices"
+	"sync"
 )
 
 type readdirMode int
@@ -17,6 +19,7 @@ type readdirMode int
 const (
 	readdirName readdirMode = iota
 	readdirDirEntry
+	readdirDirEntryTyped // like readdirDirEntry, but never calls Lstat
 	readdirFileInfo
 )
 
@@ -41,7 +44,7 @@ func (f *File) Readdir(n int) ([]FileInfo, error) {
 	if f == nil {
 		return nil, ErrInvalid
 	}
-	_, _, infos, err := f.readdir(n, readdirFileInfo)
+	_, _, infos, err := f.readdir(n, readdirFileInfo, nil)
 	if infos == nil {
 		// Readdir has historically always returned a non-nil empty slice, never nil,
 		// even on error (except misuse with nil receiver above).
@@ -70,7 +73,7 @@ func (f *File) Readdirnames(n int) (names []string, err error) {
 	if f == nil {
 		return nil, ErrInvalid
 	}
-	names, _, _, err = f.readdir(n, readdirName)
+	names, _, _, err = f.readdir(n, readdirName, nil)
 	if names == nil {
 		// Readdirnames has historically always returned a non-nil empty slice, never nil,
 		// even on error (except misuse with nil receiver above).
@@ -98,7 +101,7 @@ func (f *File) ReadDir(n int) ([]DirEntry, error) {
 	if f == nil {
 		return nil, ErrInvalid
 	}
-	_, dirents, _, err := f.readdir(n, readdirDirEntry)
+	_, dirents, _, err := f.readdir(n, readdirDirEntry, nil)
 	if dirents == nil {
 		// Match Readdir and Readdirnames: don't return nil slices.
 		dirents = []DirEntry{}
@@ -106,6 +109,102 @@ func (f *File) ReadDir(n int) ([]DirEntry, error) {
 	return dirents, err
 }
 
+// ReadDirInto is like [File.ReadDir], but stores the DirEntry records
+// in dst instead of allocating a new slice, and returns the number of
+// records stored. Reusing dst across calls avoids allocating a slice
+// for each batch when reading a large directory.
+//
+// ReadDirInto stores at most n records, or at most len(dst) records
+// if n <= 0 or n > len(dst). At the end of a directory, it returns 0
+// and [io.EOF]. If len(dst) is 0, ReadDirInto returns 0 and a nil error.
+//
+// The DirEntry values stored in dst do not refer to any state shared
+// with f, so they remain valid after the next call on f, and may be
+// retained after the elements of dst are overwritten.
+func (f *File) ReadDirInto(dst []DirEntry, n int) (int, error) {
+	if f == nil {
+		return 0, ErrInvalid
+	}
+	if n <= 0 || n > len(dst) {
+		n = len(dst)
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	_, dirents, _, err := f.readdir(n, readdirDirEntry, dst[:0:n])
+	return len(dirents), err
+}
+
+// sortedDir holds the directory entries buffered by [File.ReadDirSorted].
+type sortedDir struct {
+	mu      sync.Mutex
+	filled  bool       // whether entries has been read from the directory
+	entries []DirEntry // entries not yet returned, sorted by name
+	err     error      // error that stopped the read, returned with the last entries
+}
+
+// ReadDirSorted is like [File.ReadDir], but returns the DirEntry records
+// sorted by filename, so that successive calls with n > 0 yield the
+// remaining entries of the directory in globally sorted order.
+//
+// To guarantee that order, the first call reads all the entries
+// remaining in the directory and buffers them in f until they have been
+// returned, so memory use grows with the size of the directory rather
+// than with n. Entries already returned by [File.ReadDir] or similar
+// methods are not included. Calling those methods after ReadDirSorted
+// does not return the buffered entries. Seeking f discards the buffer.
+//
+// If n > 0, ReadDirSorted returns at most n DirEntry records.
+// In this case, if ReadDirSorted returns an empty slice, it will return
+// an error explaining why. At the end of a directory, the error is [io.EOF].
+//
+// If n <= 0, ReadDirSorted returns all the DirEntry records remaining in
+// the directory. When it succeeds, it returns a nil error (not io.EOF).
+func (f *File) ReadDirSorted(n int) ([]DirEntry, error) {
+	if f == nil {
+		return nil, ErrInvalid
+	}
+	e := f.getExtra()
+	s := e.sorted.Load()
+	if s == nil {
+		s = new(sortedDir)
+		if !e.sorted.CompareAndSwap(nil, s) {
+			s = e.sorted.Load()
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.filled {
+		_, s.entries, _, s.err = f.readdir(-1, readdirDirEntry, nil)
+		slices.SortFunc(s.entries, func(a, b DirEntry) int {
+			return bytealg.CompareString(a.Name(), b.Name())
+		})
+		s.filled = true
+	}
+
+	m := len(s.entries)
+	if n > 0 && n < m {
+		m = n
+	}
+	dirents := s.entries[:m:m]
+	s.entries = s.entries[m:]
+	var err error
+	if len(s.entries) == 0 {
+		// Report the error that stopped the read once,
+		// along with the last entries read before it.
+		err, s.err = s.err, nil
+		s.entries = nil
+	}
+	if n > 0 && len(dirents) == 0 && err == nil {
+		err = io.EOF
+	}
+	if dirents == nil {
+		// Match ReadDir: don't return nil slices.
+		dirents = []DirEntry{}
+	}
+	return dirents, err
+}
+
 // testingForceReadDirLstat forces ReadDir to call Lstat, for testing that code path.
 // This can be difficult to provoke on some Unix systems otherwise.
 var testingForceReadDirLstat bool
@@ -129,6 +228,113 @@ func ReadDir(name string) ([]DirEntry, error) {
 	return dirs, err
 }
 
+// ReadDirTyped is like [ReadDir], but never examines the entries
+// themselves to determine their types. [ReadDir] does so, by calling
+// [Lstat] on each entry whose type the directory does not record, such
+// as when the file system reports DT_UNKNOWN on Unix systems. Instead, the
+// Type method of such an entry reports [ModeIrregular], and only the
+// Info method tells what kind of file it is. Every other entry reports
+// its type without any per-entry system call.
+func ReadDirTyped(name string) ([]DirEntry, error) {
+	f, err := openDir(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	_, dirs, _, err := f.readdir(-1, readdirDirEntryTyped, nil)
+	if dirs == nil {
+		dirs = []DirEntry{}
+	}
+	slices.SortFunc(dirs, func(a, b DirEntry) int {
+		return bytealg.CompareString(a.Name(), b.Name())
+	})
+	return dirs, err
+}
+
+// readDirSeqBatch is the number of entries ReadDirSeq requests
+// from the directory at a time.
+const readDirSeqBatch = 256
+
+// ReadDirSeq returns an iterator over the entries of the named directory,
+// in directory order. Unlike [ReadDir], it does not read the whole
+// directory up front: entries are read in batches as the iteration
+// proceeds, so stopping early avoids reading the rest of the directory.
+//
+// The directory is opened when iteration starts and closed when it
+// ends, including when the loop body breaks out early.
+// If an error occurs, it is yielded with a nil DirEntry,
+// after which the iteration stops.
+func ReadDirSeq(name string) iter.Seq2[DirEntry, error] {
+	return func(yield func(DirEntry, error) bool) {
+		f, err := openDir(name)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer f.Close()
+
+		for {
+			dirs, err := f.ReadDir(readDirSeqBatch)
+			for _, d := range dirs {
+				if !yield(d, nil) {
+					return
+				}
+			}
+			if err != nil {
+				if err != io.EOF {
+					yield(nil, err)
+				}
+				return
+			}
+		}
+	}
+}
+
+// CountDirEntries returns the number of entries in the named directory,
+// not counting "." and "..". It reads the directory in batches into a
+// reused buffer, rather than building a slice of all its entries as
+// len(ReadDir(name)) would. If an error occurs reading the directory,
+// CountDirEntries returns the number of entries read before the error,
+// along with the error.
+func CountDirEntries(name string) (int, error) {
+	f, err := openDir(name)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	buf := make([]DirEntry, readDirSeqBatch)
+	count := 0
+	for {
+		n, err := f.ReadDirInto(buf, 0)
+		count += n
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+	}
+}
+
+// IsDirEmpty reports whether the named directory has no entries other
+// than "." and "..". It stops reading the directory at the first entry.
+func IsDirEmpty(name string) (bool, error) {
+	f, err := openDir(name)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	var buf [1]DirEntry
+	_, err = f.ReadDirInto(buf[:], 1)
+	if err == io.EOF {
+		return true, nil
+	}
+	return false, err
+}
+
 // CopyFS copies the file system fsys into the directory dir,
 // creating dir if necessary.
 //
@@ -140,14 +346,27 @@ func ReadDir(name string) ([]DirEntry, error) {
 // already exists in the destination, CopyFS will return an error
 // such that errors.Is(err, fs.ErrExist) will be true.
 //
-// Symbolic links in fsys are not supported. A *PathError with Err set
-// to ErrInvalid is returned when copying from a symbolic link.
+// If fsys implements [fs.ReadLinkFS], symbolic links in fsys are
+// recreated as symbolic links in dir with the same targets, after
+// everything else has been copied. CopyFS will not create a link whose
+// target is absolute or leads outside dir, even by way of the other
+// links it creates; such a link, or any symbolic link in an fsys that
+// does not implement fs.ReadLinkFS, causes CopyFS to return a *PathError
+// with Err set to ErrInvalid, and no links to be created. Likewise,
+// CopyFS returns an error for any name in fsys that is not local to dir,
+// such as one containing "..".
 //
-// Symbolic links in dir are followed.
+// Symbolic links already in dir are followed.
 //
 // Copying stops at and returns the first error encountered.
 func CopyFS(dir string, fsys fs.FS) error {
-	return fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+	// The links are checked once they are all known, since a link can
+	// lead outside dir through another, even one that comes later.
+	type link struct {
+		path, name, target string
+	}
+	var links []link
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -157,14 +376,22 @@ func CopyFS(dir string, fsys fs.FS) error {
 			return err
 		}
 		newPath := joinPath(dir, fpath)
-		if d.IsDir() {
+		switch d.Type() {
+		case ModeDir:
 			return MkdirAll(newPath, 0777)
-		}
-
-		// TODO(panjf2000): handle symlinks with the help of fs.ReadLinkFS
-		// 		once https://go.dev/issue/49580 is done.
-		//		we also need filepathlite.IsLocal from https://go.dev/cl/564295.
-		if !d.Type().IsRegular() {
+		case ModeSymlink:
+			target, err := fs.ReadLink(fsys, path)
+			if err != nil {
+				return err
+			}
+			// The link is resolved relative to its own directory.
+			if filepathlite.IsAbs(target) || !filepathlite.IsLocal(joinPath(filepathlite.Dir(fpath), target)) {
+				return &PathError{Op: "CopyFS", Path: path, Err: ErrInvalid}
+			}
+			links = append(links, link{path, fpath, target})
+			return nil
+		case 0:
+		default:
 			return &PathError{Op: "CopyFS", Path: path, Err: ErrInvalid}
 		}
 
@@ -188,4 +415,166 @@ func CopyFS(dir string, fsys fs.FS) error {
 		}
 		return w.Close()
 	})
+	if err != nil {
+		return err
+	}
+
+	targets := make(map[string]string, len(links))
+	for _, l := range links {
+		targets[l.name] = l.target
+	}
+	for _, l := range links {
+		if !copyFSLinkIsLocal(l.name, l.target, targets) {
+			return &PathError{Op: "CopyFS", Path: l.path, Err: ErrInvalid}
+		}
+	}
+	for _, l := range links {
+		if err := Symlink(l.target, joinPath(dir, l.name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyFSLinkIsLocal reports whether target, the target of the symbolic
+// link name in a copy made by CopyFS, resolves to a name inside the copy.
+// Unlike filepathlite.IsLocal, it follows the links of the copy, given
+// by name in links, that the resolution passes through.
+func copyFSLinkIsLocal(name, target string, links map[string]string) bool {
+	dir := filepathlite.Dir(name) // resolved so far; "" is the top
+	if dir == "." {
+		dir = ""
+	}
+	rest := target
+	for hops := 0; rest != ""; {
+		if filepathlite.VolumeNameLen(rest) != 0 || IsPathSeparator(rest[0]) {
+			return false
+		}
+		i := 0
+		for i < len(rest) && !IsPathSeparator(rest[i]) {
+			i++
+		}
+		elem := rest[:i]
+		for i < len(rest) && IsPathSeparator(rest[i]) {
+			i++
+		}
+		rest = rest[i:]
+
+		switch elem {
+		case ".":
+		case "..":
+			if dir == "" {
+				return false
+			}
+			if dir = filepathlite.Dir(dir); dir == "." {
+				dir = ""
+			}
+		default:
+			next := elem
+			if dir != "" {
+				next = joinPath(dir, elem)
+			}
+			t, ok := links[next]
+			if !ok {
+				dir = next
+				continue
+			}
+			// Give up on loops, as the system would.
+			if hops++; hops > 255 {
+				return false
+			}
+			if rest != "" {
+				t += string(PathSeparator) + rest
+			}
+			rest = t
+		}
+	}
+	return true
+}
+
+// WalkDir walks the file tree rooted at root, calling fn for each file or
+// directory in the tree, including root, like [path/filepath.WalkDir].
+// The files are walked in lexical order, and the semantics of fn,
+// including [fs.SkipDir] and [fs.SkipAll], are as for filepath.WalkDir.
+// WalkDir does not follow symbolic links.
+//
+// Unlike filepath.WalkDir, each directory is opened relative to its
+// parent, as by [File.OpenAt], instead of by its full name, so the walk
+// is unaffected by directories being renamed or replaced by symbolic
+// links part way through; the path passed to fn is still the name the
+// file was reached by. Systems without openat, including Windows and
+// Plan 9, fall back to opening directories by name.
+// WalkDir keeps one directory open for each level of the tree it is in.
+func WalkDir(root string, fn fs.WalkDirFunc) error {
+	info, err := Lstat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		d := fs.FileInfoToDirEntry(info)
+		if err = fn(root, d, nil); err == nil && d.IsDir() {
+			var dir *File
+			dir, err = openDir(root)
+			err = walkDirAt(dir, walkDirPrefix(root), root, d, err, fn)
+		}
+	}
+	if err == fs.SkipDir || err == fs.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// walkDirPrefix returns the prefix for the names of the files in root,
+// so that they are joined to it as [path/filepath.Join] joins them.
+func walkDirPrefix(root string) string {
+	root = filepathlite.Clean(root)
+	switch {
+	case root == ".":
+		return ""
+	case IsPathSeparator(root[len(root)-1]):
+		return root
+	}
+	return root + string(PathSeparator)
+}
+
+// walkDirAt walks the entries of the directory dir, which WalkDir has
+// already passed to fn as path and d. If err is non-nil, dir could not be
+// opened. The names of the entries are prefixed with prefix.
+func walkDirAt(dir *File, prefix, path string, d DirEntry, err error, fn fs.WalkDirFunc) error {
+	var dirs []DirEntry
+	if err == nil {
+		defer dir.Close()
+		dirs, err = dir.ReadDir(-1)
+		slices.SortFunc(dirs, func(a, b DirEntry) int {
+			return bytealg.CompareString(a.Name(), b.Name())
+		})
+	}
+	if err != nil {
+		// Second call, to report the error.
+		if err := fn(path, d, err); err != nil {
+			if err == fs.SkipDir {
+				err = nil
+			}
+			return err
+		}
+	}
+
+	for _, d1 := range dirs {
+		name := d1.Name()
+		path1 := prefix + name
+		err := fn(path1, d1, nil)
+		if err == nil && d1.IsDir() {
+			sub, err1 := dir.openDirAt(name)
+			err = walkDirAt(sub, path1+string(PathSeparator), path1, d1, err1, fn)
+		}
+		if err != nil {
+			if err == fs.SkipDir {
+				if d1.IsDir() {
+					continue
+				}
+				break
+			}
+			return err
+		}
+	}
+	return nil
 }
@@ -24,7 +24,10 @@ func (d *dirInfo) close() {
 	d.dir = 0
 }
 
-func (f *File) readdir(n int, mode readdirMode) (names []string, dirents []DirEntry, infos []FileInfo, err error) {
+func (f *File) readdir(n int, mode readdirMode, buf []DirEntry) (names []string, dirents []DirEntry, infos []FileInfo, err error) {
+	// In readdirDirEntry mode, entries are appended to buf.
+	dirents = buf
+
 	// If this file has no dirinfo, create one.
 	var d *dirInfo
 	for {
@@ -87,8 +90,8 @@ func (f *File) readdir(n int, mode readdirMode) (names []string, dirents []DirEn
 		}
 		if mode == readdirName {
 			names = append(names, string(name))
-		} else if mode == readdirDirEntry {
-			de, err := newUnixDirent(f.name, string(name), dtToType(dirent.Type))
+		} else if mode == readdirDirEntry || mode == readdirDirEntryTyped {
+			de, err := newUnixDirent(f.name, string(name), dtToType(dirent.Type), mode == readdirDirEntryTyped)
 			if IsNotExist(err) {
 				// File disappeared between readdir and stat.
 				// Treat as if it didn't exist.
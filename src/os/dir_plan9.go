@@ -10,7 +10,10 @@ import (
 	"syscall"
 )
 
-func (file *File) readdir(n int, mode readdirMode) (names []string, dirents []DirEntry, infos []FileInfo, err error) {
+func (file *File) readdir(n int, mode readdirMode, buf []DirEntry) (names []string, dirents []DirEntry, infos []FileInfo, err error) {
+	// In readdirDirEntry mode, entries are appended to buf.
+	dirents = buf
+
 	// If this file has no dirinfo, create one.
 	d := file.dirinfo.Load()
 	if d == nil {
@@ -60,7 +63,7 @@ func (file *File) readdir(n int, mode readdirMode) (names []string, dirents []Di
 			names = append(names, dir.Name)
 		} else {
 			f := fileInfoFromStat(dir)
-			if mode == readdirDirEntry {
+			if mode != readdirFileInfo {
 				dirents = append(dirents, dirEntry{f})
 			} else {
 				infos = append(infos, f)
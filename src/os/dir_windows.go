@@ -93,7 +93,10 @@ func (d *dirInfo) init(h syscall.Handle) {
 	}
 }
 
-func (file *File) readdir(n int, mode readdirMode) (names []string, dirents []DirEntry, infos []FileInfo, err error) {
+func (file *File) readdir(n int, mode readdirMode, buf []DirEntry) (names []string, dirents []DirEntry, infos []FileInfo, err error) {
+	// In readdirDirEntry mode, entries are appended to buf.
+	dirents = buf
+
 	// If this file has no dirInfo, create one.
 	var d *dirInfo
 	for {
@@ -201,7 +204,7 @@ func (file *File) readdir(n int, mode readdirMode) (names []string, dirents []Di
 				}
 				f.name = name
 				f.vol = d.vol
-				if mode == readdirDirEntry {
+				if mode != readdirFileInfo {
 					dirents = append(dirents, dirEntry{f})
 				} else {
 					infos = append(infos, f)
@@ -0,0 +1,27 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "unsafe"
+
+// directIOAlignment is the alignment of the buffers returned by
+// DirectIOAlignedBuffer. It is a multiple of the logical block size
+// of practically all devices, which is 512 or 4096 bytes.
+const directIOAlignment = 4096
+
+// DirectIOAlignedBuffer returns a new zeroed slice of size bytes whose
+// first byte is aligned to 4096 bytes, a multiple of the logical block
+// size of the devices in common use. Such a buffer is suitable for
+// reads and writes on a file opened with O_DIRECT, provided size and
+// the file offset are multiples of the logical block size too;
+// otherwise the read or write fails with EINVAL.
+func DirectIOAlignedBuffer(size int) []byte {
+	b := make([]byte, size+directIOAlignment)
+	off := 0
+	if r := int(uintptr(unsafe.Pointer(unsafe.SliceData(b))) & (directIOAlignment - 1)); r != 0 {
+		off = directIOAlignment - r
+	}
+	return b[off : off+size : off+size]
+}
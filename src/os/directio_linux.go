@@ -0,0 +1,15 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "syscall"
+
+// O_DIRECT may be or'ed with the flags of OpenFile to transfer data
+// directly between the file and the buffers of the program, bypassing
+// the page cache. The buffers, lengths and file offsets of the reads
+// and writes must be aligned to the logical block size of the device,
+// or they fail with EINVAL; see [DirectIOAlignedBuffer]. Some file
+// systems do not support O_DIRECT, and OpenFile then fails with EINVAL.
+const O_DIRECT int = syscall.O_DIRECT
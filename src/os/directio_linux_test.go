@@ -0,0 +1,59 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	"bytes"
+	"errors"
+	. "os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+func TestDirectIOAlignedBuffer(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range []int{0, 1, 512, 4096, 12345, 1 << 20} {
+		b := DirectIOAlignedBuffer(size)
+		if len(b) != size || cap(b) != size {
+			t.Errorf("DirectIOAlignedBuffer(%d) has len %d, cap %d; want %d", size, len(b), cap(b), size)
+		}
+		if p := uintptr(unsafe.Pointer(unsafe.SliceData(b))); p%4096 != 0 {
+			t.Errorf("DirectIOAlignedBuffer(%d) = %#x, not aligned to 4096 bytes", size, p)
+		}
+	}
+}
+
+func TestOpenFileDirect(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "direct")
+	f, err := OpenFile(name, O_RDWR|O_CREATE|O_DIRECT, 0o644)
+	if errors.Is(err, syscall.EINVAL) {
+		t.Skipf("file system does not support O_DIRECT: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	const size = 2 * 4096
+	want := DirectIOAlignedBuffer(size)
+	for i := range want {
+		want[i] = byte(i % 251)
+	}
+	if n, err := f.WriteAt(want, 0); n != size || err != nil {
+		t.Fatalf("WriteAt = %d, %v; want %d, nil", n, err, size)
+	}
+	got := DirectIOAlignedBuffer(size)
+	if n, err := f.ReadAt(got, 0); n != size || err != nil {
+		t.Fatalf("ReadAt = %d, %v; want %d, nil", n, err, size)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("data read back with O_DIRECT differs from the data written")
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// DiskUsage returns the size of the file system holding the named file,
+// in bytes, along with the number of bytes that are free and the number
+// that are available to the calling user. Available space can be less
+// than free space, as some file systems reserve part of the free space
+// for privileged users.
+//
+// DiskUsage uses statfs on Unix systems, statvfs on NetBSD, Solaris and
+// illumos, and GetDiskFreeSpaceExW on Windows, where avail also accounts
+// for disk quotas. The values are a snapshot: other processes may use or
+// release space at any time.
+// If there is an error, it will be of type [*PathError].
+//
+// On systems where DiskUsage is not implemented, it returns an error
+// wrapping [errors.ErrUnsupported].
+func DiskUsage(name string) (total, free, avail uint64, err error) {
+	total, free, avail, err = diskUsage(name)
+	if err != nil {
+		return 0, 0, 0, &PathError{Op: "diskusage", Path: name, Err: err}
+	}
+	return total, free, avail, nil
+}
+
+// Statfs is like [DiskUsage] for the file system holding the open file f,
+// which it queries through f rather than by resolving the name of f
+// again. It also returns the size in bytes of the blocks the file system
+// allocates space in. Statfs uses fstatfs on Unix systems, and fstatvfs
+// on NetBSD, Solaris and illumos. On Windows, it finds the volume holding
+// f from its handle, which fails for files on network shares.
+// If there is an error, it will be of type [*PathError].
+//
+// On systems where Statfs is not implemented, it returns an error
+// wrapping [errors.ErrUnsupported].
+func (f *File) Statfs() (total, free, avail uint64, blockSize int64, err error) {
+	if err := f.checkValid("statfs"); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	total, free, avail, blockSize, err = f.statfs()
+	if err != nil {
+		return 0, 0, 0, 0, f.wrapErr("statfs", err)
+	}
+	return total, free, avail, blockSize, nil
+}
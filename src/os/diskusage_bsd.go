@@ -0,0 +1,18 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd
+
+package os
+
+import "syscall"
+
+// statfsSizes returns the sizes in bytes reported by st, along with the
+// size of the blocks they are counted in.
+func statfsSizes(st *syscall.Statfs_t) (total, free, avail uint64, blockSize int64) {
+	bsize := uint64(st.Bsize)
+	// On some systems, the available block count is negative
+	// when the reserved blocks are in use.
+	return uint64(st.Blocks) * bsize, uint64(st.Bfree) * bsize, uint64(max(int64(st.Bavail), 0)) * bsize, int64(bsize)
+}
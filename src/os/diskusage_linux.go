@@ -0,0 +1,19 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "syscall"
+
+// statfsSizes returns the sizes in bytes reported by st, along with the
+// size of the blocks they are counted in.
+func statfsSizes(st *syscall.Statfs_t) (total, free, avail uint64, blockSize int64) {
+	// The block counts are in units of the fragment size,
+	// which older kernels do not report.
+	bsize := uint64(st.Frsize)
+	if bsize == 0 {
+		bsize = uint64(st.Bsize)
+	}
+	return st.Blocks * bsize, st.Bfree * bsize, st.Bavail * bsize, int64(bsize)
+}
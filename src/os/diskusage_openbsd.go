@@ -0,0 +1,16 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "syscall"
+
+// statfsSizes returns the sizes in bytes reported by st, along with the
+// size of the blocks they are counted in.
+func statfsSizes(st *syscall.Statfs_t) (total, free, avail uint64, blockSize int64) {
+	bsize := uint64(st.F_bsize)
+	// The available block count is negative when the
+	// reserved blocks are in use.
+	return st.F_blocks * bsize, st.F_bfree * bsize, uint64(max(st.F_bavail, 0)) * bsize, int64(bsize)
+}
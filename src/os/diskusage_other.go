@@ -0,0 +1,17 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !windows
+
+package os
+
+import "errors"
+
+func diskUsage(name string) (total, free, avail uint64, err error) {
+	return 0, 0, 0, errors.ErrUnsupported
+}
+
+func (f *File) statfs() (total, free, avail uint64, blockSize int64, err error) {
+	return 0, 0, 0, 0, errors.ErrUnsupported
+}
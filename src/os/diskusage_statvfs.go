@@ -0,0 +1,54 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build netbsd || solaris
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+)
+
+// These systems have no statfs; statvfs reports the same sizes.
+
+func diskUsage(name string) (total, free, avail uint64, err error) {
+	var st unix.Statvfs_t
+	if err := ignoringEINTR(func() error {
+		return unix.Statvfs(name, &st)
+	}); err != nil {
+		return 0, 0, 0, err
+	}
+	total, free, avail, _ = statvfsSizes(&st)
+	return total, free, avail, nil
+}
+
+func (f *File) statfs() (total, free, avail uint64, blockSize int64, err error) {
+	var st unix.Statvfs_t
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		err = ignoringEINTR(func() error {
+			return unix.Fstatvfs(int(fd), &st)
+		})
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	total, free, avail, blockSize = statvfsSizes(&st)
+	return total, free, avail, blockSize, nil
+}
+
+// statvfsSizes returns the sizes in bytes reported by st, along with the
+// size of the blocks they are counted in.
+func statvfsSizes(st *unix.Statvfs_t) (total, free, avail uint64, blockSize int64) {
+	// The block counts are in units of the fragment size.
+	bsize := uint64(st.Frsize)
+	if bsize == 0 {
+		bsize = uint64(st.Bsize)
+	}
+	return st.Blocks * bsize, st.Bfree * bsize, st.Bavail * bsize, int64(bsize)
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || openbsd
+
+package os
+
+import (
+	"runtime"
+	"syscall"
+)
+
+func diskUsage(name string) (total, free, avail uint64, err error) {
+	var st syscall.Statfs_t
+	if err := ignoringEINTR(func() error {
+		return syscall.Statfs(name, &st)
+	}); err != nil {
+		return 0, 0, 0, err
+	}
+	total, free, avail, _ = statfsSizes(&st)
+	return total, free, avail, nil
+}
+
+func (f *File) statfs() (total, free, avail uint64, blockSize int64, err error) {
+	var st syscall.Statfs_t
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		err = ignoringEINTR(func() error {
+			return syscall.Fstatfs(int(fd), &st)
+		})
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	total, free, avail, blockSize = statfsSizes(&st)
+	return total, free, avail, blockSize, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"errors"
+	"internal/filepathlite"
+	"internal/stringslite"
+	"internal/syscall/windows"
+	"syscall"
+)
+
+func diskUsage(name string) (total, free, avail uint64, err error) {
+	// GetDiskFreeSpaceEx wants a directory.
+	fi, err := Stat(name)
+	if err != nil {
+		return 0, 0, 0, underlyingError(err)
+	}
+	if !fi.IsDir() {
+		name = filepathlite.Dir(name)
+	}
+	p, err := syscall.UTF16PtrFromString(fixLongPath(name))
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if err := windows.GetDiskFreeSpaceEx(p, &avail, &total, &free); err != nil {
+		return 0, 0, 0, err
+	}
+	return total, free, avail, nil
+}
+
+func (f *File) statfs() (total, free, avail uint64, blockSize int64, err error) {
+	// Find the volume holding f from its handle, by the GUID path of the
+	// file, \\?\Volume{GUID}\dir\file, rather than by the name of f.
+	path, err := windows.FinalPath(f.pfd.Sysfd, windows.VOLUME_NAME_GUID)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	volume, ok := stringslite.CutPrefix(path, `\\?\`)
+	i := stringslite.IndexByte(volume, '\\')
+	if !ok || i < 0 {
+		return 0, 0, 0, 0, errors.New("GetFinalPathNameByHandle returned unexpected path: " + path)
+	}
+	// The root directory of the volume, with its trailing backslash.
+	root := path[:len(path)-len(volume)+i+1]
+	p, err := syscall.UTF16PtrFromString(root)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	if err := windows.GetDiskFreeSpaceEx(p, &avail, &total, &free); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	var sectorsPerCluster, bytesPerSector, freeClusters, totalClusters uint32
+	if err := windows.GetDiskFreeSpace(p, &sectorsPerCluster, &bytesPerSector, &freeClusters, &totalClusters); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	return total, free, avail, int64(sectorsPerCluster) * int64(bytesPerSector), nil
+}
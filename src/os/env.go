@@ -8,7 +8,12 @@ package os
 
 import (
 	"internal/testlog"
+	"iter"
+	"runtime"
+	"slices"
 	"syscall"
+	"time"
+	_ "unsafe" // for go:linkname
 )
 
 // Expand replaces ${var} or $var in the string based on the mapping function.
@@ -121,17 +126,62 @@ func Setenv(key, value string) error {
 	if err != nil {
 		return NewSyscallError("setenv", err)
 	}
+	if key == "PWD" {
+		clearGetwdCache()
+	}
 	return nil
 }
 
+// SetenvBatch sets the values of the environment variables named by the
+// keys of vars, as if by calling [Setenv] for each of them, but as a
+// single update: a concurrent call to [Environ] or [Getenv] observes
+// either all of the new values or none of them. The variables are added
+// to the environment in sorted key order. If any key or value is
+// invalid, SetenvBatch returns an error and sets none of them.
+//
+// On Windows, the system environment block is updated one variable at a
+// time, so the update is atomic only with respect to functions in this
+// package, not to other code that reads the environment block directly.
+// If the system fails to set one of the variables, SetenvBatch restores
+// the previous values of those it has already set before returning the
+// error.
+func SetenvBatch(vars map[string]string) error {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	values := make([]string, len(keys))
+	for i, k := range keys {
+		values[i] = vars[k]
+	}
+	if err := setenvBatch(keys, values); err != nil {
+		return NewSyscallError("setenv", err)
+	}
+	if _, ok := vars["PWD"]; ok {
+		clearGetwdCache()
+	}
+	return nil
+}
+
+// Provided by syscall.
+//
+//go:linkname setenvBatch
+func setenvBatch(keys, values []string) error
+
 // Unsetenv unsets a single environment variable.
 func Unsetenv(key string) error {
-	return syscall.Unsetenv(key)
+	err := syscall.Unsetenv(key)
+	if key == "PWD" {
+		clearGetwdCache()
+	}
+	return err
 }
 
 // Clearenv deletes all environment variables.
 func Clearenv() {
 	syscall.Clearenv()
+	clearGetwdCache()
 }
 
 // Environ returns a copy of strings representing the environment,
@@ -139,3 +189,173 @@ func Clearenv() {
 func Environ() []string {
 	return syscall.Environ()
 }
+
+// EnvironSeq returns an iterator over the key/value pairs of the
+// environment, in the order they are returned by [Environ]. Each entry
+// is split at its first "=", so the value may itself contain "=".
+// On Windows, where the environment may hold keys that begin with "="
+// (such as "=C:"), a leading "=" is part of the key.
+//
+// Entries that are not of the form "key=value" cannot be retrieved by
+// [Getenv] and are skipped. Environ does not normally report the same
+// key twice; if an entry repeats an earlier key, it is skipped too, as
+// Getenv reports the first value.
+func EnvironSeq() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		var seen map[string]bool
+		for _, kv := range Environ() {
+			k, v, ok := splitEnv(kv)
+			if !ok {
+				continue
+			}
+			if seen == nil {
+				seen = make(map[string]bool)
+			}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// EnvironMap returns a snapshot of the environment as a map from keys to
+// values. It contains the same pairs as [EnvironSeq].
+func EnvironMap() map[string]string {
+	m := make(map[string]string)
+	for k, v := range EnvironSeq() {
+		m[k] = v
+	}
+	return m
+}
+
+// splitEnv splits an environment entry into its key and value.
+func splitEnv(kv string) (key, value string, ok bool) {
+	// Skip a leading "=", which is part of the key on Windows.
+	start := 0
+	if runtime.GOOS == "windows" && kv != "" && kv[0] == '=' {
+		start = 1
+	}
+	for i := start; i < len(kv); i++ {
+		if kv[i] == '=' {
+			return kv[:i], kv[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+// GetenvInt retrieves the value of the environment variable named by the
+// key and parses it as a decimal integer, as by [strconv.Atoi].
+// It returns def if the variable is not present or cannot be parsed.
+// To detect those cases, use [LookupEnvInt].
+func GetenvInt(key string, def int) int {
+	if v, ok := LookupEnvInt(key); ok {
+		return v
+	}
+	return def
+}
+
+// LookupEnvInt is like [GetenvInt], but reports whether the variable is
+// present and holds a valid integer. If not, the returned value is
+// zero and the boolean is false.
+func LookupEnvInt(key string) (int, bool) {
+	s, ok := LookupEnv(key)
+	if !ok {
+		return 0, false
+	}
+	return atoi(s)
+}
+
+// GetenvBool retrieves the value of the environment variable named by the
+// key and parses it as a boolean, as by [strconv.ParseBool]: it accepts
+// 1, t, T, TRUE, true, True, 0, f, F, FALSE, false and False.
+// It returns def if the variable is not present or cannot be parsed.
+// To detect those cases, use [LookupEnvBool].
+func GetenvBool(key string, def bool) bool {
+	if v, ok := LookupEnvBool(key); ok {
+		return v
+	}
+	return def
+}
+
+// LookupEnvBool is like [GetenvBool], but reports whether the variable is
+// present and holds a valid boolean. If not, the returned value is
+// false and so is the boolean.
+func LookupEnvBool(key string) (bool, bool) {
+	s, ok := LookupEnv(key)
+	if !ok {
+		return false, false
+	}
+	switch s {
+	case "1", "t", "T", "TRUE", "true", "True":
+		return true, true
+	case "0", "f", "F", "FALSE", "false", "False":
+		return false, true
+	}
+	return false, false
+}
+
+// GetenvDuration retrieves the value of the environment variable named by
+// the key and parses it as a duration, as by [time.ParseDuration].
+// It returns def if the variable is not present or cannot be parsed.
+// To detect those cases, use [LookupEnvDuration].
+func GetenvDuration(key string, def time.Duration) time.Duration {
+	if v, ok := LookupEnvDuration(key); ok {
+		return v
+	}
+	return def
+}
+
+// LookupEnvDuration is like [GetenvDuration], but reports whether the
+// variable is present and holds a valid duration. If not, the returned
+// value is zero and the boolean is false.
+func LookupEnvDuration(key string) (time.Duration, bool) {
+	s, ok := LookupEnv(key)
+	if !ok {
+		return 0, false
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, false
+	}
+	return d, true
+}
+
+// atoi is like strconv.Atoi, which package os cannot import.
+// It reports whether s is a valid decimal integer in the range of int.
+func atoi(s string) (int, bool) {
+	neg := false
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if s == "" {
+		return 0, false
+	}
+	// Accumulate the magnitude as a uint so that the most negative
+	// int can be parsed.
+	const maxUint = ^uint(0)
+	limit := uint(maxUint>>1) + 1 // magnitude of the most negative int
+	if !neg {
+		limit--
+	}
+	var n uint
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		d := uint(c - '0')
+		if n > (limit-d)/10 {
+			return 0, false
+		}
+		n = n*10 + d
+	}
+	if neg {
+		return -int(n), true
+	}
+	return int(n), true
+}
@@ -7,8 +7,10 @@ package os_test
 import (
 	. "os"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 // testGetenv gives us a controlled set of variables for testing Expand.
@@ -167,6 +169,228 @@ func TestLookupEnv(t *testing.T) {
 	}
 }
 
+const typedEnvKey = "GO_TEST_TYPED_ENV"
+
+const (
+	maxInt = int(^uint(0) >> 1)
+	minInt = -maxInt - 1
+)
+
+func TestGetenvInt(t *testing.T) {
+	Unsetenv(typedEnvKey)
+	if v, ok := LookupEnvInt(typedEnvKey); ok || v != 0 {
+		t.Errorf("LookupEnvInt of unset variable = %d, %v; want 0, false", v, ok)
+	}
+	if v := GetenvInt(typedEnvKey, 42); v != 42 {
+		t.Errorf("GetenvInt of unset variable = %d; want 42", v)
+	}
+
+	for _, s := range []string{
+		"0", "1", "-1", "+7", "0123", "-0",
+		strconv.Itoa(maxInt), strconv.Itoa(minInt),
+		"", "+", "-", " 1", "1 ", "1_000", "0x10", "1e3", "--1", "١",
+		strconv.Itoa(maxInt) + "0", "9223372036854775808", "-9223372036854775809",
+		"18446744073709551616", "99999999999999999999999",
+	} {
+		t.Setenv(typedEnvKey, s)
+		want, err := strconv.Atoi(s)
+		wantOK := err == nil
+		if !wantOK {
+			want = 0
+		}
+		if v, ok := LookupEnvInt(typedEnvKey); v != want || ok != wantOK {
+			t.Errorf("LookupEnvInt with value %q = %d, %v; want %d, %v", s, v, ok, want, wantOK)
+		}
+		if !wantOK {
+			want = 42
+		}
+		if v := GetenvInt(typedEnvKey, 42); v != want {
+			t.Errorf("GetenvInt with value %q = %d; want %d", s, v, want)
+		}
+	}
+}
+
+func TestGetenvBool(t *testing.T) {
+	Unsetenv(typedEnvKey)
+	if v, ok := LookupEnvBool(typedEnvKey); ok || v {
+		t.Errorf("LookupEnvBool of unset variable = %v, %v; want false, false", v, ok)
+	}
+	if v := GetenvBool(typedEnvKey, true); !v {
+		t.Errorf("GetenvBool of unset variable = %v; want true", v)
+	}
+
+	for _, s := range []string{
+		"1", "t", "T", "TRUE", "true", "True",
+		"0", "f", "F", "FALSE", "false", "False",
+		"", "yes", "no", "tRUE", "2", " true", "on",
+	} {
+		t.Setenv(typedEnvKey, s)
+		want, err := strconv.ParseBool(s)
+		wantOK := err == nil
+		if v, ok := LookupEnvBool(typedEnvKey); v != want || ok != wantOK {
+			t.Errorf("LookupEnvBool with value %q = %v, %v; want %v, %v", s, v, ok, want, wantOK)
+		}
+		for _, def := range []bool{false, true} {
+			want := want
+			if !wantOK {
+				want = def
+			}
+			if v := GetenvBool(typedEnvKey, def); v != want {
+				t.Errorf("GetenvBool(%v) with value %q = %v; want %v", def, s, v, want)
+			}
+		}
+	}
+}
+
+func TestGetenvDuration(t *testing.T) {
+	const def = 5 * time.Second
+	Unsetenv(typedEnvKey)
+	if v, ok := LookupEnvDuration(typedEnvKey); ok || v != 0 {
+		t.Errorf("LookupEnvDuration of unset variable = %v, %v; want 0, false", v, ok)
+	}
+	if v := GetenvDuration(typedEnvKey, def); v != def {
+		t.Errorf("GetenvDuration of unset variable = %v; want %v", v, def)
+	}
+
+	for _, tt := range []struct {
+		s    string
+		want time.Duration
+		ok   bool
+	}{
+		{"0", 0, true},
+		{"1.5h", 90 * time.Minute, true},
+		{"-3ms", -3 * time.Millisecond, true},
+		{"1h2m3s", time.Hour + 2*time.Minute + 3*time.Second, true},
+		{"", 0, false},
+		{"10", 0, false},
+		{"1 s", 0, false},
+		{"1d", 0, false},
+		{"9999999999h", 0, false},
+	} {
+		t.Setenv(typedEnvKey, tt.s)
+		if v, ok := LookupEnvDuration(typedEnvKey); v != tt.want || ok != tt.ok {
+			t.Errorf("LookupEnvDuration with value %q = %v, %v; want %v, %v", tt.s, v, ok, tt.want, tt.ok)
+		}
+		want := tt.want
+		if !tt.ok {
+			want = def
+		}
+		if v := GetenvDuration(typedEnvKey, def); v != want {
+			t.Errorf("GetenvDuration with value %q = %v; want %v", tt.s, v, want)
+		}
+	}
+}
+
+func TestEnvironSeq(t *testing.T) {
+	const key = "GO_TEST_ENVIRON_SEQ"
+	const value = "a=b==c="
+	t.Setenv(key, value)
+
+	m := EnvironMap()
+	if v, ok := m[key]; !ok || v != value {
+		t.Errorf("EnvironMap()[%q] = %q, %v; want %q, true", key, v, ok, value)
+	}
+	n := 0
+	for k, v := range EnvironSeq() {
+		n++
+		if v2, ok := LookupEnv(k); !ok || v != v2 {
+			t.Errorf("EnvironSeq yielded %q=%q, but LookupEnv(%q) = %q, %v", k, v, k, v2, ok)
+		}
+		if v2, ok := m[k]; !ok || v != v2 {
+			t.Errorf("EnvironSeq yielded %q=%q, but EnvironMap()[%q] = %q, %v", k, v, k, v2, ok)
+		}
+	}
+	if n != len(m) {
+		t.Errorf("EnvironSeq yielded %d pairs, EnvironMap has %d", n, len(m))
+	}
+
+	// Stopping early is allowed.
+	for range EnvironSeq() {
+		break
+	}
+}
+
+func TestSetenvBatch(t *testing.T) {
+	keys := []string{"GO_TEST_BATCH_A", "GO_TEST_BATCH_B", "GO_TEST_BATCH_C"}
+	for _, k := range keys {
+		t.Setenv(k, "old")
+	}
+
+	if err := SetenvBatch(map[string]string{keys[0]: "x", keys[1]: "y=z", keys[2]: ""}); err != nil {
+		t.Fatal(err)
+	}
+	for k, want := range map[string]string{keys[0]: "x", keys[1]: "y=z", keys[2]: ""} {
+		if v, ok := LookupEnv(k); !ok || v != want {
+			t.Errorf("after SetenvBatch, LookupEnv(%q) = %q, %v; want %q, true", k, v, ok, want)
+		}
+	}
+
+	// An invalid key leaves the environment unchanged.
+	err := SetenvBatch(map[string]string{keys[0]: "new", "GO_TEST_BATCH_\x00": "new"})
+	if err == nil {
+		t.Fatal("SetenvBatch with NUL in key succeeded")
+	}
+	if _, ok := err.(*SyscallError); !ok {
+		t.Errorf("SetenvBatch error %v has type %T, want *SyscallError", err, err)
+	}
+	if v := Getenv(keys[0]); v != "x" {
+		t.Errorf("after failed SetenvBatch, Getenv(%q) = %q; want %q", keys[0], v, "x")
+	}
+	for _, bad := range []string{"", "GO_TEST_BATCH_=D"} {
+		if err := SetenvBatch(map[string]string{keys[0]: "new", bad: "new"}); err == nil {
+			t.Errorf("SetenvBatch with key %q succeeded", bad)
+		}
+		if v := Getenv(keys[0]); v != "x" {
+			t.Errorf("after SetenvBatch with key %q, Getenv(%q) = %q; want %q", bad, keys[0], v, "x")
+		}
+	}
+
+	if err := SetenvBatch(nil); err != nil {
+		t.Errorf("SetenvBatch(nil) = %v", err)
+	}
+}
+
+func TestSetenvBatchAtomic(t *testing.T) {
+	keys := []string{"GO_TEST_BATCH_ATOMIC_A", "GO_TEST_BATCH_ATOMIC_B", "GO_TEST_BATCH_ATOMIC_C"}
+	for _, k := range keys {
+		t.Setenv(k, "0")
+	}
+
+	const n = 1000
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		vars := make(map[string]string)
+		for i := 1; i <= n; i++ {
+			for _, k := range keys {
+				vars[k] = strconv.Itoa(i)
+			}
+			if err := SetenvBatch(vars); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	for stop := false; !stop; {
+		select {
+		case <-done:
+			stop = true
+		default:
+		}
+		env := EnvironMap()
+		v := env[keys[0]]
+		for _, k := range keys[1:] {
+			if env[k] != v {
+				t.Fatalf("Environ observed a partial SetenvBatch: %s=%q, %s=%q", keys[0], v, k, env[k])
+			}
+		}
+	}
+	if v := Getenv(keys[0]); v != strconv.Itoa(n) {
+		t.Errorf("after SetenvBatch loop, Getenv(%q) = %q; want %q", keys[0], v, strconv.Itoa(n))
+	}
+}
+
 // On Windows, Environ was observed to report keys with a single leading "=".
 // Check that they are properly reported by LookupEnv and can be set by SetEnv.
 // See https://golang.org/issue/49886.
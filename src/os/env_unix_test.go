@@ -8,6 +8,7 @@ package os_test
 
 import (
 	"fmt"
+	"internal/testenv"
 	. "os"
 	"testing"
 )
@@ -54,3 +55,34 @@ func TestExpandEnvShellSpecialVar(t *testing.T) {
 		}
 	}
 }
+
+func TestEnvironSeqMalformed(t *testing.T) {
+	if Getenv("GO_WANT_HELPER_PROCESS") == "1" {
+		for k, v := range EnvironSeq() {
+			fmt.Printf("%q=%q\n", k, v)
+		}
+		Exit(0)
+	}
+
+	testenv.MustHaveExec(t)
+	t.Parallel()
+
+	cmd := testenv.Command(t, Args[0], "-test.run=^TestEnvironSeqMalformed$")
+	cmd.Env = []string{
+		"GO_WANT_HELPER_PROCESS=1",
+		"NOEQUALS",
+		"A=1=2",
+		"EMPTY=",
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("%v: %v", cmd, err)
+	}
+	want := `"GO_WANT_HELPER_PROCESS"="1"
+"A"="1=2"
+"EMPTY"=""
+`
+	if got := string(out); got != want {
+		t.Errorf("child EnvironSeq yielded:\n%s\nwant:\n%s", got, want)
+	}
+}
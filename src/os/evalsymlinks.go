@@ -0,0 +1,152 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/filepathlite"
+	"syscall"
+)
+
+// maxSymlinks is the number of symbolic links that EvalSymlinks
+// follows before it reports a loop.
+const maxSymlinks = 255
+
+// EvalSymlinks returns the path name after the evaluation of any symbolic
+// links, like [path/filepath.EvalSymlinks]. Unlike filepath.EvalSymlinks,
+// the result is always a cleaned absolute path: a relative path is
+// interpreted relative to the current directory.
+//
+// On Linux, if the kernel supports openat2, EvalSymlinks has the kernel
+// resolve the whole path at once, and reports the name of the directory
+// entry it reached. The result is therefore consistent even if the links
+// are changed concurrently. As with open(2), the special links in /proc,
+// such as /proc/self/exe, resolve to the file they refer to. Otherwise,
+// EvalSymlinks resolves path one component at a time, calling [Lstat] and
+// [Readlink].
+//
+// If path refers to a chain of more than 255 links, or to a loop of links,
+// EvalSymlinks reports an error wrapping [syscall.ELOOP].
+// If there is an error, it will be of type [*PathError].
+func EvalSymlinks(path string) (string, error) {
+	if path == "" {
+		return "", &PathError{Op: "evalsymlinks", Path: path, Err: syscall.ENOENT}
+	}
+	if dest, ok, err := evalSymlinksKernel(path); ok {
+		if err != nil {
+			return "", &PathError{Op: "evalsymlinks", Path: path, Err: err}
+		}
+		return dest, nil
+	}
+	abs, err := absPath(path)
+	if err != nil {
+		return "", &PathError{Op: "evalsymlinks", Path: path, Err: underlyingError(err)}
+	}
+	dest, err := walkSymlinks(abs)
+	if err != nil {
+		return "", &PathError{Op: "evalsymlinks", Path: path, Err: err}
+	}
+	return dest, nil
+}
+
+// walkSymlinks resolves the absolute path one component at a time.
+// It is based on the function of the same name in path/filepath.
+// Errors are returned unwrapped.
+func walkSymlinks(path string) (string, error) {
+	volLen := filepathlite.VolumeNameLen(path)
+	if volLen < len(path) && IsPathSeparator(path[volLen]) {
+		volLen++
+	}
+	vol := path[:volLen]
+	dest := vol
+	linksWalked := 0
+	for start, end := volLen, volLen; start < len(path); start = end {
+		for start < len(path) && IsPathSeparator(path[start]) {
+			start++
+		}
+		end = start
+		for end < len(path) && !IsPathSeparator(path[end]) {
+			end++
+		}
+
+		// The next path component is in path[start:end].
+		if end == start {
+			// No more path components.
+			break
+		} else if path[start:end] == "." {
+			continue
+		} else if path[start:end] == ".." {
+			// Back up to the previous component. As path is
+			// absolute, ".." at the root is the root itself.
+			r := len(dest) - 1
+			for r >= volLen && !IsPathSeparator(dest[r]) {
+				r--
+			}
+			if r < volLen {
+				dest = vol
+			} else {
+				dest = dest[:r]
+			}
+			continue
+		}
+
+		// Ordinary path component. Add it to the result.
+		if len(dest) > volLen {
+			dest += string(PathSeparator)
+		}
+		dest += path[start:end]
+
+		fi, err := Lstat(dest)
+		if err != nil {
+			return "", underlyingError(err)
+		}
+		if fi.Mode()&ModeSymlink == 0 {
+			if !fi.IsDir() && end < len(path) {
+				return "", syscall.ENOTDIR
+			}
+			continue
+		}
+
+		// Found a symlink.
+		linksWalked++
+		if linksWalked > maxSymlinks {
+			return "", errSymlinkLoop
+		}
+		link, err := Readlink(dest)
+		if err != nil {
+			return "", underlyingError(err)
+		}
+		path = link + path[end:]
+
+		if v := filepathlite.VolumeNameLen(link); v > 0 {
+			// Symlink to drive name is an absolute path.
+			if v < len(link) && IsPathSeparator(link[v]) {
+				v++
+			}
+			vol = link[:v]
+			volLen = v
+			dest = vol
+			end = v
+		} else if len(link) > 0 && IsPathSeparator(link[0]) {
+			// Symlink to absolute path, on the same volume.
+			path = vol + path[1:]
+			dest = vol
+			end = volLen
+		} else {
+			// Symlink to relative path; replace the last
+			// path component in dest.
+			r := len(dest) - 1
+			for r >= volLen && !IsPathSeparator(dest[r]) {
+				r--
+			}
+			if r < volLen {
+				dest = vol
+			} else {
+				dest = dest[:r]
+			}
+			end = 0
+		}
+	}
+	return filepathlite.Clean(dest), nil
+}
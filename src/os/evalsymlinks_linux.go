@@ -0,0 +1,54 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/itoa"
+	"internal/stringslite"
+	"internal/syscall/unix"
+	"syscall"
+)
+
+// evalSymlinksKernel resolves path with a single openat2 call, and reads
+// the name the kernel reached back from /proc. It reports ok == false if
+// openat2 or /proc is not available, and the caller should resolve path
+// itself.
+func evalSymlinksKernel(path string) (dest string, ok bool, err error) {
+	if openat2Unsupported.Load() {
+		return "", false, nil
+	}
+	how := &unix.OpenHow{
+		Flags: uint64(unix.O_PATH | syscall.O_CLOEXEC),
+	}
+	var fd int
+	err = ignoringEINTR(func() (err error) {
+		fd, err = unix.Openat2(unix.AT_FDCWD, path, how)
+		return err
+	})
+	switch err {
+	case nil:
+	case syscall.ENOSYS:
+		openat2Unsupported.Store(true)
+		return "", false, nil
+	case syscall.EPERM, syscall.EAGAIN:
+		// As in openat2InRoot, fall back to the component walk.
+		return "", false, nil
+	default:
+		return "", true, err
+	}
+	defer syscall.Close(fd)
+
+	dest, err = Readlink("/proc/self/fd/" + itoa.Itoa(fd))
+	if err != nil || len(dest) == 0 || dest[0] != '/' {
+		// /proc is not mounted, or the file is not reachable from
+		// our root directory.
+		return "", false, nil
+	}
+	if stringslite.HasSuffix(dest, " (deleted)") {
+		// The file was removed after it was opened.
+		return "", false, nil
+	}
+	return dest, true, nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	. "os"
+	"testing"
+)
+
+// TestEvalSymlinksWithoutOpenat2 runs TestEvalSymlinks using the
+// component-by-component resolution used when openat2 is unavailable.
+func TestEvalSymlinksWithoutOpenat2(t *testing.T) {
+	old := Openat2Unsupported.Load()
+	Openat2Unsupported.Store(true)
+	defer Openat2Unsupported.Store(old)
+
+	TestEvalSymlinks(t)
+}
+
+func TestEvalSymlinksProcSelfExe(t *testing.T) {
+	if _, err := Lstat("/proc/self/exe"); err != nil {
+		t.Skipf("/proc not available: %v", err)
+	}
+	exe, err := Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := Openat2Unsupported.Load()
+	defer Openat2Unsupported.Store(old)
+	for _, unsupported := range []bool{false, true} {
+		Openat2Unsupported.Store(unsupported)
+		got, err := EvalSymlinks("/proc/self/exe")
+		if err != nil || got != exe {
+			t.Errorf("with openat2 unsupported = %v: EvalSymlinks(%q) = %q, %v; want %q", unsupported, "/proc/self/exe", got, err, exe)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !plan9
+
+package os_test
+
+import (
+	"errors"
+	"internal/testenv"
+	. "os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestEvalSymlinks(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	// Not parallel: TestEvalSymlinksWithoutOpenat2 runs this test
+	// with a global setting changed.
+
+	dir := t.TempDir()
+	mustMkdirAll := func(name string) {
+		t.Helper()
+		if err := MkdirAll(filepath.Join(dir, name), 0777); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustSymlink := func(oldname, newname string) {
+		t.Helper()
+		if err := Symlink(oldname, filepath.Join(dir, newname)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustMkdirAll(filepath.Join("real", "sub"))
+	if err := WriteFile(filepath.Join(dir, "real", "file"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	mustMkdirAll("links")
+	// A chain of links, ending with an absolute one.
+	mustSymlink(filepath.Join(dir, "real"), filepath.Join("links", "abs"))
+	mustSymlink("abs", filepath.Join("links", "chain2"))
+	mustSymlink("chain2", filepath.Join("links", "chain1"))
+	// Relative links, going up the tree.
+	mustSymlink(filepath.Join("..", "real", "file"), filepath.Join("links", "relfile"))
+	mustSymlink(filepath.Join("..", "..", "links", "chain1", "sub"), filepath.Join("real", "sub", "up"))
+	// A loop.
+	mustSymlink("loop2", "loop1")
+	mustSymlink("loop1", "loop2")
+
+	for _, name := range []string{
+		"real",
+		"real/file",
+		"links/abs",
+		"links/chain1",
+		"links/chain1/file",
+		"links/chain1/../links",
+		"links/relfile",
+		"real/sub/up/up/up",
+		"links/./chain2/sub/../file",
+	} {
+		// Not filepath.Join, which would clean the path lexically.
+		path := dir + string(PathSeparator) + filepath.FromSlash(name)
+		want, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := EvalSymlinks(path)
+		if err != nil {
+			t.Errorf("EvalSymlinks(%q): %v", name, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("EvalSymlinks(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	for _, name := range []string{"loop1", filepath.Join("loop2", "file")} {
+		_, err := EvalSymlinks(filepath.Join(dir, name))
+		if !errors.Is(err, syscall.ELOOP) {
+			t.Errorf("EvalSymlinks(%q) = %v, want ELOOP", name, err)
+		}
+		var pe *PathError
+		if !errors.As(err, &pe) {
+			t.Errorf("EvalSymlinks(%q) error is %T, want *PathError", name, err)
+		}
+	}
+
+	for _, name := range []string{"missing", filepath.Join("links", "relfile", "file")} {
+		if _, err := EvalSymlinks(filepath.Join(dir, name)); err == nil {
+			t.Errorf("EvalSymlinks(%q) succeeded, want error", name)
+		}
+	}
+
+	// A relative path is resolved against the current directory.
+	wd, err := Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := filepath.EvalSymlinks(wd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := EvalSymlinks("."); err != nil || got != want {
+		t.Errorf(`EvalSymlinks(".") = %q, %v; want %q, nil`, got, err, want)
+	}
+}
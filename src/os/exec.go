@@ -5,6 +5,7 @@
 package os
 
 import (
+	"context"
 	"errors"
 	"internal/testlog"
 	"runtime"
@@ -100,6 +101,15 @@ type Process struct {
 	// On Linux, it is a pidfd.
 	// It is unused on other GOOSes.
 	handle uintptr
+
+	// group identifies the group of processes that KillGroup
+	// terminates: a process group ID on Unix, and a Job Object handle
+	// on Windows. It is zero if the process was not started in a group
+	// of its own. group is immutable.
+	//
+	// On Windows, the Job Object handle has the same lifetime as
+	// handle, so it too must only be used while holding a reference.
+	group uintptr
 }
 
 func newPIDProcess(pid int) *Process {
@@ -267,6 +277,24 @@ type ProcAttr struct {
 	// and calling Close will not interrupt a Read or Write.
 	Files []*File
 
+	// If NewProcessGroup is true, the new process is started in a
+	// group of its own, which its descendants join unless they leave
+	// it explicitly, so that [Process.KillGroup] can terminate all of
+	// them together. On Unix, this sets Setpgid in Sys. On Windows,
+	// the process is created with CREATE_NEW_PROCESS_GROUP and
+	// assigned to a new Job Object.
+	NewProcessGroup bool
+
+	// If DetachSession is true, the new process is started in a
+	// session of its own, detached from the controlling terminal of
+	// the calling process. It implies NewProcessGroup. On Unix, this
+	// sets Setsid in Sys instead of Setpgid, as a session leader also
+	// leads a new process group; StartProcess therefore fails if
+	// Setpgid or Foreground is also set in Sys. On Windows, the
+	// process is also created with DETACHED_PROCESS, so that it has
+	// no console.
+	DetachSession bool
+
 	// Operating system-specific process creation attributes.
 	// Note that setting this field means that your program
 	// may not execute properly or even compile on some
@@ -301,6 +329,18 @@ func FindProcess(pid int) (*Process, error) {
 	return findProcess(pid)
 }
 
+// FindProcessLive is like [FindProcess], but returns [ErrProcessDone]
+// if there is no process with the given pid, including on Unix systems.
+//
+// On Linux, the returned Process refers to the process by a pidfd where
+// available, so it keeps referring to the same process even if it exits
+// and its pid is reused. On other Unix systems, FindProcessLive checks
+// that the process exists with kill(pid, 0), and the result may be
+// stale as soon as it returns.
+func FindProcessLive(pid int) (*Process, error) {
+	return findProcessLive(pid)
+}
+
 // StartProcess starts a new process with the program, arguments and attributes
 // specified by name, argv and attr. The argv slice will become [os.Args] in the
 // new process, so it normally starts with the program name.
@@ -349,6 +389,28 @@ func (p *Process) Kill() error {
 	return p.kill()
 }
 
+// KillGroup causes the [Process] and the other processes in its group
+// to exit immediately. The Process must have been started by
+// [StartProcess] with [ProcAttr.NewProcessGroup] or
+// [ProcAttr.DetachSession] set, or on Unix with Setpgid or Setsid set
+// in [ProcAttr.Sys]; otherwise KillGroup returns an error.
+//
+// On Unix, KillGroup sends SIGKILL to the process group. On Windows,
+// it terminates the process's Job Object; a process that creates its
+// own child before being assigned to the Job Object, immediately after
+// it has started, may leave that child outside the group. On other
+// systems KillGroup returns an error wrapping [errors.ErrUnsupported].
+//
+// As with [Process.Signal], KillGroup returns [ErrProcessDone] once the
+// Process has been waited for.
+func (p *Process) KillGroup() error {
+	return p.killGroup()
+}
+
+// errNoProcessGroup is returned by KillGroup for a process that was
+// not started in a group of its own.
+var errNoProcessGroup = errors.New("os: process was not started in a new process group")
+
 // Wait waits for the [Process] to exit, and then returns a
 // ProcessState describing its status and an error, if any.
 // Wait releases any resources associated with the Process.
@@ -358,6 +420,25 @@ func (p *Process) Wait() (*ProcessState, error) {
 	return p.wait()
 }
 
+// WaitContext is like [Process.Wait] but gives up waiting when ctx is
+// done. If ctx is done before the process exits, WaitContext returns
+// ctx.Err() and leaves the process unreaped, so a later call to Wait
+// or WaitContext still reports its status.
+//
+// On Linux, WaitContext waits on the process's pidfd when one is
+// available. Elsewhere it may leave a goroutine blocked until the
+// process exits. On Plan 9, WaitContext returns an error wrapping
+// [errors.ErrUnsupported] unless ctx can never be canceled.
+func (p *Process) WaitContext(ctx context.Context) (*ProcessState, error) {
+	if ctx.Done() == nil {
+		return p.wait()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.waitContext(ctx)
+}
+
 // Signal sends a signal to the [Process].
 // Sending [Interrupt] on Windows is not implemented.
 func (p *Process) Signal(sig Signal) error {
@@ -374,6 +455,38 @@ func (p *ProcessState) SystemTime() time.Duration {
 	return p.systemTime()
 }
 
+// MaxRSS returns the maximum resident set size of the exited process,
+// in bytes. On Unix systems this is the largest of the exited process
+// and those of its children that it waited for. It returns 0 on systems
+// that do not report it, including Windows and Plan 9.
+func (p *ProcessState) MaxRSS() int64 {
+	return p.maxRSS()
+}
+
+// MinorPageFaults returns the number of page faults serviced by the exited
+// process and its children without any I/O activity.
+// It returns 0 on systems that do not report it, including Windows and Plan 9.
+func (p *ProcessState) MinorPageFaults() int64 {
+	return p.minorPageFaults()
+}
+
+// MajorPageFaults returns the number of page faults serviced by the exited
+// process and its children that required I/O activity.
+// It returns 0 on systems that do not report it, including Windows and Plan 9.
+func (p *ProcessState) MajorPageFaults() int64 {
+	return p.majorPageFaults()
+}
+
+// ContextSwitches returns the number of context switches of the exited
+// process and its children: voluntary ones, made because the process
+// waited for a resource, and involuntary ones, made because a higher
+// priority process became runnable or the time slice was used up.
+// It returns 0, 0 on systems that do not report them, including Windows
+// and Plan 9.
+func (p *ProcessState) ContextSwitches() (voluntary, involuntary int64) {
+	return p.contextSwitches()
+}
+
 // Exited reports whether the program has exited.
 // On Unix systems this reports true if the program exited due to calling exit,
 // but false if the program terminated due to a signal.
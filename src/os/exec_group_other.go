@@ -0,0 +1,24 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build (js && wasm) || wasip1
+
+package os
+
+import (
+	"errors"
+	"syscall"
+)
+
+func sysProcAttr(attr *ProcAttr) (*syscall.SysProcAttr, error) {
+	return attr.Sys, nil
+}
+
+func (p *Process) initGroup(_ *ProcAttr, _ *syscall.SysProcAttr) error {
+	return nil
+}
+
+func (p *Process) killGroup() error {
+	return NewSyscallError("kill", errors.ErrUnsupported)
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package os
+
+import (
+	"errors"
+	"syscall"
+)
+
+// errDetachSessionGroup is returned by StartProcess when
+// ProcAttr.DetachSession is combined with a process group in ProcAttr.Sys.
+var errDetachSessionGroup = errors.New("DetachSession cannot be combined with Setpgid or Foreground")
+
+// sysProcAttr returns attr.Sys, changed to honor attr.NewProcessGroup
+// and attr.DetachSession.
+func sysProcAttr(attr *ProcAttr) (*syscall.SysProcAttr, error) {
+	if !attr.NewProcessGroup && !attr.DetachSession {
+		return attr.Sys, nil
+	}
+	var sys syscall.SysProcAttr
+	if attr.Sys != nil {
+		sys = *attr.Sys // copy
+	}
+	switch {
+	case attr.DetachSession:
+		// A session leader cannot call setpgid, so the group that
+		// Setpgid or Foreground asks for cannot be joined after
+		// setsid. The session leader leads a new process group anyway.
+		if sys.Setpgid || sys.Foreground {
+			return nil, errDetachSessionGroup
+		}
+		sys.Setsid = true
+	case !sys.Setpgid && !sys.Setsid:
+		sys.Setpgid = true
+		sys.Pgid = 0
+	}
+	return &sys, nil
+}
+
+// initGroup records the process group that p was started in by
+// StartProcess, if it was not started in ours.
+func (p *Process) initGroup(_ *ProcAttr, sys *syscall.SysProcAttr) error {
+	switch {
+	case sys == nil:
+	case sys.Setpgid || sys.Foreground:
+		pgid := sys.Pgid
+		if pgid == 0 {
+			pgid = p.Pid
+		}
+		p.group = uintptr(pgid)
+	case sys.Setsid:
+		p.group = uintptr(p.Pid)
+	}
+	return nil
+}
+
+func (p *Process) killGroup() error {
+	if p.group == 0 {
+		return errNoProcessGroup
+	}
+	// As in pidSignal, hold sigMu so that Wait cannot reap the process,
+	// freeing its ID, which is also the ID of the group, for reuse while
+	// we signal it.
+	p.sigMu.RLock()
+	defer p.sigMu.RUnlock()
+
+	switch processStatus(p.state.Load() & processStatusMask) {
+	case statusDone:
+		return ErrProcessDone
+	case statusReleased:
+		return errors.New("os: process already released")
+	}
+	return convertESRCH(syscall.Kill(-int(p.group), syscall.SIGKILL))
+}
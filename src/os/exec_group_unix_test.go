@@ -0,0 +1,95 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package syscall does not provide Getpgid on aix and solaris.
+
+//go:build unix && !aix && !solaris
+
+package os_test
+
+import (
+	"internal/testenv"
+	. "os"
+	"syscall"
+	"testing"
+)
+
+func TestStartProcessNewProcessGroup(t *testing.T) {
+	testenv.MustHaveExec(t)
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name string
+		attr ProcAttr
+	}{
+		{"NewProcessGroup", ProcAttr{NewProcessGroup: true}},
+		{"DetachSession", ProcAttr{DetachSession: true}},
+		{"DetachSessionWithSys", ProcAttr{DetachSession: true, Sys: &syscall.SysProcAttr{}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			pgid := startProcessGroup(t, &tt.attr)
+			if pgid == syscall.Getpgrp() {
+				t.Errorf("child process group is the same as ours, %d", pgid)
+			}
+		})
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		pgid := startProcessGroup(t, &ProcAttr{})
+		if want := syscall.Getpgrp(); pgid != want {
+			t.Errorf("child process group is %d, want ours, %d", pgid, want)
+		}
+	})
+}
+
+func TestStartProcessDetachSessionConflict(t *testing.T) {
+	testenv.MustHaveExec(t)
+	t.Parallel()
+
+	for _, sys := range []*syscall.SysProcAttr{
+		{Setpgid: true},
+		{Foreground: true},
+	} {
+		attr := &ProcAttr{DetachSession: true, Sys: sys}
+		p, err := StartProcess(Args[0], []string{Args[0]}, attr)
+		if err == nil {
+			p.Kill()
+			p.Wait()
+			t.Errorf("StartProcess with DetachSession and %+v succeeded, want error", *sys)
+			continue
+		}
+		if _, ok := err.(*PathError); !ok {
+			t.Errorf("StartProcess with DetachSession and %+v: error %v has type %T, want *PathError", *sys, err, err)
+		}
+	}
+}
+
+// startProcessGroup starts a child process with attr, and returns its
+// process group ID.
+func startProcessGroup(t *testing.T, attr *ProcAttr) int {
+	r, w, err := Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	attr.Env = append(Environ(), "GO_OS_TEST_DRAIN_STDIN=1")
+	attr.Files = []*File{r, nil, Stderr}
+	p, err := StartProcess(Args[0], []string{Args[0]}, attr)
+	r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pgid, err := syscall.Getpgid(p.Pid)
+	w.Close()
+	p.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attr.NewProcessGroup || attr.DetachSession {
+		if pgid != p.Pid {
+			t.Errorf("child process group is %d, want %d", pgid, p.Pid)
+		}
+	}
+	return pgid
+}
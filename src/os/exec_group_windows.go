@@ -0,0 +1,66 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/windows"
+	"syscall"
+)
+
+// sysProcAttr returns attr.Sys, with the creation flags requested by
+// attr.NewProcessGroup and attr.DetachSession added.
+func sysProcAttr(attr *ProcAttr) (*syscall.SysProcAttr, error) {
+	if !attr.NewProcessGroup && !attr.DetachSession {
+		return attr.Sys, nil
+	}
+	var sys syscall.SysProcAttr
+	if attr.Sys != nil {
+		sys = *attr.Sys // copy
+	}
+	sys.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+	if attr.DetachSession {
+		sys.CreationFlags |= windows.DETACHED_PROCESS
+	}
+	return &sys, nil
+}
+
+// initGroup assigns p to a new Job Object if attr.NewProcessGroup or
+// attr.DetachSession is set. If that fails, p is terminated and
+// released.
+func (p *Process) initGroup(attr *ProcAttr, _ *syscall.SysProcAttr) error {
+	if !attr.NewProcessGroup && !attr.DetachSession {
+		return nil
+	}
+	job, err := windows.CreateJobObject(nil, nil)
+	if err == nil {
+		err = windows.AssignProcessToJobObject(job, syscall.Handle(p.handle))
+		if err != nil {
+			syscall.CloseHandle(job)
+		}
+	}
+	if err != nil {
+		syscall.TerminateProcess(syscall.Handle(p.handle), 1)
+		p.Release()
+		return NewSyscallError("AssignProcessToJobObject", err)
+	}
+	p.group = uintptr(job)
+	return nil
+}
+
+func (p *Process) killGroup() error {
+	if p.group == 0 {
+		return errNoProcessGroup
+	}
+	_, status := p.handleTransientAcquire()
+	switch status {
+	case statusDone:
+		return ErrProcessDone
+	case statusReleased:
+		return syscall.EINVAL
+	}
+	defer p.handleTransientRelease()
+
+	return NewSyscallError("TerminateJobObject", windows.TerminateJobObject(syscall.Handle(p.group), 1))
+}
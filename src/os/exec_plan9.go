@@ -5,6 +5,8 @@
 package os
 
 import (
+	"context"
+	"errors"
 	"internal/itoa"
 	"runtime"
 	"syscall"
@@ -89,6 +91,17 @@ func (p *Process) wait() (ps *ProcessState, err error) {
 	return ps, nil
 }
 
+// waitContext is not supported: reading the wait message of a
+// process also reaps it, so there is no way to give up waiting and
+// still leave the process for a later Wait.
+func (p *Process) waitContext(ctx context.Context) (*ProcessState, error) {
+	return nil, NewSyscallError("wait", errors.ErrUnsupported)
+}
+
+func (p *Process) killGroup() error {
+	return NewSyscallError("kill", errors.ErrUnsupported)
+}
+
 func (p *Process) release() error {
 	p.Pid = -1
 
@@ -105,6 +118,16 @@ func findProcess(pid int) (p *Process, err error) {
 	return newPIDProcess(pid), nil
 }
 
+func findProcessLive(pid int) (*Process, error) {
+	if _, err := Stat("/proc/" + itoa.Itoa(pid)); err != nil {
+		if IsNotExist(err) {
+			return nil, ErrProcessDone
+		}
+		return nil, err
+	}
+	return newPIDProcess(pid), nil
+}
+
 // ProcessState stores information about a process, as reported by Wait.
 type ProcessState struct {
 	pid    int              // The process's id.
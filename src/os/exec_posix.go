@@ -35,7 +35,11 @@ func startProcess(name string, argv []string, attr *ProcAttr) (p *Process, err e
 		}
 	}
 
-	attrSys, shouldDupPidfd := ensurePidfd(attr.Sys)
+	sys, err := sysProcAttr(attr)
+	if err != nil {
+		return nil, &PathError{Op: "fork/exec", Path: name, Err: err}
+	}
+	attrSys, shouldDupPidfd := ensurePidfd(sys)
 	sysattr := &syscall.ProcAttr{
 		Dir: attr.Dir,
 		Env: attr.Env,
@@ -66,11 +70,17 @@ func startProcess(name string, argv []string, attr *ProcAttr) (p *Process, err e
 		var ok bool
 		h, ok = getPidfd(sysattr.Sys, shouldDupPidfd)
 		if !ok {
-			return newPIDProcess(pid), nil
+			p = newPIDProcess(pid)
 		}
 	}
+	if p == nil {
+		p = newHandleProcess(pid, h)
+	}
 
-	return newHandleProcess(pid, h), nil
+	if err := p.initGroup(attr, sysattr.Sys); err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
 func (p *Process) kill() error {
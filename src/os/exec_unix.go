@@ -7,7 +7,9 @@
 package os
 
 import (
+	"context"
 	"errors"
+	"internal/syscall/unix"
 	"runtime"
 	"syscall"
 	"time"
@@ -83,6 +85,105 @@ func (p *Process) pidWait() (*ProcessState, error) {
 	}, nil
 }
 
+func (p *Process) waitContext(ctx context.Context) (*ProcessState, error) {
+	switch p.mode {
+	case modeHandle:
+		// pidfd
+		return p.pidfdWaitContext(ctx)
+	case modePID:
+		// Regular PID
+		return p.pidWaitContext(ctx)
+	default:
+		panic("unreachable")
+	}
+}
+
+// pidWaitContext is like pidWait, but returns ctx.Err() without reaping
+// the process if ctx is done before the process exits.
+func (p *Process) pidWaitContext(ctx context.Context) (*ProcessState, error) {
+	switch p.pidStatus() {
+	case statusReleased:
+		return nil, syscall.EINVAL
+	}
+	if !canBlockUntilWaitable {
+		return p.pidPollWait(ctx)
+	}
+
+	// Block in a separate goroutine, which is left behind until the
+	// process exits if ctx is done first. blockUntilWaitable does not
+	// reap the process, so a later Wait still sees it.
+	waitable := make(chan error, 1)
+	go func() {
+		_, err := p.blockUntilWaitable()
+		waitable <- err
+	}()
+	select {
+	case err := <-waitable:
+		if err != nil {
+			return nil, err
+		}
+		return p.pidWait()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// pidPollWait is pidWaitContext for systems where blockUntilWaitable
+// is not implemented. It polls the process with WNOHANG, backing off
+// up to maxPollWaitInterval between attempts.
+func (p *Process) pidPollWait(ctx context.Context) (*ProcessState, error) {
+	const maxPollWaitInterval = 100 * time.Millisecond
+	interval := time.Millisecond
+	t := time.NewTimer(interval)
+	defer t.Stop()
+	for {
+		if ps, err := p.pidTryWait(); ps != nil || err != nil {
+			return ps, err
+		}
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		interval = min(2*interval, maxPollWaitInterval)
+		t.Reset(interval)
+	}
+}
+
+// pidTryWait reaps the process if it has exited, and returns a nil
+// ProcessState and error if it has not.
+func (p *Process) pidTryWait() (*ProcessState, error) {
+	// Hold sigMu across the wait so that Process.pidSignal cannot send
+	// a signal to a reused PID after the process is reaped.
+	p.sigMu.Lock()
+	defer p.sigMu.Unlock()
+
+	var (
+		status syscall.WaitStatus
+		rusage syscall.Rusage
+		pid1   int
+		e      error
+	)
+	for {
+		pid1, e = syscall.Wait4(p.Pid, &status, unix.WNOHANG, &rusage)
+		if e != syscall.EINTR {
+			break
+		}
+	}
+	if e != nil {
+		return nil, NewSyscallError("wait", e)
+	}
+	if pid1 == 0 {
+		return nil, nil
+	}
+	p.pidDeactivate(statusDone)
+	return &ProcessState{
+		pid:    pid1,
+		status: status,
+		rusage: &rusage,
+	}, nil
+}
+
 func (p *Process) signal(sig Signal) error {
 	s, ok := sig.(syscall.Signal)
 	if !ok {
@@ -169,6 +270,30 @@ func findProcess(pid int) (p *Process, err error) {
 	return newHandleProcess(pid, h), nil
 }
 
+func findProcessLive(pid int) (*Process, error) {
+	if pid <= 0 {
+		// kill(2) would signal a group of processes instead.
+		return nil, syscall.EINVAL
+	}
+	h, err := pidfdFind(pid)
+	switch err {
+	case nil:
+		return newHandleProcess(pid, h), nil
+	case ErrProcessDone:
+		return nil, ErrProcessDone
+	}
+	// Fall back to using the PID, having checked that it exists.
+	// EPERM means that the process exists, but that we are not
+	// allowed to signal it.
+	if err := syscall.Kill(pid, 0); err != nil && err != syscall.EPERM {
+		if err == syscall.ESRCH {
+			return nil, ErrProcessDone
+		}
+		return nil, NewSyscallError("kill", err)
+	}
+	return newPIDProcess(pid), nil
+}
+
 func (p *ProcessState) userTime() time.Duration {
 	return time.Duration(p.rusage.Utime.Nano()) * time.Nanosecond
 }
@@ -87,3 +87,27 @@ func TestProcessBadPID(t *testing.T) {
 		t.Error("p.Signal succeeded unexpectedly")
 	}
 }
+
+func TestFindProcessLive(t *testing.T) {
+	p, err := FindProcessLive(Getpid())
+	if err != nil {
+		t.Fatalf("FindProcessLive(Getpid()): %v", err)
+	}
+	if err := p.Signal(syscall.Signal(0)); err != nil {
+		t.Errorf("Signal(0) to self: %v", err)
+	}
+	if err := p.Release(); err != nil {
+		t.Errorf("Release: %v", err)
+	}
+
+	testenv.MustHaveExec(t)
+	cmd := testenv.Command(t, Args[0], "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	// The child has been reaped, so unless its pid has already been
+	// reused, there is no such process any more.
+	if _, err := FindProcessLive(cmd.Process.Pid); err != ErrProcessDone {
+		t.Errorf("FindProcessLive of reaped child: got %v, want %v", err, ErrProcessDone)
+	}
+}
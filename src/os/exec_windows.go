@@ -5,6 +5,7 @@
 package os
 
 import (
+	"context"
 	"errors"
 	"internal/syscall/windows"
 	"runtime"
@@ -48,6 +49,44 @@ func (p *Process) wait() (ps *ProcessState, err error) {
 	return &ProcessState{p.Pid, syscall.WaitStatus{ExitCode: ec}, &u}, nil
 }
 
+// waitContext waits in a separate goroutine for the process handle to
+// be signaled, and collects the process status with wait once it is.
+// If ctx is done first, the goroutine keeps its reference to the
+// handle until the process exits.
+func (p *Process) waitContext(ctx context.Context) (*ProcessState, error) {
+	handle, status := p.handleTransientAcquire()
+	switch status {
+	case statusDone:
+		return nil, ErrProcessDone
+	case statusReleased:
+		return nil, syscall.EINVAL
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		defer p.handleTransientRelease()
+		s, e := syscall.WaitForSingleObject(syscall.Handle(handle), syscall.INFINITE)
+		switch s {
+		case syscall.WAIT_OBJECT_0:
+			exited <- nil
+		case syscall.WAIT_FAILED:
+			exited <- NewSyscallError("WaitForSingleObject", e)
+		default:
+			exited <- errors.New("os: unexpected result from WaitForSingleObject")
+		}
+	}()
+
+	select {
+	case err := <-exited:
+		if err != nil {
+			return nil, err
+		}
+		return p.wait()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 func (p *Process) signal(sig Signal) error {
 	handle, status := p.handleTransientAcquire()
 	switch status {
@@ -90,6 +129,9 @@ func (p *Process) release() error {
 
 func (p *Process) closeHandle() {
 	syscall.CloseHandle(syscall.Handle(p.handle))
+	if p.group != 0 {
+		syscall.CloseHandle(syscall.Handle(p.group))
+	}
 }
 
 func findProcess(pid int) (p *Process, err error) {
@@ -102,6 +144,16 @@ func findProcess(pid int) (p *Process, err error) {
 	return newHandleProcess(pid, uintptr(h)), nil
 }
 
+func findProcessLive(pid int) (*Process, error) {
+	p, err := findProcess(pid)
+	if err != nil && underlyingErrorIs(err, windows.ERROR_INVALID_PARAMETER) {
+		// OpenProcess reports a pid that does not exist as an
+		// invalid parameter.
+		return nil, ErrProcessDone
+	}
+	return p, err
+}
+
 func init() {
 	cmd := windows.UTF16PtrToString(syscall.GetCommandLine())
 	if len(cmd) == 0 {
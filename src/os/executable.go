@@ -4,6 +4,8 @@
 
 package os
 
+import "internal/filepathlite"
+
 // Executable returns the path name for the executable that started
 // the current process. There is no guarantee that the path is still
 // pointing to the correct executable. If a symlink was used to start
@@ -18,3 +20,36 @@ package os
 func Executable() (string, error) {
 	return executable()
 }
+
+// ExecutableInvoked returns the path name by which the current process
+// was started. Unlike [Executable], it does not resolve symlinks: if the
+// process was started through a symlink, such as the command names of a
+// multi-call binary, ExecutableInvoked returns the path of the symlink.
+//
+// The result is derived from Args[0]. On Unix systems, if Args[0]
+// contains no slash, it is searched for in the directories named by the
+// PATH environment variable, as a shell would have done. On other
+// systems, ExecutableInvoked falls back to Executable in that case.
+//
+// The result may be a relative path. It is interpreted relative to the
+// working directory at the start of the process, so it is unreliable
+// once the process has changed directories. Args[0] is chosen by the
+// parent process and might not name the executable at all.
+func ExecutableInvoked() (string, error) {
+	return executableInvoked()
+}
+
+// ExecutableDir returns the directory containing the executable that
+// started the current process, as reported by [Executable]. The same
+// caveats about symlinks apply.
+//
+// ExecutableDir returns an absolute path unless an error occurred, in
+// which case it returns an empty path, rather than the "." that
+// filepath.Dir would make of an empty Executable result.
+func ExecutableDir() (string, error) {
+	exe, err := executable()
+	if err != nil {
+		return "", err
+	}
+	return filepathlite.Dir(exe), nil
+}
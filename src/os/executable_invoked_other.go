@@ -0,0 +1,18 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !unix
+
+package os
+
+func executableInvoked() (string, error) {
+	if len(Args) > 0 {
+		for i := 0; i < len(Args[0]); i++ {
+			if IsPathSeparator(Args[0][i]) {
+				return Args[0], nil
+			}
+		}
+	}
+	return executable()
+}
@@ -60,45 +60,3 @@ func executable() (string, error) {
 	}
 	return "", ErrNotExist
 }
-
-// isExecutable returns an error if a given file is not an executable.
-func isExecutable(path string) error {
-	stat, err := Stat(path)
-	if err != nil {
-		return err
-	}
-	mode := stat.Mode()
-	if !mode.IsRegular() {
-		return ErrPermission
-	}
-	if (mode & 0111) == 0 {
-		return ErrPermission
-	}
-	return nil
-}
-
-// splitPathList splits a path list.
-// This is based on genSplit from strings/strings.go
-func splitPathList(pathList string) []string {
-	if pathList == "" {
-		return nil
-	}
-	n := 1
-	for i := 0; i < len(pathList); i++ {
-		if pathList[i] == PathListSeparator {
-			n++
-		}
-	}
-	start := 0
-	a := make([]string, n)
-	na := 0
-	for i := 0; i+1 <= len(pathList) && na+1 < n; i++ {
-		if pathList[i] == PathListSeparator {
-			a[na] = pathList[start:i]
-			na++
-			start = i + 1
-		}
-	}
-	a[na] = pathList[start:]
-	return a[:na+1]
-}
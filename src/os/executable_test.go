@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"internal/testenv"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -88,6 +89,57 @@ func init() {
 	}
 }
 
+func TestExecutableDir(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "exedir.go")
+	exe := filepath.Join(dir, "bin", "exedir.exe")
+	if err := os.WriteFile(src, []byte(testExecutableDir), 0666); err != nil {
+		t.Fatal(err)
+	}
+	out, err := testenv.Command(t, testenv.GoToolPath(t), "build", "-o", exe, src).CombinedOutput()
+	t.Logf("build output:\n%s", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Run the binary from somewhere else, by a relative path.
+	cmd := testenv.Command(t, filepath.Join("bin", "exedir.exe"))
+	cmd.Dir = dir
+	cmd.Path = filepath.Join("bin", "exedir.exe")
+	out, err = cmd.Output()
+	if err != nil {
+		t.Fatalf("exec output: %s: %v", out, err)
+	}
+	got := string(out)
+	if !filepath.IsAbs(got) {
+		t.Fatalf("ExecutableDir returned %q, want an absolute path", got)
+	}
+	if !sameFile(got, filepath.Dir(exe)) {
+		t.Fatalf("ExecutableDir returned %q, not the same directory as %q", got, filepath.Dir(exe))
+	}
+}
+
+const testExecutableDir = `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	os.Chdir(os.TempDir())
+	dir, err := os.ExecutableDir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Print(dir)
+}
+`
+
 func TestExecutableDeleted(t *testing.T) {
 	testenv.MustHaveGoBuild(t)
 	switch runtime.GOOS {
@@ -153,3 +205,79 @@ func main() {
 	}
 }
 `
+
+func TestExecutableInvoked(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	testenv.MustHaveSymlink(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows; Args[0] is not found in $PATH")
+	}
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "invoked.go")
+	exe := filepath.Join(dir, "invoked.exe")
+	if err := os.WriteFile(src, []byte(testExecutableInvoked), 0666); err != nil {
+		t.Fatal(err)
+	}
+	out, err := testenv.Command(t, testenv.GoToolPath(t), "build", "-o", exe, src).CombinedOutput()
+	t.Logf("build output:\n%s", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin := filepath.Join(dir, "bin")
+	if err := os.Mkdir(bin, 0777); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(bin, "applet")
+	if err := os.Symlink(exe, link); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(cmd *exec.Cmd) string {
+		t.Helper()
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("exec output: %s: %v", out, err)
+		}
+		return string(out)
+	}
+
+	// Run the binary by the path of the symlink.
+	if got := run(testenv.Command(t, link)); got != link {
+		t.Errorf("ExecutableInvoked through %s = %q, want %q", link, got, link)
+	}
+
+	// Run the binary by the name of the symlink, found in $PATH
+	// after a file of the same name that is not executable.
+	noexec := filepath.Join(dir, "noexec")
+	if err := os.Mkdir(noexec, 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(noexec, "applet"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	cmd := testenv.Command(t, link)
+	cmd.Args[0] = "applet"
+	cmd.Env = append(cmd.Environ(), "PATH="+noexec+string(os.PathListSeparator)+bin)
+	if got := run(cmd); got != link {
+		t.Errorf("ExecutableInvoked through $PATH = %q, want %q", got, link)
+	}
+}
+
+const testExecutableInvoked = `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	exe, err := os.ExecutableInvoked()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Print(exe)
+}
+`
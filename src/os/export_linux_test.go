@@ -7,8 +7,13 @@ package os
 var (
 	PollCopyFileRangeP  = &pollCopyFileRange
 	PollSpliceFile      = &pollSplice
+	UnixFicloneP        = &unixFiclone
 	GetPollFDAndNetwork = getPollFDAndNetwork
 	CheckPidfdOnce      = checkPidfdOnce
+	Openat2Unsupported  = &openat2Unsupported
+	StatxUnsupported    = &statxUnsupported
+	NewPIDProcess       = newPIDProcess
+	ChmodAtNofollow     = chmodAtNofollow
 )
 
 const StatusDone = statusDone
@@ -11,6 +11,10 @@ var LstatP = &lstat
 var ErrWriteAtInAppendMode = errWriteAtInAppendMode
 var TestingForceReadDirLstat = &testingForceReadDirLstat
 var ErrPatternHasSeparator = errPatternHasSeparator
+var ErrPathEscapes = errPathEscapes
+var RenameAcrossFSRename = &renameAcrossFSRename
+
+const CopyProgressChunk = copyProgressChunk
 
 func init() {
 	checkWrapErr = true
@@ -7,3 +7,5 @@
 package os
 
 var SplitPath = splitPath
+var LookupHostsFQDN = lookupHostsFQDN
+var LookupGroupNames = lookupGroupNames
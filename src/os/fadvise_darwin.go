@@ -0,0 +1,45 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"errors"
+	"internal/syscall/unix"
+	"runtime"
+	"syscall"
+)
+
+func (f *File) fadvise(offset, length int64, advice int) error {
+	if advice == FadviceDontNeed {
+		return errors.ErrUnsupported
+	}
+	var err error
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		switch advice {
+		case FadviceWillNeed:
+			if offset < 0 || length < 0 {
+				err = syscall.EINVAL
+				return
+			}
+			// F_RDADVISE takes a 32-bit count, and has no way
+			// to say "to the end of the file".
+			const maxInt32 = 1<<31 - 1
+			count := int32(maxInt32)
+			if length > 0 && length < maxInt32 {
+				count = int32(length)
+			}
+			err = unix.RdAdvise(int(fd), offset, count)
+		case FadviceRandom:
+			err = unix.RdAhead(int(fd), false)
+		default:
+			err = unix.RdAhead(int(fd), true)
+		}
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		return cerr
+	}
+	return err
+}
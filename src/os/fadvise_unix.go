@@ -0,0 +1,34 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build freebsd || linux
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+)
+
+var fadviseAdvice = [...]int{
+	FadviceNormal:     unix.POSIX_FADV_NORMAL,
+	FadviceSequential: unix.POSIX_FADV_SEQUENTIAL,
+	FadviceRandom:     unix.POSIX_FADV_RANDOM,
+	FadviceWillNeed:   unix.POSIX_FADV_WILLNEED,
+	FadviceDontNeed:   unix.POSIX_FADV_DONTNEED,
+}
+
+func (f *File) fadvise(offset, length int64, advice int) error {
+	var err error
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		err = ignoringEINTR(func() error {
+			return unix.PosixFadvise(int(fd), offset, length, fadviseAdvice[advice])
+		})
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		return cerr
+	}
+	return err
+}
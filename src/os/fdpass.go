@@ -0,0 +1,48 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "syscall"
+
+// SendFiles sends the descriptors of files over conn, which must be a
+// Unix domain socket such as a *net.UnixConn, or a [File] for one.
+// The files remain open in the calling process; the receiving process
+// gets duplicates of them, as if by dup(2), which it can obtain with
+// [ReceiveFiles].
+//
+// SendFiles sends a single byte of ordinary data alongside the
+// descriptors, which ReceiveFiles consumes.
+//
+// On systems other than Unix, SendFiles returns an error wrapping
+// [errors.ErrUnsupported].
+func SendFiles(conn syscall.Conn, files ...*File) error {
+	if len(files) == 0 {
+		return nil
+	}
+	for _, f := range files {
+		if f == nil {
+			return ErrInvalid
+		}
+	}
+	return sendFiles(conn, files)
+}
+
+// ReceiveFiles receives up to n file descriptors sent over conn by
+// [SendFiles], and returns them as Files with empty names. The
+// received descriptors are close-on-exec. conn must be a Unix domain
+// socket such as a *net.UnixConn, or a [File] for one.
+//
+// If the message carried more than n descriptors, ReceiveFiles closes
+// the ones it did receive and returns an error. At the end of the
+// stream, ReceiveFiles returns [io.EOF].
+//
+// On systems other than Unix, ReceiveFiles returns an error wrapping
+// [errors.ErrUnsupported].
+func ReceiveFiles(conn syscall.Conn, n int) ([]*File, error) {
+	if n < 1 {
+		return nil, ErrInvalid
+	}
+	return receiveFiles(conn, n)
+}
@@ -0,0 +1,13 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build dragonfly || freebsd || linux || netbsd || openbsd
+
+package os
+
+import "syscall"
+
+// msgCmsgCloexec is the recvmsg flag that makes received descriptors
+// close-on-exec, or 0 if the system has none.
+const msgCmsgCloexec = syscall.MSG_CMSG_CLOEXEC
@@ -0,0 +1,20 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !unix
+
+package os
+
+import (
+	"errors"
+	"syscall"
+)
+
+func sendFiles(conn syscall.Conn, files []*File) error {
+	return NewSyscallError("sendmsg", errors.ErrUnsupported)
+}
+
+func receiveFiles(conn syscall.Conn, n int) ([]*File, error) {
+	return nil, NewSyscallError("recvmsg", errors.ErrUnsupported)
+}
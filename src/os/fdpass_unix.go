@@ -0,0 +1,153 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package os
+
+import (
+	"errors"
+	"io"
+	"syscall"
+)
+
+func sendFiles(conn syscall.Conn, files []*File) error {
+	rc, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	// Send duplicates, so that the files may be closed concurrently
+	// without the descriptors being reused under us.
+	fds := make([]int, 0, len(files))
+	defer func() {
+		for _, fd := range fds {
+			syscall.Close(fd)
+		}
+	}()
+	for _, f := range files {
+		if err := f.checkValid("sendmsg"); err != nil {
+			return err
+		}
+		fd, _, err := f.pfd.Dup()
+		if err != nil {
+			return f.wrapErr("sendmsg", err)
+		}
+		fds = append(fds, fd)
+	}
+
+	rights := syscall.UnixRights(fds...)
+	var serr error
+	err = rc.Write(func(s uintptr) bool {
+		serr = ignoringEINTR(func() error {
+			return syscall.Sendmsg(int(s), []byte{0}, rights, nil, 0)
+		})
+		return serr != syscall.EAGAIN
+	})
+	if err == nil {
+		err = serr
+	}
+	if err != nil {
+		return NewSyscallError("sendmsg", err)
+	}
+	return nil
+}
+
+func receiveFiles(conn syscall.Conn, n int) ([]*File, error) {
+	rc, err := conn.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		buf        [1]byte
+		oob        = make([]byte, syscall.CmsgSpace(n*4))
+		nr, rflags int
+		fds        []int
+		rerr, perr error
+	)
+	err = rc.Read(func(s uintptr) bool {
+		if msgCmsgCloexec == 0 {
+			// Wait for the message before taking ForkLock, so that
+			// a blocking recvmsg does not hold up StartProcess.
+			// Peeking leaves the message and its descriptors queued.
+			// On a non-blocking socket it fails with EAGAIN, and
+			// rc.Read waits for the socket in the poller.
+			rerr = ignoringEINTR(func() error {
+				_, _, _, _, err := syscall.Recvmsg(int(s), buf[:], nil, syscall.MSG_PEEK)
+				return err
+			})
+			if rerr != nil {
+				return rerr != syscall.EAGAIN
+			}
+			// Keep the received descriptors from leaking into a
+			// child started before they are marked close-on-exec.
+			syscall.ForkLock.RLock()
+			defer syscall.ForkLock.RUnlock()
+		}
+		var oobn int
+		rerr = ignoringEINTR(func() error {
+			var err error
+			nr, oobn, rflags, _, err = syscall.Recvmsg(int(s), buf[:], oob, msgCmsgCloexec)
+			return err
+		})
+		if rerr == nil {
+			fds, perr = parseUnixRights(oob[:oobn])
+			if msgCmsgCloexec == 0 {
+				for _, fd := range fds {
+					syscall.CloseOnExec(fd)
+				}
+			}
+		}
+		return rerr != syscall.EAGAIN
+	})
+	if err == nil {
+		err = rerr
+	}
+	if err != nil {
+		return nil, NewSyscallError("recvmsg", err)
+	}
+	// The control message buffer may have room for more than n
+	// descriptors because of alignment.
+	if perr == nil && (rflags&syscall.MSG_CTRUNC != 0 || len(fds) > n) {
+		perr = errors.New("os: received more files than requested")
+	}
+	if perr != nil {
+		for _, fd := range fds {
+			syscall.Close(fd)
+		}
+		return nil, NewSyscallError("recvmsg", perr)
+	}
+	if nr == 0 && len(fds) == 0 {
+		return nil, io.EOF
+	}
+
+	files := make([]*File, len(fds))
+	for i, fd := range fds {
+		files[i] = NewFile(uintptr(fd), "")
+	}
+	return files, nil
+}
+
+// parseUnixRights returns the descriptors carried by the SCM_RIGHTS
+// socket control messages in oob. If it fails, it returns the descriptors it parsed so
+// far, so that they can be closed.
+func parseUnixRights(oob []byte) ([]int, error) {
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return nil, err
+	}
+	var fds []int
+	for i := range msgs {
+		if h := msgs[i].Header; h.Level != syscall.SOL_SOCKET || h.Type != syscall.SCM_RIGHTS {
+			continue
+		}
+		rights, err := syscall.ParseUnixRights(&msgs[i])
+		if err != nil {
+			return fds, err
+		}
+		fds = append(fds, rights...)
+	}
+	return fds, nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package os_test
+
+import (
+	"internal/syscall/unix"
+	"io"
+	. "os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSendReceiveFiles(t *testing.T) {
+	t.Parallel()
+
+	const data = "passed by descriptor"
+	name := filepath.Join(t.TempDir(), "file")
+	if err := WriteFile(name, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	send := NewFile(uintptr(fds[0]), "send")
+	defer send.Close()
+	recv := NewFile(uintptr(fds[1]), "recv")
+	defer recv.Close()
+
+	type result struct {
+		files []*File
+		err   error
+	}
+	c := make(chan result)
+	go func() {
+		files, err := ReceiveFiles(recv, 2)
+		c <- result{files, err}
+	}()
+
+	if err := SendFiles(send, f); err != nil {
+		t.Fatalf("SendFiles: %v", err)
+	}
+	res := <-c
+	if res.err != nil {
+		t.Fatalf("ReceiveFiles: %v", res.err)
+	}
+	if len(res.files) != 1 {
+		t.Fatalf("ReceiveFiles returned %d files, want 1", len(res.files))
+	}
+	got := res.files[0]
+	defer got.Close()
+
+	// The sending side can close its copy without affecting ours.
+	f.Close()
+	b, err := io.ReadAll(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != data {
+		t.Errorf("read %q through received file, want %q", b, data)
+	}
+
+	flags, err := unix.Fcntl(int(got.Fd()), syscall.F_GETFD, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flags&syscall.FD_CLOEXEC == 0 {
+		t.Errorf("received descriptor is not close-on-exec")
+	}
+
+	send.Close()
+	if _, err := ReceiveFiles(recv, 1); err != io.EOF {
+		t.Errorf("ReceiveFiles after peer closed: got %v, want %v", err, io.EOF)
+	}
+}
+
+func TestSendFilesTooMany(t *testing.T) {
+	t.Parallel()
+
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	send := NewFile(uintptr(fds[0]), "send")
+	defer send.Close()
+	recv := NewFile(uintptr(fds[1]), "recv")
+	defer recv.Close()
+
+	r, w, err := Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	if err := SendFiles(send, nil); err != ErrInvalid {
+		t.Errorf("SendFiles(nil): got %v, want %v", err, ErrInvalid)
+	}
+	if err := SendFiles(send, r, w); err != nil {
+		t.Fatalf("SendFiles: %v", err)
+	}
+	if files, err := ReceiveFiles(recv, 1); err == nil {
+		t.Errorf("ReceiveFiles of 2 files with n = 1 succeeded, returning %d files", len(files))
+	}
+}
+
+// ReceiveFiles must not hold syscall.ForkLock while it waits for a
+// message, or it would stop every StartProcess until one arrives.
+func TestReceiveFilesWaitForkLock(t *testing.T) {
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	send := NewFile(uintptr(fds[0]), "send")
+	defer send.Close()
+	recv := NewFile(uintptr(fds[1]), "recv")
+	defer recv.Close()
+
+	c := make(chan error)
+	go func() {
+		_, err := ReceiveFiles(recv, 1)
+		c <- err
+	}()
+	// Give ReceiveFiles time to start waiting.
+	time.Sleep(10 * time.Millisecond)
+
+	locked := make(chan struct{})
+	go func() {
+		syscall.ForkLock.Lock()
+		syscall.ForkLock.Unlock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+	case <-time.After(10 * time.Second):
+		t.Error("ForkLock held while ReceiveFiles waits")
+	}
+
+	// Let ReceiveFiles return.
+	if err := send.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-c; err != io.EOF {
+		t.Errorf("ReceiveFiles after close: got %v, want %v", err, io.EOF)
+	}
+}
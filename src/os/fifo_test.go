@@ -7,6 +7,7 @@
 package os_test
 
 import (
+	"context"
 	"errors"
 	"internal/syscall/unix"
 	"internal/testenv"
@@ -14,6 +15,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"sync"
 	"syscall"
@@ -261,3 +263,248 @@ func TestFIFONonBlockingEOF(t *testing.T) {
 		}
 	}
 }
+
+func TestOpenFileNonblockFifo(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "fifo")
+	if err := os.Mkfifo(name, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Opening the read end with no writer must not block.
+	opened := make(chan *os.File, 1)
+	go func() {
+		r, err := os.OpenFileNonblock(name, os.O_RDONLY, 0)
+		if err != nil {
+			t.Error(err)
+		}
+		opened <- r
+	}()
+	var r *os.File
+	select {
+	case r = <-opened:
+	case <-time.After(10 * time.Second):
+		t.Fatal("OpenFileNonblock of a FIFO with no writer blocked")
+	}
+	if r == nil {
+		return
+	}
+	defer r.Close()
+
+	// With no writer, the FIFO is at end of file.
+	if n, err := r.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Errorf("Read with no writer = %d, %v; want 0, EOF", n, err)
+	}
+
+	if runtime.GOOS == "darwin" || runtime.GOOS == "ios" {
+		// FIFOs are not added to the poller on Darwin; see newFile.
+		return
+	}
+
+	// Reads honor deadlines.
+	if err := r.SetReadDeadline(time.Now().Add(10 * time.Millisecond)); err != nil {
+		t.Fatalf("SetReadDeadline: %v", err)
+	}
+	w, err := os.OpenFile(name, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if _, err := r.Read(make([]byte, 1)); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("Read with no data = %v, want ErrDeadlineExceeded", err)
+	}
+
+	// Data written after the read starts waiting is seen.
+	if err := r.SetReadDeadline(time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		io.WriteString(w, "x")
+	}()
+	buf := make([]byte, 1)
+	if n, err := r.Read(buf); n != 1 || err != nil || buf[0] != 'x' {
+		t.Errorf("Read = %d, %v, %q; want 1, nil, %q", n, err, buf[:n], "x")
+	}
+}
+
+func TestMkfifo(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "fifo")
+	if err := os.Mkfifo(name, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode()&fs.ModeType != fs.ModeNamedPipe {
+		t.Errorf("Stat(%q).Mode() = %v; want ModeNamedPipe", name, fi.Mode())
+	}
+
+	var pe *os.PathError
+	if err := os.Mkfifo(name, 0o600); !errors.As(err, &pe) || pe.Op != "mkfifo" || !errors.Is(err, fs.ErrExist) {
+		t.Errorf("Mkfifo of existing file: got %v, want mkfifo PathError wrapping ErrExist", err)
+	}
+
+	const msg = "hello, fifo"
+	done := make(chan error, 1)
+	go func() {
+		w, err := os.OpenFile(name, os.O_WRONLY, 0)
+		if err != nil {
+			done <- err
+			return
+		}
+		_, err = io.WriteString(w, msg)
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+		done <- err
+	}()
+
+	r, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != msg {
+		t.Errorf("read %q from FIFO; want %q", got, msg)
+	}
+}
+
+func TestMknodFIFO(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "fifo")
+	if err := os.Mknod(name, fs.ModeNamedPipe|0o600, 0); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode()&fs.ModeType != fs.ModeNamedPipe {
+		t.Errorf("Stat(%q).Mode() = %v; want ModeNamedPipe", name, fi.Mode())
+	}
+
+	var pe *os.PathError
+	if err := os.Mknod(name, fs.ModeNamedPipe|0o600, 0); !errors.As(err, &pe) || pe.Op != "mknod" || !errors.Is(err, fs.ErrExist) {
+		t.Errorf("Mknod of existing file: got %v, want mknod PathError wrapping ErrExist", err)
+	}
+
+	for _, mode := range []fs.FileMode{0o600, fs.ModeDir | 0o700, fs.ModeSymlink | 0o777} {
+		name := filepath.Join(dir, "bad")
+		if err := os.Mknod(name, mode, 0); !errors.Is(err, syscall.EINVAL) {
+			t.Errorf("Mknod with mode %v: got %v, want EINVAL", mode, err)
+		}
+	}
+}
+
+func TestMknodDevice(t *testing.T) {
+	t.Parallel()
+
+	// Copy /dev/null: this only succeeds with privileges, so check
+	// that the failure otherwise is a permission error.
+	fi, err := os.Stat("/dev/null")
+	if err != nil {
+		t.Skip(err)
+	}
+	st := fi.Sys().(*syscall.Stat_t)
+	name := filepath.Join(t.TempDir(), "null")
+	err = os.Mknod(name, fi.Mode()&(fs.ModeType|fs.ModePerm), uint64(st.Rdev))
+	if err != nil {
+		if !errors.Is(err, fs.ErrPermission) {
+			t.Fatalf("Mknod: got %v, want nil or a permission error", err)
+		}
+		t.Skipf("Mknod: %v", err)
+	}
+	fi2, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi2.Mode()&fs.ModeType != fi.Mode()&fs.ModeType {
+		t.Errorf("Stat(%q).Mode() = %v; want type of %v", name, fi2.Mode(), fi.Mode())
+	}
+	if rdev := fi2.Sys().(*syscall.Stat_t).Rdev; rdev != st.Rdev {
+		t.Errorf("Stat(%q) device = %#x; want %#x", name, rdev, st.Rdev)
+	}
+}
+
+func TestMkdev(t *testing.T) {
+	// The device numbers of /dev/null.
+	var major, minor uint32
+	switch runtime.GOOS {
+	case "linux":
+		major, minor = 1, 3
+	case "darwin", "ios":
+		major, minor = 3, 2
+	default:
+		t.Skipf("no known device number for /dev/null on %s", runtime.GOOS)
+	}
+	fi, err := os.Stat("/dev/null")
+	if err != nil {
+		t.Skip(err)
+	}
+	rdev := uint64(fi.Sys().(*syscall.Stat_t).Rdev)
+	if got := os.Mkdev(major, minor); got != rdev {
+		t.Errorf("Mkdev(%d, %d) = %#x; want %#x, the device number of /dev/null", major, minor, got, rdev)
+	}
+}
+
+func TestReadFileContextFIFO(t *testing.T) {
+	t.Parallel()
+
+	fifoName := filepath.Join(t.TempDir(), "fifo")
+	if err := syscall.Mkfifo(fifoName, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The writer sends some data and then stalls, leaving the
+	// reader blocked until the context is canceled.
+	written := make(chan *os.File, 1)
+	go func() {
+		w, err := os.OpenFile(fifoName, os.O_WRONLY, 0)
+		if err != nil {
+			t.Error(err)
+			written <- nil
+			return
+		}
+		if _, err := w.Write([]byte("partial")); err != nil {
+			t.Error(err)
+		}
+		written <- w
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		if w := <-written; w != nil {
+			// Keep the writer open until the reader has returned.
+			defer w.Close()
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+			<-done
+		}
+	}()
+
+	data, err := os.ReadFileContext(ctx, fifoName)
+	close(done)
+	if err != context.Canceled {
+		t.Fatalf("ReadFileContext of stalled FIFO: got %v, want %v", err, context.Canceled)
+	}
+	// The data may not have been read yet if the reader was slow to start.
+	if len(data) > 0 && string(data) != "partial" {
+		t.Errorf("ReadFileContext returned %q, want %q", data, "partial")
+	}
+}
@@ -44,13 +44,18 @@
 package os
 
 import (
+	"context"
 	"errors"
+	"internal/bytealg"
 	"internal/filepathlite"
 	"internal/poll"
 	"internal/testlog"
 	"io"
 	"io/fs"
+	"iter"
 	"runtime"
+	"slices"
+	"sync/atomic"
 	"syscall"
 	"time"
 	"unsafe"
@@ -73,6 +78,27 @@ var (
 	Stderr = NewFile(uintptr(syscall.Stderr), "/dev/stderr")
 )
 
+// StdinPollable returns a new File for a duplicate of the standard input
+// descriptor that, unlike [Stdin], supports [File.SetReadDeadline] where
+// the runtime poller can wait for the standard input to become readable,
+// as when it is a pipe, a socket or a terminal on Linux. On Unix systems,
+// that requires a descriptor in non-blocking mode. On Linux, a pipe or
+// terminal is opened again through /proc/self/fd/0, so that the mode is
+// its own. Otherwise the mode of the duplicate is shared by Stdin and by
+// any other process reading the same file, so all reads from it should
+// go through the returned File, and closing the File puts the standard
+// input back into blocking mode.
+//
+// If the standard input is a regular file, or another kind of file that
+// the poller does not support, the returned File behaves like Stdin, and
+// its SetReadDeadline method returns an error that matches both
+// [ErrNoDeadline] and [errors.ErrUnsupported].
+// On Windows and Plan 9, StdinPollable returns an error wrapping
+// [errors.ErrUnsupported].
+func StdinPollable() (*File, error) {
+	return stdinPollable()
+}
+
 // Flags to OpenFile wrapping those of the underlying system. Not all
 // flags may be implemented on a given system.
 const (
@@ -125,6 +151,19 @@ func (f *File) Read(b []byte) (n int, err error) {
 	return n, f.wrapErr("read", e)
 }
 
+// Readv reads up to the combined length of bufs bytes from the File,
+// filling each buffer in turn, and returns the total number of bytes read.
+// On Unix systems it makes a single readv system call, so like Read it
+// may fill fewer bytes than requested. At end of file, Readv returns 0,
+// io.EOF.
+func (f *File) Readv(bufs [][]byte) (n int, err error) {
+	if err := f.checkValid("readv"); err != nil {
+		return 0, err
+	}
+	n, e := f.readv(bufs)
+	return n, f.wrapErr("readv", e)
+}
+
 // ReadAt reads len(b) bytes from the File starting at byte offset off.
 // It returns the number of bytes read and the error, if any.
 // ReadAt always returns a non-nil error when n < len(b).
@@ -151,6 +190,35 @@ func (f *File) ReadAt(b []byte, off int64) (n int, err error) {
 	return
 }
 
+// ReadvAt reads up to the combined length of bufs bytes from the File
+// starting at byte offset off, filling each buffer in turn. It returns the
+// total number of bytes read and the error, if any. Like ReadAt, it does
+// not change the file offset, and it always returns a non-nil error when
+// n is less than the combined length of bufs. At end of file, that error
+// is io.EOF.
+func (f *File) ReadvAt(bufs [][]byte, off int64) (n int, err error) {
+	if err := f.checkValid("read"); err != nil {
+		return 0, err
+	}
+
+	if off < 0 {
+		return 0, &PathError{Op: "readvat", Path: f.name, Err: errors.New("negative offset")}
+	}
+
+	bufs = consumeBufs(slices.Clone(bufs), 0)
+	for len(bufs) > 0 {
+		m, e := f.preadv(bufs, off)
+		if e != nil {
+			err = f.wrapErr("read", e)
+			break
+		}
+		n += m
+		off += int64(m)
+		bufs = consumeBufs(bufs, m)
+	}
+	return
+}
+
 // ReadFrom implements io.ReaderFrom.
 func (f *File) ReadFrom(r io.Reader) (n int64, err error) {
 	if err := f.checkValid("write"); err != nil {
@@ -158,7 +226,9 @@ func (f *File) ReadFrom(r io.Reader) (n int64, err error) {
 	}
 	n, handled, e := f.readFrom(r)
 	if !handled {
-		return genericReadFrom(f, r) // without wrapping
+		n, err = genericReadFrom(f, r) // without wrapping
+		f.recordCopy(copyMethodGeneric, n)
+		return n, err
 	}
 	return n, f.wrapErr("write", e)
 }
@@ -209,6 +279,37 @@ func (f *File) Write(b []byte) (n int, err error) {
 	return n, err
 }
 
+// Writev writes the contents of each buffer in bufs to the File in order,
+// as if they were concatenated, and returns the total number of bytes
+// written. On Unix systems it uses the writev system call, issuing more
+// calls as needed until all of bufs is written. Writev returns a non-nil
+// error when n is less than the combined length of bufs.
+// The bufs slice itself is not modified.
+func (f *File) Writev(bufs [][]byte) (n int, err error) {
+	if err := f.checkValid("writev"); err != nil {
+		return 0, err
+	}
+	var total int
+	for _, b := range bufs {
+		total += len(b)
+	}
+	n, e := f.writev(bufs)
+	if n < 0 {
+		n = 0
+	}
+	if n != total {
+		err = io.ErrShortWrite
+	}
+
+	epipecheck(f, e)
+
+	if e != nil {
+		err = f.wrapErr("writev", e)
+	}
+
+	return n, err
+}
+
 var errWriteAtInAppendMode = errors.New("os: invalid use of WriteAt on file opened with O_APPEND")
 
 // WriteAt writes len(b) bytes to the File starting at byte offset off.
@@ -241,6 +342,58 @@ func (f *File) WriteAt(b []byte, off int64) (n int, err error) {
 	return
 }
 
+// WritevAt writes the contents of each buffer in bufs to the File in order,
+// as if they were concatenated, starting at byte offset off. It returns the
+// total number of bytes written and an error, if any. Like WriteAt, it does
+// not change the file offset, and it returns a non-nil error when n is
+// less than the combined length of bufs.
+//
+// If file was opened with the O_APPEND flag, WritevAt returns an error.
+func (f *File) WritevAt(bufs [][]byte, off int64) (n int, err error) {
+	if err := f.checkValid("write"); err != nil {
+		return 0, err
+	}
+	if f.appendMode {
+		return 0, errWriteAtInAppendMode
+	}
+
+	if off < 0 {
+		return 0, &PathError{Op: "writevat", Path: f.name, Err: errors.New("negative offset")}
+	}
+
+	bufs = consumeBufs(slices.Clone(bufs), 0)
+	for len(bufs) > 0 {
+		m, e := f.pwritev(bufs, off)
+		if e == nil && m == 0 {
+			e = io.ErrUnexpectedEOF
+		}
+		if e != nil {
+			err = f.wrapErr("write", e)
+			break
+		}
+		n += m
+		off += int64(m)
+		bufs = consumeBufs(bufs, m)
+	}
+	return
+}
+
+// consumeBufs removes the first n bytes from bufs, along with any
+// buffers left empty at its start. It reslices the elements of bufs
+// in place.
+func consumeBufs(bufs [][]byte, n int) [][]byte {
+	for len(bufs) > 0 {
+		m := min(n, len(bufs[0]))
+		bufs[0] = bufs[0][m:]
+		n -= m
+		if len(bufs[0]) > 0 {
+			break
+		}
+		bufs = bufs[1:]
+	}
+	return bufs
+}
+
 // WriteTo implements io.WriterTo.
 func (f *File) WriteTo(w io.Writer) (n int64, err error) {
 	if err := f.checkValid("read"); err != nil {
@@ -250,7 +403,9 @@ func (f *File) WriteTo(w io.Writer) (n int64, err error) {
 	if handled {
 		return n, f.wrapErr("read", e)
 	}
-	return genericWriteTo(f, w) // without wrapping
+	n, err = genericWriteTo(f, w) // without wrapping
+	f.recordCopy(copyMethodGeneric, n)
+	return n, err
 }
 
 // noWriteTo can be embedded alongside another type to
@@ -275,6 +430,91 @@ func genericWriteTo(f *File, w io.Writer) (int64, error) {
 	return io.Copy(w, fileWithoutWriteTo{File: f})
 }
 
+// CopyStats describes how the most recent [File.ReadFrom] or
+// [File.WriteTo] call on a File moved its data.
+type CopyStats struct {
+	// Method names the mechanism that was used: "sendfile"
+	// (TransmitFile on Windows), "splice", "copy_file_range",
+	// "ficlone" for a copy-on-write clone made with the FICLONE
+	// ioctl on Linux, "sparse" for a copy that skips the holes of
+	// a sparse source file, copying its data with copy_file_range
+	// or through a user space buffer, or "generic" for the portable
+	// loop that copies through a user space buffer.
+	// It is empty if no ReadFrom or WriteTo call has completed
+	// on the File.
+	Method string
+
+	// Bytes is the number of bytes moved by Method.
+	Bytes int64
+}
+
+// CopyStats reports how the most recent ReadFrom or WriteTo call on f,
+// including one made implicitly by [io.Copy], transferred its data.
+// It is intended for tests and diagnostics that want to confirm that a
+// kernel fast path was used. Reading the statistics does not make any
+// system calls. If ReadFrom or WriteTo calls run concurrently, the
+// result describes one of them.
+func (f *File) CopyStats() CopyStats {
+	if f == nil {
+		return CopyStats{}
+	}
+	if e := f.extra.Load(); e != nil {
+		if s := e.copyStats.Load(); s != nil {
+			return *s
+		}
+	}
+	return CopyStats{}
+}
+
+// A copyMethod identifies the mechanism used by ReadFrom or WriteTo.
+type copyMethod uint32
+
+const (
+	copyMethodNone copyMethod = iota
+	copyMethodGeneric
+	copyMethodSendfile
+	copyMethodSplice
+	copyMethodCopyFileRange
+	copyMethodSparse
+	copyMethodFiclone
+)
+
+var copyMethodNames = [...]string{
+	copyMethodNone:          "",
+	copyMethodGeneric:       "generic",
+	copyMethodSendfile:      "sendfile",
+	copyMethodSplice:        "splice",
+	copyMethodCopyFileRange: "copy_file_range",
+	copyMethodSparse:        "sparse",
+	copyMethodFiclone:       "ficlone",
+}
+
+// recordCopy records a copy of n bytes using m, to be reported
+// by CopyStats.
+func (f *File) recordCopy(m copyMethod, n int64) {
+	f.getExtra().copyStats.Store(&CopyStats{Method: copyMethodNames[m], Bytes: n})
+}
+
+// fileExtra holds the state of a File that only some uses need,
+// so that it does not enlarge every File.
+type fileExtra struct {
+	copyStats atomic.Pointer[CopyStats] // reported by CopyStats
+	sorted    atomic.Pointer[sortedDir] // nil unless ReadDirSorted called
+	anon      *anonFile                 // non-nil for an unlinked OpenAnonymous file
+}
+
+// getExtra returns the fileExtra of f, allocating it if needed.
+func (f *File) getExtra() *fileExtra {
+	e := f.extra.Load()
+	if e == nil {
+		e = new(fileExtra)
+		if !f.extra.CompareAndSwap(nil, e) {
+			e = f.extra.Load()
+		}
+	}
+	return e
+}
+
 // Seek sets the offset for the next Read or Write on file to offset, interpreted
 // according to whence: 0 means relative to the origin of the file, 1 means
 // relative to the current offset, and 2 means relative to the end.
@@ -301,8 +541,240 @@ func (f *File) WriteString(s string) (n int, err error) {
 	return f.Write(b)
 }
 
+// linesBufSize is the initial size of the buffer used by File.Lines.
+const linesBufSize = 4096
+
+// Lines returns an iterator over the newline-delimited lines of the file,
+// starting at the current offset. Each line is yielded without its
+// trailing newline; any other characters, including a carriage return
+// preceding the newline, are left intact. The last line of the file
+// need not end in a newline. There is no limit on the length of a line.
+//
+// The yielded slice is only valid until the next iteration,
+// as Lines reuses its buffer to avoid allocating for every line.
+// Callers that need to keep a line must copy it.
+//
+// If reading fails, the error is yielded with a nil line, after which
+// the iteration stops. Because Lines reads ahead, the file offset
+// is unspecified after the iteration stops early.
+func (f *File) Lines() iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		if err := f.checkValid("read"); err != nil {
+			yield(nil, err)
+			return
+		}
+		buf := make([]byte, linesBufSize)
+		start, end := 0, 0
+		for {
+			for {
+				i := bytealg.IndexByte(buf[start:end], '\n')
+				if i < 0 {
+					break
+				}
+				if !yield(buf[start:start+i:start+i], nil) {
+					return
+				}
+				start += i + 1
+			}
+
+			// Move the partial line to the front of the buffer,
+			// growing the buffer if the line fills it.
+			end = copy(buf, buf[start:end])
+			start = 0
+			if end == len(buf) {
+				buf = append(buf, make([]byte, len(buf))...)
+			}
+
+			n, err := f.Read(buf[end:])
+			end += n
+			if err == io.EOF {
+				if end > 0 {
+					yield(buf[:end:end], nil)
+				}
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
+
+// Lock places an exclusive lock on the file, waiting until any
+// conflicting lock is released. The lock is associated with the open
+// File: it is held until [File.Unlock] is called or the File is closed,
+// and it conflicts with locks placed through other open Files, whether in
+// this process or another.
+//
+// On Unix systems Lock uses flock(2), and the lock is advisory: it does
+// not prevent I/O on the file by processes that do not use locks. On
+// Windows Lock uses LockFileEx, and the lock is mandatory: while it is
+// held, reads and writes of the file through any other handle, including
+// another File for the same file in this process, fail.
+// On other systems Lock returns an error wrapping [errors.ErrUnsupported].
+// If there is an error, it will be of type [*PathError].
+func (f *File) Lock() error {
+	if err := f.checkValid("lock"); err != nil {
+		return err
+	}
+	_, err := f.lock(true)
+	return err
+}
+
+// TryLock is like [File.Lock], but it does not wait: if a conflicting lock
+// is held, TryLock returns false and a nil error.
+func (f *File) TryLock() (bool, error) {
+	if err := f.checkValid("lock"); err != nil {
+		return false, err
+	}
+	return f.lock(false)
+}
+
+// Unlock releases a lock placed on the file by [File.Lock] or [File.TryLock].
+// If there is an error, it will be of type [*PathError].
+func (f *File) Unlock() error {
+	if err := f.checkValid("unlock"); err != nil {
+		return err
+	}
+	return f.unlock()
+}
+
+// Flags for [File.SyncRange], which may be combined.
+// They have the values of the Linux SYNC_FILE_RANGE_WAIT_BEFORE,
+// SYNC_FILE_RANGE_WRITE and SYNC_FILE_RANGE_WAIT_AFTER flags.
+const (
+	SyncRangeWaitBefore = 0x1 // wait for writeout already in progress in the range
+	SyncRangeWrite      = 0x2 // start writeout of dirty pages in the range
+	SyncRangeWaitAfter  = 0x4 // wait for writeout of the range to complete
+)
+
+// SyncRange starts or waits for writeout of the nbytes bytes of f
+// starting at offset, as selected by flags. A nbytes of 0 extends to the
+// end of the file. Unlike [File.Sync], SyncRange does not flush the file's
+// metadata or the disk's write cache, so it does not by itself make the
+// data durable; it is useful for limiting the amount of dirty data that a
+// later Sync must write.
+//
+// SyncRange is only available on Linux, where it uses sync_file_range(2).
+// On other systems it returns an error wrapping [errors.ErrUnsupported].
+// If there is an error, it will be of type [*PathError].
+func (f *File) SyncRange(offset, nbytes int64, flags int) error {
+	if err := f.checkValid("sync_file_range"); err != nil {
+		return err
+	}
+	if e := f.syncRange(offset, nbytes, flags); e != nil {
+		return f.wrapErr("sync_file_range", e)
+	}
+	return nil
+}
+
+// Allocate reserves disk space for the length bytes of f starting at offset,
+// so that later writes to that range do not fail for lack of space.
+// If the range extends past the end of the file, the file's size is
+// increased to offset+length, as by [File.Truncate]; otherwise the size
+// is unchanged. If the space cannot be reserved, Allocate returns an
+// error wrapping [syscall.ENOSPC] or the system's equivalent.
+//
+// On Linux, Allocate uses fallocate(2), on Darwin, F_PREALLOCATE, and on
+// FreeBSD, posix_fallocate. On Windows, Allocate extends the file, which
+// reserves its space, but does not allocate holes within it. On other
+// systems, or file systems that cannot preallocate, Allocate returns an
+// error wrapping [errors.ErrUnsupported].
+// If there is an error, it will be of type [*PathError].
+func (f *File) Allocate(offset, length int64) error {
+	if err := f.checkValid("allocate"); err != nil {
+		return err
+	}
+	if offset < 0 || length <= 0 || offset > 1<<63-1-length {
+		return f.wrapErr("allocate", syscall.EINVAL)
+	}
+	if e := f.allocate(offset, length); e != nil {
+		return f.wrapErr("allocate", e)
+	}
+	return nil
+}
+
+// A TruncateMode selects how [File.TruncateTo] changes the size of a file.
+type TruncateMode int
+
+// Modes for [File.TruncateTo].
+const (
+	TruncateExact      TruncateMode = iota // set the size, as File.Truncate does
+	TruncateGrowOnly                       // extend the file if it is smaller, never shrink it
+	TruncateShrinkOnly                     // shrink the file if it is larger, never extend it
+)
+
+// TruncateTo changes the size of the file to size, as [File.Truncate]
+// does, but with TruncateGrowOnly or TruncateShrinkOnly as mode it only
+// extends or only shrinks the file, leaving it unchanged otherwise.
+//
+// The current size is checked with [File.Stat] on f just before the
+// size is changed, which narrows but does not close the window for a
+// concurrent writer to change the size in between. Callers that need to
+// exclude such writers must coordinate with them, for example using
+// [File.Lock].
+// If there is an error, it will be of type [*PathError].
+func (f *File) TruncateTo(size int64, mode TruncateMode) error {
+	if err := f.checkValid("truncate"); err != nil {
+		return err
+	}
+	switch mode {
+	case TruncateExact:
+	case TruncateGrowOnly, TruncateShrinkOnly:
+		fi, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		if mode == TruncateGrowOnly && fi.Size() >= size ||
+			mode == TruncateShrinkOnly && fi.Size() <= size {
+			return nil
+		}
+	default:
+		return f.wrapErr("truncate", syscall.EINVAL)
+	}
+	return f.Truncate(size)
+}
+
+// Advice values for [File.Fadvise].
+const (
+	FadviceNormal     = iota // no particular access pattern
+	FadviceSequential        // the data will be read sequentially
+	FadviceRandom            // the data will be read in random order
+	FadviceWillNeed          // the data will be read soon
+	FadviceDontNeed          // the data will not be read soon
+)
+
+// Fadvise advises the system of the expected pattern of access to the
+// length bytes of f starting at offset, so that it can adjust read-ahead
+// and caching to match. A length of 0 extends to the end of the file.
+// advice is one of the Fadvice constants. The advice is only a hint: it
+// does not change the results of any later operation on f.
+//
+// On Linux and FreeBSD, Fadvise uses posix_fadvise. On Darwin,
+// FadviceWillNeed issues an F_RDADVISE for the range, FadviceRandom turns
+// off read-ahead for the whole file and FadviceNormal and
+// FadviceSequential turn it back on; FadviceDontNeed is not supported.
+// Where some advice is not supported, Fadvise returns an error wrapping
+// [errors.ErrUnsupported].
+// If there is an error, it will be of type [*PathError].
+func (f *File) Fadvise(offset, length int64, advice int) error {
+	if err := f.checkValid("fadvise"); err != nil {
+		return err
+	}
+	if advice < FadviceNormal || advice > FadviceDontNeed {
+		return f.wrapErr("fadvise", syscall.EINVAL)
+	}
+	if e := f.fadvise(offset, length, advice); e != nil {
+		return f.wrapErr("fadvise", e)
+	}
+	return nil
+}
+
 // Mkdir creates a new directory with the specified name and permission
-// bits (before umask).
+// bits (before umask). On Unix systems, the ModeSetuid, ModeSetgid and
+// ModeSticky bits of perm are applied too, subject to the rules of the
+// system, even where mkdir(2) ignores them; this is not atomic.
 // If there is an error, it will be of type *PathError.
 func Mkdir(name string, perm FileMode) error {
 	longName := fixLongPath(name)
@@ -314,9 +786,11 @@ func Mkdir(name string, perm FileMode) error {
 		return &PathError{Op: "mkdir", Path: name, Err: e}
 	}
 
-	// mkdir(2) itself won't handle the sticky bit on *BSD and Solaris
-	if !supportsCreateWithStickyBit && perm&ModeSticky != 0 {
-		e = setStickyBit(name)
+	// mkdir(2) itself may drop the special bits: Linux takes the setgid
+	// bit of a new directory from its parent, and *BSD and Solaris
+	// ignore the sticky bit.
+	if perm&modeSpecial != 0 {
+		e = setSpecialBits(name, perm)
 
 		if e != nil {
 			Remove(name)
@@ -327,13 +801,69 @@ func Mkdir(name string, perm FileMode) error {
 	return nil
 }
 
-// setStickyBit adds ModeSticky to the permission bits of path, non atomic.
-func setStickyBit(name string) error {
-	fi, err := Stat(name)
-	if err != nil {
+// modeSpecial is the set of permission bits that creating a file or
+// directory may drop.
+const modeSpecial = ModeSetuid | ModeSetgid | ModeSticky
+
+// Mkfifo creates a named pipe (FIFO) with the specified name and
+// permission bits (before umask).
+// If there is an error, it will be of type *PathError.
+//
+// On systems without named pipes, including Windows and Plan 9,
+// Mkfifo returns an error wrapping [errors.ErrUnsupported].
+func Mkfifo(name string, perm FileMode) error {
+	if e := mkfifo(name, perm); e != nil {
+		return &PathError{Op: "mkfifo", Path: name, Err: e}
+	}
+	return nil
+}
+
+// Mknod creates a special file with the specified name, permission
+// bits (before umask) and type, taken from mode:
+// a block device for [ModeDevice], a character device for [ModeCharDevice]
+// (with or without ModeDevice), or a named pipe for [ModeNamedPipe].
+// For device files, dev is the device number, as returned by [Mkdev].
+// It is ignored for named pipes.
+// If there is an error, it will be of type *PathError.
+//
+// Creating a device file usually requires privileges, such as
+// CAP_MKNOD on Linux. Without them, Mknod returns an error for which
+// errors.Is(err, ErrPermission) is true.
+//
+// On systems without special files, including Windows and Plan 9,
+// Mknod returns an error wrapping [errors.ErrUnsupported].
+func Mknod(name string, mode FileMode, dev uint64) error {
+	if e := mknod(name, mode, dev); e != nil {
+		return &PathError{Op: "mknod", Path: name, Err: e}
+	}
+	return nil
+}
+
+// Mkdev returns the device number with the given major and minor numbers,
+// in the encoding that the system uses for [Mknod] and for the device
+// numbers reported by [File.Stat].
+// On systems where Mknod is not supported, the encoding is unspecified.
+func Mkdev(major, minor uint32) uint64 {
+	return mkdev(major, minor)
+}
+
+// ChtimesFull is like [Chtimes], but also changes the birth (creation)
+// time of the named file to btime, on systems that allow it to be set:
+// Windows, and Darwin on file systems that support it.
+// Elsewhere btime is silently ignored.
+// A zero [time.Time] value will leave the corresponding file time unchanged.
+// If there is an error, it will be of type *PathError.
+func ChtimesFull(name string, atime, mtime, btime time.Time) error {
+	if err := Chtimes(name, atime, mtime); err != nil {
 		return err
 	}
-	return Chmod(name, fi.Mode()|ModeSticky)
+	if btime.IsZero() {
+		return nil
+	}
+	if e := setBirthtime(name, btime); e != nil {
+		return &PathError{Op: "chtimes", Path: name, Err: e}
+	}
+	return nil
 }
 
 // Chdir changes the current working directory to the named directory.
@@ -347,6 +877,8 @@ func Chdir(dir string) error {
 		getwdCache.Lock()
 		getwdCache.dir = dir
 		getwdCache.Unlock()
+	} else {
+		clearGetwdCache()
 	}
 	if log := testlog.Logger(); log != nil {
 		wd, err := Getwd()
@@ -357,6 +889,28 @@ func Chdir(dir string) error {
 	return nil
 }
 
+// WithChroot changes the root directory of the process to dir, calls fn,
+// and then restores the previous root directory and working directory.
+// While fn runs, the working directory is the new root. WithChroot
+// returns the error from fn or, if fn succeeded, any error restoring the
+// previous state. Changing the root directory usually requires
+// privileges; without them, WithChroot returns an error satisfying
+// [IsPermission] and does not call fn.
+//
+// The root and working directories are shared by all goroutines, so
+// every goroutine sees dir as the root while fn runs, and paths opened
+// concurrently elsewhere in the program are resolved inside dir. Calls
+// to WithChroot are serialized, and fn must not call WithChroot itself.
+// WithChroot is not a security boundary: any file opened before the
+// call still refers to the file outside of dir.
+//
+// On systems without chroot, including Windows, WithChroot returns an
+// error wrapping [errors.ErrUnsupported].
+// If there is an error, it will be of type [*PathError].
+func WithChroot(dir string, fn func() error) error {
+	return withChroot(dir, fn)
+}
+
 // Open opens the named file for reading. If successful, methods on
 // the returned file can be used for reading; the associated file
 // descriptor has mode O_RDONLY.
@@ -374,11 +928,23 @@ func Create(name string) (*File, error) {
 	return OpenFile(name, O_RDWR|O_CREATE|O_TRUNC, 0666)
 }
 
+// CreateMode is like [Create], but creates the file with mode perm
+// (before umask) if it does not exist. It is shorthand for
+// OpenFile(name, O_RDWR|O_CREATE|O_TRUNC, perm). As with OpenFile, perm
+// does not change the mode of an existing file, and on Windows only
+// its 0o200 bit is used: without it, the file is created read-only.
+// If there is an error, it will be of type *PathError.
+func CreateMode(name string, perm FileMode) (*File, error) {
+	return OpenFile(name, O_RDWR|O_CREATE|O_TRUNC, perm)
+}
+
 // OpenFile is the generalized open call; most users will use Open
 // or Create instead. It opens the named file with specified flag
 // (O_RDONLY etc.). If the file does not exist, and the O_CREATE flag
-// is passed, it is created with mode perm (before umask). If successful,
-// methods on the returned File can be used for I/O.
+// is passed, it is created with mode perm (before umask). As with [Mkdir],
+// the ModeSetuid, ModeSetgid and ModeSticky bits of perm are applied
+// on Unix systems. If successful, methods on the returned File can be
+// used for I/O.
 // If there is an error, it will be of type *PathError.
 func OpenFile(name string, flag int, perm FileMode) (*File, error) {
 	testlog.Open(name)
@@ -391,6 +957,132 @@ func OpenFile(name string, flag int, perm FileMode) (*File, error) {
 	return f, nil
 }
 
+// OpenFileNonblock is like [OpenFile], but opens the named file in
+// non-blocking mode, so that opening a FIFO or a device that is not
+// ready does not block. For example, opening the read end of a FIFO
+// succeeds at once even if no writer has opened it yet. Until one does,
+// reads from it return [io.EOF] immediately rather than waiting, as
+// they do once all writers have closed the FIFO; a reader that wants to
+// wait for a writer should retry, or open the FIFO with [OpenFile].
+//
+// The file is then added to the runtime poller if possible, so that
+// reads and writes wait for it to be ready, rather than failing, and
+// the SetDeadline methods work. Files that the poller does not support,
+// such as regular files, are switched back to blocking mode after they
+// are opened. As for other files, calling [File.Fd] puts the file into
+// blocking mode; after that, blocking operations tie up a thread and
+// deadlines no longer apply.
+//
+// On systems where opening a file does not block in this way, including
+// Windows and Plan 9, OpenFileNonblock is the same as OpenFile.
+func OpenFileNonblock(name string, flag int, perm FileMode) (*File, error) {
+	return openFileNonblock(name, flag, perm)
+}
+
+// OpenAt opens the named file relative to the directory f, which must
+// have been opened with [Open] or an equivalent call on a directory.
+// It is otherwise like [OpenFile]. The name must not be absolute.
+// Because the name is resolved relative to f itself rather than to f's name,
+// OpenAt keeps working if the directory is renamed after f was opened,
+// except on systems without openat, including Windows and Plan 9,
+// where OpenAt is emulated using f's name.
+// The returned file's name is the name of f joined with name.
+// If there is an error, it will be of type [*PathError].
+func (f *File) OpenAt(name string, flag int, perm FileMode) (*File, error) {
+	if err := f.checkValidAt("openat", name); err != nil {
+		return nil, err
+	}
+	testlog.Open(joinPath(f.name, name))
+	nf, err := f.openAt(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	nf.appendMode = flag&O_APPEND != 0
+	return nf, nil
+}
+
+// StatAt returns a [FileInfo] describing the named file relative to
+// the directory f, as [Stat] does, or as [Lstat] does if followSymlinks
+// is false. The name must not be absolute.
+// See [File.OpenAt] for how name is resolved.
+// If there is an error, it will be of type [*PathError].
+func (f *File) StatAt(name string, followSymlinks bool) (FileInfo, error) {
+	if err := f.checkValidAt("statat", name); err != nil {
+		return nil, err
+	}
+	testlog.Stat(joinPath(f.name, name))
+	return f.statAt(name, followSymlinks)
+}
+
+// MkdirAt creates a new directory with the specified name and permission
+// bits (before umask) relative to the directory f, as [Mkdir] does.
+// The name must not be absolute.
+// See [File.OpenAt] for how name is resolved.
+// If there is an error, it will be of type [*PathError].
+func (f *File) MkdirAt(name string, perm FileMode) error {
+	if err := f.checkValidAt("mkdirat", name); err != nil {
+		return err
+	}
+	return f.mkdirAt(name, perm)
+}
+
+// ReadlinkAt returns the destination of the named symbolic link
+// relative to the directory f, as [Readlink] does.
+// The name must not be absolute.
+// See [File.OpenAt] for how name is resolved.
+// On Windows, ReadlinkAt returns an error wrapping [errors.ErrUnsupported].
+// If there is an error, it will be of type [*PathError].
+func (f *File) ReadlinkAt(name string) (string, error) {
+	if err := f.checkValidAt("readlinkat", name); err != nil {
+		return "", err
+	}
+	return f.readlinkAt(name)
+}
+
+// ChmodAt changes the mode of the named file relative to the directory f
+// to mode, as [Chmod] does. The name must not be absolute.
+// If the file is a symbolic link and followSymlink is false, ChmodAt
+// changes the mode of the link itself rather than of its target; most
+// systems, including Linux, do not support that and return an error.
+// See [File.OpenAt] for how name is resolved.
+// If there is an error, it will be of type [*PathError].
+func (f *File) ChmodAt(name string, mode FileMode, followSymlink bool) error {
+	if err := f.checkValidAt("chmodat", name); err != nil {
+		return err
+	}
+	return f.chmodAt(name, mode, followSymlink)
+}
+
+// ChownAt changes the numeric uid and gid of the named file relative to
+// the directory f, as [Chown] does, or as [Lchown] does if followSymlink
+// is false. A uid or gid of -1 means to not change that value.
+// The name must not be absolute.
+// See [File.OpenAt] for how name is resolved.
+// On Windows and Plan 9, ChownAt always returns an error, as Chown does.
+// If there is an error, it will be of type [*PathError].
+func (f *File) ChownAt(name string, uid, gid int, followSymlink bool) error {
+	if err := f.checkValidAt("chownat", name); err != nil {
+		return err
+	}
+	return f.chownAt(name, uid, gid, followSymlink)
+}
+
+// checkValidAt checks that f is valid and that name may be resolved
+// relative to it.
+func (f *File) checkValidAt(op, name string) error {
+	if err := f.checkValid(op); err != nil {
+		return err
+	}
+	if filepathlite.IsAbs(name) || filepathlite.VolumeName(name) != "" {
+		return &PathError{Op: op, Path: name, Err: errAbsoluteAt}
+	}
+	return nil
+}
+
+// errAbsoluteAt is returned by the methods of File that resolve names
+// relative to a directory when given an absolute name.
+var errAbsoluteAt = errors.New("name is absolute")
+
 // openDir opens a file which is assumed to be a directory. As such, it skips
 // the syscalls that make the file descriptor non-blocking as these take time
 // and will fail on file descriptors for directories.
@@ -402,6 +1094,42 @@ func openDir(name string) (*File, error) {
 // lstat is overridden in tests.
 var lstat = Lstat
 
+// LinkFollow creates newname as a hard link to the oldname file, like [Link],
+// but lets the caller choose what happens when oldname is a symbolic link.
+// If followSymlink is true, newname is linked to the file that the symbolic
+// link refers to; otherwise it is linked to the symbolic link itself.
+// If oldname is not a symbolic link, LinkFollow behaves like Link.
+//
+// On Linux, Darwin and the BSDs, LinkFollow uses linkat(2), with
+// AT_SYMLINK_FOLLOW if followSymlink is true. On other systems, a followed
+// symbolic link is resolved before the link is created, so the target may
+// change in between. There, linking to a symbolic link itself is possible
+// on Windows; elsewhere, whether link(2) follows symbolic links is system
+// dependent, and LinkFollow returns an error wrapping
+// [errors.ErrUnsupported] if oldname is a symbolic link and followSymlink
+// is false.
+//
+// If there is an error, it will be of type *LinkError.
+func LinkFollow(oldname, newname string, followSymlink bool) error {
+	return linkFollow(oldname, newname, followSymlink)
+}
+
+// SymlinkDir is like [Symlink], but on Windows it always creates a
+// directory symlink, without checking whether oldname is a directory.
+// This allows creating a link to a directory that does not exist yet,
+// which Symlink would create as a file symlink.
+// On other systems, SymlinkDir is the same as Symlink.
+func SymlinkDir(oldname, newname string) error {
+	return symlinkAs(oldname, newname, true)
+}
+
+// SymlinkFile is like [Symlink], but on Windows it always creates a
+// file symlink, without checking whether oldname is a directory.
+// On other systems, SymlinkFile is the same as Symlink.
+func SymlinkFile(oldname, newname string) error {
+	return symlinkAs(oldname, newname, false)
+}
+
 // Rename renames (moves) oldpath to newpath.
 // If newpath already exists and is not a directory, Rename replaces it.
 // OS-specific restrictions may apply when oldpath and newpath are in different directories.
@@ -416,25 +1144,98 @@ func Rename(oldpath, newpath string) error {
 //
 // If the link destination is relative, Readlink returns the relative path
 // without resolving it to an absolute one.
+//
+// On Unix systems, the destination is returned exactly as stored, even
+// if it is not valid UTF-8: a Go string may hold arbitrary bytes, and
+// converting it to a []byte recovers them unchanged.
 func Readlink(name string) (string, error) {
 	return readlink(name)
 }
 
-// Many functions in package syscall return a count of -1 instead of 0.
-// Using fixCount(call()) instead of call() corrects the count.
-func fixCount(n int, err error) (int, error) {
-	if n < 0 {
-		n = 0
+// ReparsePointTarget returns the tag of the named Windows reparse point
+// and, for symbolic links, directory junctions and app execution aliases,
+// its target. For other reparse points the returned target is empty.
+// If there is an error, it will be of type *PathError.
+//
+// On other systems, ReparsePointTarget returns an error wrapping
+// [errors.ErrUnsupported].
+func ReparsePointTarget(name string) (target string, tag uint32, err error) {
+	return reparsePointTarget(name)
+}
+
+// IsTerminal reports whether f refers to a terminal, such as a console
+// on Windows. It returns false if f is nil or closed, or on any error.
+//
+// On Unix systems other than Linux, the BSDs and macOS, and on other
+// systems than Windows, IsTerminal always returns false.
+func (f *File) IsTerminal() bool {
+	if f.checkValid("isterminal") != nil {
+		return false
 	}
-	return n, err
+	return f.isTerminal()
 }
 
-// checkWrapErr is the test hook to enable checking unexpected wrapped errors of poll.ErrFileClosing.
-// It is set to true in the export_test.go for tests (including fuzz tests).
-var checkWrapErr = false
+// TerminalSize returns the width and height, in characters, of the
+// terminal f refers to, such as the visible window of a console on
+// Windows. If f is not a terminal, TerminalSize returns an error,
+// which will be of type *PathError.
+//
+// TerminalSize is only implemented on Linux, the BSDs, macOS and
+// Windows; elsewhere it always returns an error.
+func (f *File) TerminalSize() (cols, rows int, err error) {
+	if err := f.checkValid("terminalsize"); err != nil {
+		return 0, 0, err
+	}
+	cols, rows, err = f.terminalSize()
+	if err != nil {
+		return 0, 0, f.wrapErr("terminalsize", err)
+	}
+	return cols, rows, nil
+}
 
-// wrapErr wraps an error that occurred during an operation on an open file.
-// It passes io.EOF through unchanged, otherwise converts
+// SetPipeSize sets the capacity of the pipe f refers to, such as an end
+// of a pipe returned by [Pipe], to at least size bytes, and returns the
+// resulting capacity. The kernel rounds size up, to at least a page.
+// A larger pipe lets a writer get further ahead of its reader, which
+// can improve throughput for bulk transfers.
+//
+// On Linux, unprivileged processes cannot make a pipe larger than the
+// limit in /proc/sys/fs/pipe-max-size, 1 MiB by default, and SetPipeSize
+// then fails with EPERM. If f is not a pipe, or on systems other than
+// Linux, SetPipeSize returns an error wrapping [errors.ErrUnsupported].
+// If there is an error, it will be of type [*PathError].
+func (f *File) SetPipeSize(size int) (actual int, err error) {
+	if err := f.checkValid("setpipesize"); err != nil {
+		return 0, err
+	}
+	actual, err = f.setPipeSize(size)
+	if err != nil {
+		return 0, f.wrapErr("setpipesize", err)
+	}
+	return actual, nil
+}
+
+// IsTerminal reports whether the file descriptor fd refers to a terminal,
+// as [File.IsTerminal] does. It returns false on any error.
+func IsTerminal(fd uintptr) bool {
+	return isTerminal(fd)
+}
+
+// Many functions in package syscall return a count of -1 instead of 0.
+// Using fixCount(call()) instead of call() corrects the count.
+func fixCount(n int, err error) (int, error) {
+	if n < 0 {
+		n = 0
+	}
+	return n, err
+}
+
+// checkWrapErr is the test hook to enable checking unexpected wrapped errors of poll.ErrFileClosing.
+// It is set to true in the export_test.go for tests (including fuzz tests).
+var checkWrapErr = false
+
+// wrapErr wraps an error that occurred during an operation on an open file.
+// It passes io.EOF through unchanged, otherwise converts
 // poll.ErrFileClosing to ErrClosed and wraps the error in a PathError.
 func (f *File) wrapErr(op string, err error) error {
 	if err == nil || err == io.EOF {
@@ -563,6 +1364,100 @@ func UserConfigDir() (string, error) {
 	return dir, nil
 }
 
+// UserStateDir returns the default root directory to use for
+// user-specific state data, such as logs and history, that should
+// persist between runs but is not important enough to be kept with
+// configuration. Users should create their own application-specific
+// subdirectory within this one and use that.
+//
+// On Unix systems, it returns $XDG_STATE_HOME as specified by
+// https://specifications.freedesktop.org/basedir-spec/basedir-spec-latest.html if
+// non-empty, else $HOME/.local/state.
+// On Darwin, it returns $HOME/Library/Application Support.
+// On Windows, it returns %LocalAppData%.
+// On Plan 9, it returns $home/lib/state.
+//
+// If the location cannot be determined (for example, $HOME is not defined),
+// then it will return an error.
+func UserStateDir() (string, error) {
+	var dir string
+
+	switch runtime.GOOS {
+	case "windows":
+		dir = Getenv("LocalAppData")
+		if dir == "" {
+			return "", errors.New("%LocalAppData% is not defined")
+		}
+
+	case "darwin", "ios":
+		dir = Getenv("HOME")
+		if dir == "" {
+			return "", errors.New("$HOME is not defined")
+		}
+		dir += "/Library/Application Support"
+
+	case "plan9":
+		dir = Getenv("home")
+		if dir == "" {
+			return "", errors.New("$home is not defined")
+		}
+		dir += "/lib/state"
+
+	default: // Unix
+		dir = Getenv("XDG_STATE_HOME")
+		if dir == "" {
+			dir = Getenv("HOME")
+			if dir == "" {
+				return "", errors.New("neither $XDG_STATE_HOME nor $HOME are defined")
+			}
+			dir += "/.local/state"
+		}
+	}
+
+	return dir, nil
+}
+
+// UserRuntimeDir returns the default root directory to use for
+// user-specific runtime files, such as sockets and pid files, that
+// should not outlive the user's session. Users should create their
+// own application-specific subdirectory within this one and use that.
+//
+// On Unix systems, it returns $XDG_RUNTIME_DIR as specified by
+// https://specifications.freedesktop.org/basedir-spec/basedir-spec-latest.html.
+// There is no fallback, as a directory shared with other users would
+// not be safe to use.
+// On Darwin, it returns $TMPDIR, which is private to the user.
+// On Windows, it returns the directory reported by [TempDir].
+// On Plan 9, it returns /tmp, which is private to the namespace.
+//
+// If the location cannot be determined (for example, $XDG_RUNTIME_DIR
+// is not defined), then it will return an error.
+func UserRuntimeDir() (string, error) {
+	var dir string
+
+	switch runtime.GOOS {
+	case "windows":
+		dir = TempDir()
+
+	case "darwin", "ios":
+		dir = Getenv("TMPDIR")
+		if dir == "" {
+			return "", errors.New("$TMPDIR is not defined")
+		}
+
+	case "plan9":
+		dir = "/tmp"
+
+	default: // Unix
+		dir = Getenv("XDG_RUNTIME_DIR")
+		if dir == "" {
+			return "", errors.New("$XDG_RUNTIME_DIR is not defined")
+		}
+	}
+
+	return dir, nil
+}
+
 // UserHomeDir returns the current user's home directory.
 //
 // On Unix, including macOS, it returns the $HOME environment variable.
@@ -612,6 +1507,22 @@ func UserHomeDir() (string, error) {
 // and ModeTemporary are used.
 func Chmod(name string, mode FileMode) error { return chmod(name, mode) }
 
+// ChmodACL is like [Chmod], but on Windows it also replaces the access
+// control list of the named file to approximate the permission bits of
+// mode. Each of the owner, group and other classes of bits is granted to
+// the file's owner, its primary group and the Everyone group respectively,
+// and rights that a class lacks but a wider class has are explicitly
+// denied to it. The list is protected from inheriting entries of the
+// parent directory. Because Windows checks group membership rather than
+// POSIX classes, a user who is both the owner and a member of the group
+// gets the rights denied to the group, and the group bits are
+// ignored if the owner and group are the same.
+// Use [StatACL] to read back the bits.
+//
+// On other systems, ChmodACL is the same as Chmod.
+// If there is an error, it will be of type *PathError.
+func ChmodACL(name string, mode FileMode) error { return chmodACL(name, mode) }
+
 // Chmod changes the mode of the file to mode.
 // If there is an error, it will be of type *PathError.
 func (f *File) Chmod(mode FileMode) error { return f.chmod(mode) }
@@ -621,7 +1532,9 @@ func (f *File) Chmod(mode FileMode) error { return f.chmod(mode) }
 //
 // Only some kinds of files support setting a deadline. Calls to SetDeadline
 // for files that do not support deadlines will return ErrNoDeadline.
-// On most systems ordinary files do not support deadlines, but pipes do.
+// On most systems ordinary files do not support deadlines, but pipes do,
+// as do FIFOs opened with [OpenFile] on systems other than Darwin and iOS.
+// On Windows, pipes created by [Pipe] do not support deadlines.
 //
 // A deadline is an absolute time after which I/O operations fail with an
 // error instead of blocking. The deadline applies to all future and pending
@@ -684,8 +1597,8 @@ func (f *File) SyscallConn() (syscall.RawConn, error) {
 //
 // The directory dir must not be "".
 //
-// The result implements [io/fs.StatFS], [io/fs.ReadFileFS] and
-// [io/fs.ReadDirFS].
+// The result implements [io/fs.StatFS], [io/fs.ReadFileFS],
+// [io/fs.ReadDirFS], [io/fs.ReadLinkFS] and [io/fs.SubFS].
 func DirFS(dir string) fs.FS {
 	return dirFS(dir)
 }
@@ -761,6 +1674,57 @@ func (dir dirFS) Stat(name string) (fs.FileInfo, error) {
 	return f, nil
 }
 
+// Lstat returns a FileInfo describing the named file without following
+// a final symbolic link. Through this method, dirFS implements
+// [io/fs.ReadLinkFS].
+func (dir dirFS) Lstat(name string) (fs.FileInfo, error) {
+	fullname, err := dir.join(name)
+	if err != nil {
+		return nil, &PathError{Op: "lstat", Path: name, Err: err}
+	}
+	f, err := Lstat(fullname)
+	if err != nil {
+		// See comment in dirFS.Open.
+		err.(*PathError).Path = name
+		return nil, err
+	}
+	return f, nil
+}
+
+// ReadLink returns the destination of the named symbolic link, as
+// [Readlink] does. The destination is not resolved, so it may refer
+// to a file outside dir. Through this method, dirFS implements
+// [io/fs.ReadLinkFS].
+func (dir dirFS) ReadLink(name string) (string, error) {
+	fullname, err := dir.join(name)
+	if err != nil {
+		return "", &PathError{Op: "readlink", Path: name, Err: err}
+	}
+	target, err := Readlink(fullname)
+	if err != nil {
+		if e, ok := err.(*PathError); ok {
+			// See comment in dirFS.Open.
+			e.Path = name
+		}
+		return "", err
+	}
+	return target, nil
+}
+
+// Sub returns the file system rooted at the named subdirectory of dir.
+// Unlike the result of [io/fs.Sub], it keeps implementing the optional
+// interfaces of dirFS. Through this method, dirFS implements [io/fs.SubFS].
+func (dir dirFS) Sub(name string) (fs.FS, error) {
+	if name == "." {
+		return dir, nil
+	}
+	fullname, err := dir.join(name)
+	if err != nil {
+		return nil, &PathError{Op: "sub", Path: name, Err: err}
+	}
+	return dirFS(fullname), nil
+}
+
 // join returns the path for name in dir.
 func (dir dirFS) join(name string) (string, error) {
 	if dir == "" {
@@ -822,6 +1786,79 @@ func ReadFile(name string) ([]byte, error) {
 	}
 }
 
+// readFileContextChunk is the most ReadFileContext reads between
+// checks of its context.
+const readFileContextChunk = 1 << 20
+
+// ReadFileContext is like [ReadFile], but stops reading if ctx is done
+// before the whole file has been read, returning the data read so far
+// along with ctx.Err(). The file is read in chunks, and ctx is checked
+// before each one.
+//
+// For files that support read deadlines, such as pipes and FIFOs on
+// most systems, canceling ctx also interrupts a read blocked waiting
+// for data. For other files, including regular files on a hung network
+// file system, cancellation takes effect only once the read in progress
+// returns. Opening the file cannot be interrupted.
+func ReadFileContext(ctx context.Context, name string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f, err := Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Wake a blocked read once ctx is done. For a file without
+	// deadline support this fails with ErrNoDeadline, which we ignore.
+	stop := context.AfterFunc(ctx, func() {
+		f.SetReadDeadline(time.Unix(1, 0))
+	})
+	defer stop()
+
+	var size int
+	if info, err := f.Stat(); err == nil {
+		size64 := info.Size()
+		if int64(int(size64)) == size64 {
+			size = int(size64)
+		}
+	}
+	size++ // one byte for final read at EOF
+
+	// See ReadFile.
+	if size < 512 {
+		size = 512
+	}
+
+	data := make([]byte, 0, size)
+	for {
+		if err := ctx.Err(); err != nil {
+			return data, err
+		}
+		buf := data[len(data):cap(data)]
+		if len(buf) > readFileContextChunk {
+			buf = buf[:readFileContextChunk]
+		}
+		n, err := f.Read(buf)
+		data = data[:len(data)+n]
+		if err != nil {
+			if err == io.EOF {
+				return data, nil
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(err, ErrDeadlineExceeded) {
+				return data, ctxErr
+			}
+			return data, err
+		}
+
+		if len(data) >= cap(data) {
+			d := append(data[:cap(data)], 0)
+			data = d[:len(data)]
+		}
+	}
+}
+
 // WriteFile writes data to the named file, creating it if necessary.
 // If the file does not exist, WriteFile creates it with permissions perm (before umask);
 // otherwise WriteFile truncates it before writing, without changing permissions.
@@ -838,3 +1875,360 @@ func WriteFile(name string, data []byte, perm FileMode) error {
 	}
 	return err
 }
+
+// ReadAtFile reads len(p) bytes from the named file starting at byte
+// offset off, as by [File.ReadAt] on the file opened with [Open].
+// It returns the number of bytes read and any error encountered,
+// which is io.EOF if the file ends before p is filled and is otherwise
+// of type *PathError.
+func ReadAtFile(name string, p []byte, off int64) (int, error) {
+	f, err := Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return f.ReadAt(p, off)
+}
+
+// WriteAtFile writes p to the named file starting at byte offset off,
+// as by [File.WriteAt], without truncating the file. The file must
+// already exist; use [WriteAtFileFlag] to create it.
+// WriteAtFile returns the number of bytes written and an error,
+// of type *PathError, if n < len(p).
+func WriteAtFile(name string, p []byte, off int64) (int, error) {
+	return WriteAtFileFlag(name, p, off, 0, 0)
+}
+
+// WriteAtFileFlag is like [WriteAtFile], but opens the file with
+// [O_WRONLY] and the additional flags in flag, such as [O_CREATE],
+// using perm for a newly created file as [OpenFile] does.
+func WriteAtFileFlag(name string, p []byte, off int64, flag int, perm FileMode) (int, error) {
+	f, err := OpenFile(name, O_WRONLY|flag, perm)
+	if err != nil {
+		return 0, err
+	}
+	n, err := f.WriteAt(p, off)
+	if err1 := f.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+	return n, err
+}
+
+// WriteFileAtomic writes data to the named file, creating it if necessary,
+// such that a crash or a failure part way through leaves name either
+// unchanged or holding all of data, never a partial write.
+// The data is written to an unnamed file in the same directory, as created by
+// [OpenAnonymous], which is synced and then linked over name; the directory
+// is then synced too, so that the new contents are durable once
+// WriteFileAtomic returns.
+// If the file does not exist, WriteFileAtomic creates it with permissions
+// perm (before umask); otherwise the replacement is given the mode of the
+// existing file. If name is a symbolic link, the link itself is replaced.
+func WriteFileAtomic(name string, data []byte, perm FileMode) error {
+	mode := perm
+	replace := false
+	if fi, err := Lstat(name); err == nil && fi.Mode().IsRegular() {
+		mode = fi.Mode() & (ModePerm | ModeSetuid | ModeSetgid | ModeSticky)
+		replace = true
+	}
+
+	dir := filepathlite.Dir(name)
+	f, err := OpenAnonymous(dir, mode)
+	if err != nil {
+		return err
+	}
+	// Close discards f unless LinkTo has succeeded.
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	// The umask applies to the file created by OpenAnonymous,
+	// but the replacement should match the existing file exactly.
+	if replace {
+		if err := f.Chmod(mode); err != nil {
+			return err
+		}
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.LinkTo(name); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir commits the directory entries of dir to stable storage.
+// Windows cannot sync a directory, and renames there are
+// journaled by the file system.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := Open(dir)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if err1 := d.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+	return err
+}
+
+// CopyFile copies the contents of the file named src to the file named dst.
+// If dst does not exist, it is created; if it exists, it is truncated
+// before the copy. In both cases dst is given the permission bits and
+// the [ModeSticky] bit of src. Like cp(1), CopyFile does not copy the
+// [ModeSetuid] and [ModeSetgid] bits, which would let the new file,
+// owned by the caller, run with privileges that src does not grant.
+// The copy uses the same system-specific fast paths as [File.ReadFrom],
+// such as a reflink clone or copy_file_range on Linux, so holes in a
+// sparse src are preserved when the underlying mechanism supports it.
+// If the copy fails after dst has been opened, dst is removed, so that
+// no partial copy is left behind.
+//
+// On macOS, CopyFile first tries to create dst as a copy-on-write clone
+// of src with clonefile(2), which takes constant time on APFS. As clones
+// are always new files, an existing dst is then replaced, as by [Rename],
+// rather than truncated, so other hard links to it keep the old contents.
+//
+// CopyFile only copies regular files. If src and dst refer to the
+// same file, CopyFile returns an error without modifying it.
+// If there is an error, it will be of type *PathError, naming the
+// path on which the failing operation was performed.
+func CopyFile(dst, src string) error {
+	return copyFile(dst, src, false, nil)
+}
+
+// CopyFileProgress is like [CopyFile], but calls progress as the copy
+// proceeds with the number of bytes copied so far and the size of src
+// when the copy started. The copy still uses the fast paths of CopyFile,
+// in chunks of a few megabytes so that progress is called after each
+// one; a copy that completes in a single step, such as a clone, calls
+// progress once. The last call reports copied equal to the number of
+// bytes written to dst, which exceeds total if src grew meanwhile.
+// Progress is called at least once, even for an empty src.
+//
+// If progress is nil, CopyFileProgress is equivalent to CopyFile.
+func CopyFileProgress(dst, src string, progress func(copied, total int64)) error {
+	return copyFile(dst, src, false, progress)
+}
+
+// copyFile implements CopyFile and CopyFileProgress. If move is set, as
+// when RenameAcrossFS moves src, dst keeps the ModeSetuid and ModeSetgid
+// bits of src, and it is synced before it is closed.
+func copyFile(dst, src string, move bool, progress func(copied, total int64)) error {
+	in, err := Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return &PathError{Op: "copyfile", Path: src, Err: ErrInvalid}
+	}
+	if dinfo, err := Stat(dst); err == nil && SameFile(info, dinfo) {
+		return &PathError{Op: "copyfile", Path: dst, Err: ErrExist}
+	}
+
+	mode := info.Mode() & (ModePerm | ModeSticky)
+	if move {
+		mode |= info.Mode() & (ModeSetuid | ModeSetgid)
+	}
+	if ok, err := cloneFile(dst, in, mode); ok {
+		if err == nil && move {
+			err = syncFile(dst)
+		}
+		if err == nil && progress != nil {
+			progress(info.Size(), info.Size())
+		}
+		return err
+	}
+	out, err := OpenFile(dst, O_WRONLY|O_CREATE|O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	err = copyOpenFile(out, in, info.Size(), mode, move, progress)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		Remove(dst)
+	}
+	return err
+}
+
+// copyOpenFile copies the contents of in to out for copyFile, and gives
+// out the mode mode.
+func copyOpenFile(out, in *File, size int64, mode FileMode, sync bool, progress func(copied, total int64)) error {
+	var err error
+	if progress == nil {
+		_, err = io.Copy(out, in)
+	} else {
+		err = copyWithProgress(out, in, size, progress)
+	}
+	if err != nil {
+		return err
+	}
+	// OpenFile leaves the mode of an existing file alone,
+	// and applies the umask to a new one.
+	if err := out.Chmod(mode); err != nil {
+		return err
+	}
+	if sync {
+		return out.Sync()
+	}
+	return nil
+}
+
+// copyProgressChunk is the amount of data copyWithProgress copies
+// between calls to its progress function.
+const copyProgressChunk = 8 << 20
+
+// copyWithProgress copies in to out like io.Copy, going through
+// out.ReadFrom one chunk at a time so that each chunk still takes
+// the fast paths, and reports progress after each chunk. A clone of
+// the whole file, which chunks would rule out, is tried first.
+func copyWithProgress(out, in *File, total int64, progress func(copied, total int64)) error {
+	if n, handled, err := out.cloneFrom(in); handled {
+		if err != nil {
+			return out.wrapErr("write", err)
+		}
+		progress(n, total)
+		return nil
+	}
+
+	var copied int64
+	lr := &io.LimitedReader{R: in}
+	for {
+		lr.N = copyProgressChunk
+		n, err := out.ReadFrom(lr)
+		copied += n
+		if err != nil {
+			return err
+		}
+		if n > 0 || copied == 0 {
+			progress(copied, total)
+		}
+		if lr.N > 0 {
+			// in is at EOF.
+			return nil
+		}
+	}
+}
+
+// syncFile commits the contents of the named file to stable storage.
+func syncFile(name string) error {
+	f, err := Open(name)
+	if err != nil {
+		return err
+	}
+	err = f.Sync()
+	if err1 := f.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+	return err
+}
+
+// RenameAcrossFS renames (moves) oldpath to newpath like [Rename], but
+// also works when they are on different file systems. If Rename fails
+// because of that, RenameAcrossFS copies oldpath to newpath instead, as
+// by [CopyFile] for regular files, recreating directories recursively
+// and symbolic links as links. Permission bits and modification times
+// are preserved, and the copy is synced to stable storage before
+// oldpath is removed.
+//
+// The copy is made under a temporary name in the directory of newpath
+// and then renamed to newpath, so if the copy fails, oldpath and any
+// existing newpath are left in place. As with Rename, an existing file
+// at newpath is replaced, and a symbolic link there is replaced rather
+// than followed, but an existing directory is not replaced.
+// Unlike Rename, the copy fallback does not move oldpath atomically.
+func RenameAcrossFS(oldpath, newpath string) error {
+	err := renameAcrossFSRename(oldpath, newpath)
+	if err == nil || !isCrossDeviceError(err) {
+		return err
+	}
+	fi, err := Lstat(oldpath)
+	if err != nil {
+		return err
+	}
+	if nfi, err := Lstat(newpath); err == nil && nfi.IsDir() {
+		return &LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EEXIST}
+	}
+	dir := filepathlite.Dir(newpath)
+	staging, err := MkdirTemp(dir, ".rename")
+	if err != nil {
+		return err
+	}
+	defer RemoveAll(staging)
+	tmp := joinPath(staging, filepathlite.Base(newpath))
+	if err := copyTree(tmp, oldpath, fi); err != nil {
+		return err
+	}
+	if err := Rename(tmp, newpath); err != nil {
+		return err
+	}
+	if err := syncDir(dir); err != nil {
+		return err
+	}
+	return RemoveAll(oldpath)
+}
+
+// renameAcrossFSRename is overridden in tests.
+var renameAcrossFSRename = Rename
+
+// copyTree copies src, described by fi, to dst for RenameAcrossFS.
+// The file dst must not exist.
+func copyTree(dst, src string, fi FileInfo) error {
+	mode := fi.Mode()
+	switch {
+	case mode.IsRegular():
+		if err := copyFile(dst, src, true, nil); err != nil {
+			return err
+		}
+	case mode&ModeSymlink != 0:
+		target, err := Readlink(src)
+		if err != nil {
+			return err
+		}
+		// The link itself keeps the times it was created with.
+		return Symlink(target, dst)
+	case mode.IsDir():
+		// Create the directory writable so it can be filled in,
+		// and give it its final mode once it is complete.
+		if err := Mkdir(dst, 0o700); err != nil {
+			return err
+		}
+		entries, err := ReadDir(src)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			info, err := e.Info()
+			if err != nil {
+				return err
+			}
+			if err := copyTree(joinPath(dst, e.Name()), joinPath(src, e.Name()), info); err != nil {
+				return err
+			}
+		}
+		if err := Chmod(dst, mode&(ModePerm|ModeSetgid|ModeSticky)); err != nil {
+			return err
+		}
+		if err := syncDir(dst); err != nil {
+			return err
+		}
+	default:
+		return &PathError{Op: "rename", Path: src, Err: ErrInvalid}
+	}
+	return Chtimes(dst, atime(fi), fi.ModTime())
+}
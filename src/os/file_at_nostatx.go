@@ -0,0 +1,13 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix && !linux
+
+package os
+
+import "internal/syscall/unix"
+
+func fstatatFile(dirfd int, name string, fs *fileStat, flags int) error {
+	return unix.Fstatat(dirfd, name, &fs.sys, flags)
+}
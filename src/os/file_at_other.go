@@ -0,0 +1,119 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !unix
+
+package os
+
+import (
+	"errors"
+	"runtime"
+	"syscall"
+)
+
+// On systems without openat, the methods that resolve names relative
+// to a directory use the name the directory was opened with.
+
+func (f *File) openAt(name string, flag int, perm FileMode) (*File, error) {
+	nf, err := openFileNolog(joinPath(f.name, name), flag, perm)
+	if err != nil {
+		return nil, atError("openat", err)
+	}
+	return nf, nil
+}
+
+// openDirAt opens the directory name relative to f for WalkDir.
+func (f *File) openDirAt(name string) (*File, error) {
+	nf, err := openDirNolog(joinPath(f.name, name))
+	if err != nil {
+		return nil, atError("openat", err)
+	}
+	return nf, nil
+}
+
+// openDirNoFollow opens the directory name, failing if name is not a
+// directory, including if it is a symbolic link to one.
+func openDirNoFollow(name string) (*File, error) {
+	fi, err := lstatNolog(name)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return nil, &PathError{Op: "open", Path: name, Err: syscall.ENOTDIR}
+	}
+	return openDirNolog(name)
+}
+
+func (f *File) statAt(name string, followSymlinks bool) (FileInfo, error) {
+	var (
+		fi  FileInfo
+		err error
+	)
+	if followSymlinks {
+		fi, err = statNolog(joinPath(f.name, name))
+	} else {
+		fi, err = lstatNolog(joinPath(f.name, name))
+	}
+	if err != nil {
+		return nil, atError("statat", err)
+	}
+	return fi, nil
+}
+
+func (f *File) mkdirAt(name string, perm FileMode) error {
+	if err := Mkdir(joinPath(f.name, name), perm); err != nil {
+		return atError("mkdirat", err)
+	}
+	return nil
+}
+
+// atError rewrites the operation of the *PathError err to op,
+// so that errors match those of systems with openat.
+func atError(op string, err error) error {
+	if pe, ok := err.(*PathError); ok {
+		pe.Op = op
+	}
+	return err
+}
+
+func (f *File) readlinkAt(name string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return "", &PathError{Op: "readlinkat", Path: joinPath(f.name, name), Err: errors.ErrUnsupported}
+	}
+	s, err := readlink(joinPath(f.name, name))
+	if err != nil {
+		return "", atError("readlinkat", err)
+	}
+	return s, nil
+}
+
+func (f *File) chmodAt(name string, mode FileMode, followSymlink bool) error {
+	path := joinPath(f.name, name)
+	if !followSymlink {
+		fi, err := lstatNolog(path)
+		if err != nil {
+			return atError("chmodat", err)
+		}
+		if fi.Mode()&ModeSymlink != 0 {
+			return &PathError{Op: "chmodat", Path: path, Err: errors.ErrUnsupported}
+		}
+	}
+	if err := chmod(path, mode); err != nil {
+		return atError("chmodat", err)
+	}
+	return nil
+}
+
+func (f *File) chownAt(name string, uid, gid int, followSymlink bool) error {
+	var err error
+	if followSymlink {
+		err = Chown(joinPath(f.name, name), uid, gid)
+	} else {
+		err = Lchown(joinPath(f.name, name), uid, gid)
+	}
+	if err != nil {
+		return atError("chownat", err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+	"syscall"
+)
+
+func (f *File) openAt(name string, flag int, perm FileMode) (*File, error) {
+	var (
+		r int
+		e error
+	)
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		// We have to check EINTR here, per issues 11180 and 39237.
+		e = ignoringEINTR(func() (err error) {
+			r, err = unix.Openat(int(fd), name, flag|syscall.O_CLOEXEC, syscallMode(perm))
+			return err
+		})
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		e = cerr
+	}
+	if e != nil {
+		return nil, &PathError{Op: "openat", Path: joinPath(f.name, name), Err: e}
+	}
+
+	// There's a race here with fork/exec, which we are
+	// content to live with. See ../syscall/exec_unix.go.
+	if !supportsCloseOnExec {
+		syscall.CloseOnExec(r)
+	}
+
+	return newFile(r, joinPath(f.name, name), kindOpenFile, unix.HasNonblockFlag(flag)), nil
+}
+
+// openDirAt opens the directory name relative to f for WalkDir.
+// If name is not a directory, including if it is a symbolic link to one,
+// it returns an error.
+func (f *File) openDirAt(name string) (*File, error) {
+	var (
+		r int
+		e error
+	)
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		r, e = openDirAt(int(fd), name)
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		e = cerr
+	}
+	if e != nil {
+		return nil, &PathError{Op: "openat", Path: joinPath(f.name, name), Err: e}
+	}
+	// We use kindNoPoll because we know that this is a directory.
+	return newFile(r, joinPath(f.name, name), kindNoPoll, false), nil
+}
+
+// openDirNoFollow opens the directory name, like openDirAt relative to
+// the current directory.
+func openDirNoFollow(name string) (*File, error) {
+	r, e := openDirAt(unix.AT_FDCWD, name)
+	if e != nil {
+		return nil, &PathError{Op: "open", Path: name, Err: e}
+	}
+	return newFile(r, name, kindNoPoll, false), nil
+}
+
+func (f *File) statAt(name string, followSymlinks bool) (FileInfo, error) {
+	flags := 0
+	if !followSymlinks {
+		flags = unix.AT_SYMLINK_NOFOLLOW
+	}
+	var fs fileStat
+	var e error
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		e = ignoringEINTR(func() error {
+			return fstatatFile(int(fd), name, &fs, flags)
+		})
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		e = cerr
+	}
+	if e != nil {
+		return nil, &PathError{Op: "statat", Path: joinPath(f.name, name), Err: e}
+	}
+	fillFileStatFromSys(&fs, name)
+	return &fs, nil
+}
+
+func (f *File) mkdirAt(name string, perm FileMode) error {
+	var e error
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		e = ignoringEINTR(func() error {
+			return unix.Mkdirat(int(fd), name, syscallMode(perm))
+		})
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		e = cerr
+	}
+	if e != nil {
+		return &PathError{Op: "mkdirat", Path: joinPath(f.name, name), Err: e}
+	}
+	return nil
+}
+
+func (f *File) readlinkAt(name string) (string, error) {
+	var (
+		s string
+		e error
+	)
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		s, e = readlinkat(int(fd), name)
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		e = cerr
+	}
+	if e != nil {
+		return "", &PathError{Op: "readlinkat", Path: joinPath(f.name, name), Err: e}
+	}
+	return s, nil
+}
+
+func (f *File) chmodAt(name string, mode FileMode, followSymlink bool) error {
+	flags := 0
+	if !followSymlink {
+		flags = unix.AT_SYMLINK_NOFOLLOW
+	}
+	var e error
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		e = ignoringEINTR(func() error {
+			return unix.Fchmodat(int(fd), name, syscallMode(mode), flags)
+		})
+		if e == syscall.EOPNOTSUPP && flags != 0 {
+			// Linux before 6.6 rejects AT_SYMLINK_NOFOLLOW even if
+			// name is not a symbolic link.
+			e = chmodAtNofollow(int(fd), name, syscallMode(mode))
+		}
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		e = cerr
+	}
+	if e != nil {
+		return &PathError{Op: "chmodat", Path: joinPath(f.name, name), Err: e}
+	}
+	return nil
+}
+
+func (f *File) chownAt(name string, uid, gid int, followSymlink bool) error {
+	flags := 0
+	if !followSymlink {
+		flags = unix.AT_SYMLINK_NOFOLLOW
+	}
+	var e error
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		e = ignoringEINTR(func() error {
+			return unix.Fchownat(int(fd), name, uid, gid, flags)
+		})
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		e = cerr
+	}
+	if e != nil {
+		return &PathError{Op: "chownat", Path: joinPath(f.name, name), Err: e}
+	}
+	return nil
+}
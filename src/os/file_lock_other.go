@@ -0,0 +1,17 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !darwin && !dragonfly && !freebsd && !illumos && !linux && !netbsd && !openbsd && !windows
+
+package os
+
+import "errors"
+
+func (f *File) lock(wait bool) (bool, error) {
+	return false, f.wrapErr("lock", errors.ErrUnsupported)
+}
+
+func (f *File) unlock() error {
+	return f.wrapErr("unlock", errors.ErrUnsupported)
+}
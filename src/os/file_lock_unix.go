@@ -0,0 +1,49 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || illumos || linux || netbsd || openbsd
+
+package os
+
+import (
+	"runtime"
+	"syscall"
+)
+
+// lock implements File.Lock and File.TryLock using flock(2).
+func (f *File) lock(wait bool) (bool, error) {
+	how := syscall.LOCK_EX
+	if !wait {
+		how |= syscall.LOCK_NB
+	}
+	if err := f.flock(how); err != nil {
+		if !wait && err == syscall.EWOULDBLOCK {
+			return false, nil
+		}
+		return false, f.wrapErr("lock", err)
+	}
+	return true, nil
+}
+
+// unlock implements File.Unlock.
+func (f *File) unlock() error {
+	if err := f.flock(syscall.LOCK_UN); err != nil {
+		return f.wrapErr("unlock", err)
+	}
+	return nil
+}
+
+func (f *File) flock(how int) error {
+	var err error
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		err = ignoringEINTR(func() error {
+			return syscall.Flock(int(fd), how)
+		})
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		return cerr
+	}
+	return err
+}
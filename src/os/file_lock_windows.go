@@ -0,0 +1,56 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/windows"
+	"runtime"
+	"syscall"
+)
+
+// allBytes is the length passed to LockFileEx and UnlockFileEx,
+// as both the low and high word, to cover the whole file.
+const allBytes = ^uint32(0)
+
+// lock implements File.Lock and File.TryLock using LockFileEx.
+func (f *File) lock(wait bool) (bool, error) {
+	flags := uint32(windows.LOCKFILE_EXCLUSIVE_LOCK)
+	if !wait {
+		flags |= windows.LOCKFILE_FAIL_IMMEDIATELY
+	}
+	var err error
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		// LockFileEx requires an OVERLAPPED structure holding the
+		// offset of the start of the range; zero locks the whole file.
+		err = windows.LockFileEx(syscall.Handle(fd), flags, 0, allBytes, allBytes, new(syscall.Overlapped))
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		err = cerr
+	}
+	if err != nil {
+		if !wait && err == windows.ERROR_LOCK_VIOLATION {
+			return false, nil
+		}
+		return false, f.wrapErr("lock", err)
+	}
+	return true, nil
+}
+
+// unlock implements File.Unlock.
+func (f *File) unlock() error {
+	var err error
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		err = windows.UnlockFileEx(syscall.Handle(fd), 0, allBytes, allBytes, new(syscall.Overlapped))
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		err = cerr
+	}
+	if err != nil {
+		return f.wrapErr("unlock", err)
+	}
+	return nil
+}
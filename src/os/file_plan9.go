@@ -5,6 +5,7 @@
 package os
 
 import (
+	"errors"
 	"internal/bytealg"
 	"internal/poll"
 	"internal/stringslite"
@@ -29,8 +30,9 @@ type file struct {
 	fdmu       poll.FDMutex
 	fd         int
 	name       string
-	dirinfo    atomic.Pointer[dirInfo] // nil unless directory being read
-	appendMode bool                    // whether file is opened for appending
+	dirinfo    atomic.Pointer[dirInfo]   // nil unless directory being read
+	appendMode bool                      // whether file is opened for appending
+	extra      atomic.Pointer[fileExtra] // nil unless needed; see fileExtra
 }
 
 // Fd returns the integer Plan 9 file descriptor referencing the open file.
@@ -61,6 +63,42 @@ func NewFile(fd uintptr, name string) *File {
 	return f
 }
 
+func isTerminal(fd uintptr) bool {
+	return false
+}
+
+func (f *File) isTerminal() bool {
+	return false
+}
+
+func (f *File) terminalSize() (cols, rows int, err error) {
+	return 0, 0, syscall.EPLAN9
+}
+
+func newFileWithKind(fd uintptr, name string) (*File, FileKind, error) {
+	f := NewFile(fd, name)
+	if f == nil {
+		return nil, FileKindUnknown, &PathError{Op: "stat", Path: name, Err: syscall.EINVAL}
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		// Leave fd open for the caller.
+		runtime.SetFinalizer(f.file, nil)
+		return nil, FileKindUnknown, err
+	}
+	switch m := fi.Mode(); {
+	case m.IsRegular():
+		return f, FileKindRegular, nil
+	case m.IsDir():
+		return f, FileKindDir, nil
+	case m&ModeNamedPipe != 0:
+		return f, FileKindPipe, nil
+	case m&ModeDevice != 0:
+		return f, FileKindDevice, nil
+	}
+	return f, FileKindUnknown, nil
+}
+
 // Auxiliary information if the File describes a directory
 type dirInfo struct {
 	mu   sync.Mutex
@@ -91,6 +129,16 @@ func syscallMode(i FileMode) (o uint32) {
 	return
 }
 
+// stdinPollable is the Plan 9 implementation of StdinPollable.
+func stdinPollable() (*File, error) {
+	return nil, &PathError{Op: "stdinpollable", Path: Stdin.name, Err: errors.ErrUnsupported}
+}
+
+// openFileNonblock is the Plan 9 implementation of OpenFileNonblock.
+func openFileNonblock(name string, flag int, perm FileMode) (*File, error) {
+	return OpenFile(name, flag, perm)
+}
+
 // openFileNolog is the Plan 9 implementation of OpenFile.
 func openFileNolog(name string, flag int, perm FileMode) (*File, error) {
 	var (
@@ -155,7 +203,9 @@ func (f *File) Close() error {
 	if f == nil {
 		return ErrInvalid
 	}
-	return f.file.close()
+	err := f.file.close()
+	f.removeAnonymous()
+	return err
 }
 
 func (file *file) close() error {
@@ -285,6 +335,21 @@ func (f *File) Sync() error {
 	return nil
 }
 
+// Datasync is like [File.Sync]; Plan 9 has no separate way
+// to commit only a file's data.
+func (f *File) Datasync() error {
+	if f == nil {
+		return ErrInvalid
+	}
+	if err := f.Sync(); err != nil {
+		if pe, ok := err.(*PathError); ok {
+			pe.Op = "datasync"
+		}
+		return err
+	}
+	return nil
+}
+
 // read reads up to len(b) bytes from the File.
 // It returns the number of bytes read and an error, if any.
 func (f *File) read(b []byte) (n int, err error) {
@@ -356,6 +421,9 @@ func (f *File) seek(offset int64, whence int) (ret int64, err error) {
 	// Free cached dirinfo, so we allocate a new one if we
 	// access this file as a directory again. See #35767 and #37161.
 	f.dirinfo.Store(nil)
+	if e := f.extra.Load(); e != nil {
+		e.sorted.Store(nil)
+	}
 	return syscall.Seek(f.fd, offset, whence)
 }
 
@@ -425,6 +493,13 @@ func rename(oldname, newname string) error {
 	return nil
 }
 
+// isCrossDeviceError reports whether err, as returned by rename,
+// means that oldname and newname are on different file systems.
+// Plan 9 reports no such distinct error.
+func isCrossDeviceError(err error) bool {
+	return false
+}
+
 // See docs in file.go:Chmod.
 func chmod(name string, mode FileMode) error {
 	var d syscall.Dir
@@ -490,6 +565,13 @@ func Pipe() (r *File, w *File, err error) {
 	return NewFile(uintptr(p[0]), "|0"), NewFile(uintptr(p[1]), "|1"), nil
 }
 
+func pipe2(flags int) (r *File, w *File, err error) {
+	if flags&PipeCloexec != 0 {
+		return nil, nil, NewSyscallError("pipe2", syscall.EPLAN9)
+	}
+	return Pipe()
+}
+
 // not supported on Plan 9
 
 // Link creates newname as a hard link to the oldname file.
@@ -498,9 +580,14 @@ func Link(oldname, newname string) error {
 	return &LinkError{"link", oldname, newname, syscall.EPLAN9}
 }
 
+func linkFollow(oldname, newname string, followSymlink bool) error {
+	return &LinkError{"link", oldname, newname, syscall.EPLAN9}
+}
+
 // Symlink creates newname as a symbolic link to oldname.
 // On Windows, a symlink to a non-existent oldname creates a file symlink;
 // if oldname is later created as a directory the symlink will not work.
+// Use [SymlinkDir] to create a link to a directory that does not exist yet.
 // If there is an error, it will be of type *LinkError.
 func Symlink(oldname, newname string) error {
 	return &LinkError{"symlink", oldname, newname, syscall.EPLAN9}
@@ -617,3 +704,7 @@ func newRawConn(file *File) (*rawConn, error) {
 func ignoringEINTR(fn func() error) error {
 	return fn()
 }
+
+func umask(mask int) int {
+	return 0
+}
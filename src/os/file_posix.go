@@ -20,7 +20,9 @@ func (f *File) Close() error {
 	if f == nil {
 		return ErrInvalid
 	}
-	return f.file.close()
+	err := f.file.close()
+	f.removeAnonymous()
+	return err
 }
 
 // read reads up to len(b) bytes from the File.
@@ -56,6 +58,16 @@ func (f *File) pwrite(b []byte, off int64) (n int, err error) {
 	return n, err
 }
 
+func (f *File) isTerminal() bool {
+	ok := false
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		ok = isTerminal(fd)
+	}); err != nil {
+		return false
+	}
+	return ok
+}
+
 // syscallMode returns the syscall-specific mode bits from Go's portable mode bits.
 func syscallMode(i FileMode) (o uint32) {
 	o |= uint32(i.Perm())
@@ -169,6 +181,22 @@ func (f *File) Sync() error {
 	return nil
 }
 
+// Datasync is like [File.Sync], but it does not flush metadata,
+// such as the modification time, that is not needed to read the
+// file's contents back correctly. This can make it substantially
+// faster than Sync when the file's size has not changed.
+// Datasync uses fdatasync on Linux, FreeBSD and NetBSD; on other
+// systems it behaves exactly like Sync.
+func (f *File) Datasync() error {
+	if err := f.checkValid("datasync"); err != nil {
+		return err
+	}
+	if e := f.pfd.Fdatasync(); e != nil {
+		return f.wrapErr("datasync", e)
+	}
+	return nil
+}
+
 // Chtimes changes the access and modification times of the named
 // file, similar to the Unix utime() or utimes() functions.
 // A zero [time.Time] value will leave the corresponding file time unchanged.
@@ -195,6 +223,9 @@ func Chtimes(name string, atime time.Time, mtime time.Time) error {
 
 // Chdir changes the current working directory to the file,
 // which must be a directory.
+// On Unix systems, Chdir uses fchdir(2), so it changes to the directory
+// f refers to even if it was renamed after f was opened, and later calls
+// to [Getwd] report its new name.
 // If there is an error, it will be of type [*PathError].
 func (f *File) Chdir() error {
 	if err := f.checkValid("chdir"); err != nil {
@@ -203,6 +234,7 @@ func (f *File) Chdir() error {
 	if e := f.pfd.Fchdir(); e != nil {
 		return f.wrapErr("chdir", e)
 	}
+	clearGetwdCache()
 	return nil
 }
 
@@ -211,7 +243,7 @@ func (f *File) setDeadline(t time.Time) error {
 	if err := f.checkValid("SetDeadline"); err != nil {
 		return err
 	}
-	return f.pfd.SetDeadline(t)
+	return f.deadlineError(f.pfd.SetDeadline(t))
 }
 
 // setReadDeadline sets the read deadline.
@@ -219,7 +251,7 @@ func (f *File) setReadDeadline(t time.Time) error {
 	if err := f.checkValid("SetReadDeadline"); err != nil {
 		return err
 	}
-	return f.pfd.SetReadDeadline(t)
+	return f.deadlineError(f.pfd.SetReadDeadline(t))
 }
 
 // setWriteDeadline sets the write deadline.
@@ -227,7 +259,7 @@ func (f *File) setWriteDeadline(t time.Time) error {
 	if err := f.checkValid("SetWriteDeadline"); err != nil {
 		return err
 	}
-	return f.pfd.SetWriteDeadline(t)
+	return f.deadlineError(f.pfd.SetWriteDeadline(t))
 }
 
 // checkValid checks whether f is valid for use.
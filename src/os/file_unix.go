@@ -7,12 +7,14 @@
 package os
 
 import (
+	"errors"
 	"internal/poll"
 	"internal/syscall/unix"
 	"io/fs"
 	"runtime"
 	"sync/atomic"
 	"syscall"
+	"time"
 	_ "unsafe" // for go:linkname
 )
 
@@ -52,6 +54,12 @@ func rename(oldname, newname string) error {
 	return nil
 }
 
+// isCrossDeviceError reports whether err, as returned by rename,
+// means that oldname and newname are on different file systems.
+func isCrossDeviceError(err error) bool {
+	return underlyingErrorIs(err, syscall.EXDEV)
+}
+
 // file is the real representation of *File.
 // The extra level of indirection ensures that no clients of os
 // can overwrite this data, which could cause the finalizer
@@ -59,10 +67,14 @@ func rename(oldname, newname string) error {
 type file struct {
 	pfd         poll.FD
 	name        string
-	dirinfo     atomic.Pointer[dirInfo] // nil unless directory being read
-	nonblock    bool                    // whether we set nonblocking mode
-	stdoutOrErr bool                    // whether this is stdout or stderr
-	appendMode  bool                    // whether file is opened for appending
+	dirinfo     atomic.Pointer[dirInfo]   // nil unless directory being read
+	nonblock    bool                      // whether we set nonblocking mode
+	resetBlock  bool                      // whether to clear nonblocking mode when closing
+	noDeadline  bool                      // whether a deadline error matches errors.ErrUnsupported
+	stdoutOrErr bool                      // whether this is stdout or stderr
+	appendMode  bool                      // whether file is opened for appending
+	maybeSock   bool                      // whether the descriptor may be a socket
+	extra       atomic.Pointer[fileExtra] // nil unless needed; see fileExtra
 }
 
 // Fd returns the integer Unix file descriptor referencing the open file.
@@ -118,6 +130,71 @@ func NewFile(fd uintptr, name string) *File {
 	return f
 }
 
+func isTerminal(fd uintptr) bool {
+	return unix.IsTerminal(int(fd))
+}
+
+func (f *File) terminalSize() (cols, rows int, err error) {
+	var ws *unix.Winsize
+	if cerr := f.pfd.RawControl(func(fd uintptr) {
+		ws, err = unix.GetWinsize(int(fd))
+	}); cerr != nil {
+		return 0, 0, cerr
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
+
+func newFileWithKind(fd uintptr, name string) (*File, FileKind, error) {
+	fdi := int(fd)
+	if fdi < 0 {
+		return nil, FileKindUnknown, &PathError{Op: "fstat", Path: name, Err: syscall.EBADF}
+	}
+	var st syscall.Stat_t
+	if err := ignoringEINTR(func() error {
+		return syscall.Fstat(fdi, &st)
+	}); err != nil {
+		return nil, FileKindUnknown, &PathError{Op: "fstat", Path: name, Err: err}
+	}
+
+	fk, kind := statFileKind(fdi, &st)
+	flags, err := unix.Fcntl(fdi, syscall.F_GETFL, 0)
+	if err != nil {
+		flags = 0
+	}
+	f := newFile(fdi, name, kind, unix.HasNonblockFlag(flags))
+	f.appendMode = flags&syscall.O_APPEND != 0
+	f.maybeSock = fk == FileKindSocket
+	return f, fk, nil
+}
+
+// statFileKind returns the FileKind of the descriptor fd, whose status
+// is st, and the kind to pass to newFile for it.
+func statFileKind(fd int, st *syscall.Stat_t) (FileKind, newFileKind) {
+	switch st.Mode & syscall.S_IFMT {
+	case syscall.S_IFREG:
+		return FileKindRegular, kindNewFile
+	case syscall.S_IFDIR:
+		return FileKindDir, kindNewFile
+	case syscall.S_IFIFO:
+		// See the comment on FIFOs in newFile.
+		if runtime.GOOS == "darwin" || runtime.GOOS == "ios" {
+			return FileKindPipe, kindNewFile
+		}
+		return FileKindPipe, kindPipe
+	case syscall.S_IFSOCK:
+		return FileKindSocket, kindPipe
+	case syscall.S_IFCHR, syscall.S_IFBLK:
+		if isTerminal(uintptr(fd)) {
+			return FileKindTerminal, kindNewFile
+		}
+		return FileKindDevice, kindNewFile
+	}
+	return FileKindUnknown, kindNewFile
+}
+
 // net_newUnixFile is a hidden entry point called by net.conn.File.
 // This is used so that a nonblocking network connection will become
 // blocking if code calls the Fd method. We don't want that for direct
@@ -168,6 +245,9 @@ func newFile(fd int, name string, kind newFileKind, nonBlocking bool) *File {
 		},
 		name:        name,
 		stdoutOrErr: fd == 1 || fd == 2,
+		// Only descriptors from the net package or passed to
+		// NewFile can be sockets.
+		maybeSock: kind == kindSock || kind == kindNewFile,
 	}}
 
 	pollable := kind == kindOpenFile || kind == kindPipe || kind == kindSock || nonBlocking
@@ -262,10 +342,10 @@ const DevNull = "/dev/null"
 // openFileNolog is the Unix implementation of OpenFile.
 // Changes here should be reflected in openDirAt and openDirNolog, if relevant.
 func openFileNolog(name string, flag int, perm FileMode) (*File, error) {
-	setSticky := false
-	if !supportsCreateWithStickyBit && flag&O_CREATE != 0 && perm&ModeSticky != 0 {
+	setSpecial := false
+	if flag&O_CREATE != 0 && perm&modeSpecial != 0 {
 		if _, err := Stat(name); IsNotExist(err) {
-			setSticky = true
+			setSpecial = true
 		}
 	}
 
@@ -283,11 +363,6 @@ func openFileNolog(name string, flag int, perm FileMode) (*File, error) {
 		return nil, &PathError{Op: "open", Path: name, Err: e}
 	}
 
-	// open(2) itself won't handle the sticky bit on *BSD and Solaris
-	if setSticky {
-		setStickyBit(name)
-	}
-
 	// There's a race here with fork/exec, which we are
 	// content to live with. See ../syscall/exec_unix.go.
 	if !supportsCloseOnExec {
@@ -296,9 +371,135 @@ func openFileNolog(name string, flag int, perm FileMode) (*File, error) {
 
 	f := newFile(r, name, kindOpenFile, unix.HasNonblockFlag(flag))
 	f.pfd.SysFile = s
+
+	// open(2) itself may drop the special bits of a new file: *BSD and
+	// Solaris ignore the sticky bit. Add them back with fchmod, which
+	// drops those the caller may not set, such as the setgid bit for a
+	// group it is not a member of. On *BSD, fchmod fails with EFTYPE
+	// instead when an unprivileged user asks for the sticky bit of a
+	// regular file, so try again without it.
+	if setSpecial {
+		if err := f.addSpecialBits(perm); err != nil {
+			f.Close()
+			if flag&O_EXCL != 0 {
+				Remove(name)
+			}
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// addSpecialBits adds the bits of perm in modeSpecial to the mode of
+// the file f, just created by openFileNolog, if any of them is missing.
+func (f *File) addSpecialBits(perm FileMode) error {
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	want := perm & modeSpecial
+	if fi.Mode()&want == want {
+		return nil
+	}
+	err = f.Chmod(fi.Mode() | want)
+	if err != nil && want&ModeSticky != 0 {
+		err = f.Chmod(fi.Mode() | want&^ModeSticky)
+	}
+	return err
+}
+
+// stdinPollable is the Unix implementation of StdinPollable.
+func stdinPollable() (*File, error) {
+	const name = "/dev/stdin"
+	var st syscall.Stat_t
+	if err := ignoringEINTR(func() error {
+		return syscall.Fstat(syscall.Stdin, &st)
+	}); err != nil {
+		return nil, &PathError{Op: "fstat", Path: name, Err: err}
+	}
+	fk, kind := statFileKind(syscall.Stdin, &st)
+	if fk == FileKindTerminal {
+		// newFile finds out whether the poller supports it.
+		kind = kindPipe
+	}
+
+	if fk == FileKindPipe || fk == FileKindTerminal {
+		if fd, ok := reopenStdin(); ok {
+			f := newFile(fd, name, kind, false)
+			f.noDeadline = f.pfd.SetReadDeadline(time.Time{}) == poll.ErrNoDeadline
+			return f, nil
+		}
+	}
+
+	fd, op, err := poll.DupCloseOnExec(syscall.Stdin)
+	if err != nil {
+		return nil, &PathError{Op: op, Path: name, Err: err}
+	}
+	// As for NewFile, a descriptor that is already in non-blocking
+	// mode is added to the poller, and left in that mode.
+	flags, err := unix.Fcntl(fd, syscall.F_GETFL, 0)
+	if err != nil {
+		flags = 0
+	}
+	f := newFile(fd, name, kind, unix.HasNonblockFlag(flags))
+	f.maybeSock = fk == FileKindSocket
+	// The duplicate shares its mode with Stdin.
+	f.resetBlock = f.nonblock
+	f.noDeadline = f.pfd.SetReadDeadline(time.Time{}) == poll.ErrNoDeadline
+	return f, nil
+}
+
+// errNoDeadlineUnsupported is returned by the SetDeadline methods of a
+// StdinPollable file that the poller does not support.
+var errNoDeadlineUnsupported error = noDeadlineError{}
+
+// noDeadlineError is ErrNoDeadline, but also matches
+// errors.ErrUnsupported.
+type noDeadlineError struct{}
+
+func (noDeadlineError) Error() string { return ErrNoDeadline.Error() }
+
+func (noDeadlineError) Is(target error) bool {
+	return target == ErrNoDeadline || target == errors.ErrUnsupported
+}
+
+// deadlineError returns err, the error from setting a deadline on f.pfd,
+// as errNoDeadlineUnsupported if f is a StdinPollable file that does not
+// support deadlines.
+func (f *File) deadlineError(err error) error {
+	if err == poll.ErrNoDeadline && f.noDeadline {
+		return errNoDeadlineUnsupported
+	}
+	return err
+}
+
+// openFileNonblock is the Unix implementation of OpenFileNonblock.
+func openFileNonblock(name string, flag int, perm FileMode) (*File, error) {
+	f, err := OpenFile(name, flag|unix.NonblockFlag, perm)
+	if err != nil {
+		return nil, err
+	}
+	// Setting a deadline fails if the file was not added to the poller,
+	// in which case I/O would fail with EAGAIN instead of waiting.
+	if f.pfd.SetReadDeadline(time.Time{}) == poll.ErrNoDeadline {
+		f.pfd.SetBlocking()
+	} else {
+		// Have Fd return a blocking descriptor, as for files
+		// that we put into non-blocking mode ourselves.
+		f.nonblock = true
+	}
 	return f, nil
 }
 
+// newPipeFile returns a File for one end of a pipe created by pipe2.
+func newPipeFile(fd int, name string, flags int) *File {
+	if flags&PipeNonblock == 0 {
+		// Leave the descriptor in blocking mode.
+		return newFile(fd, name, kindNoPoll, false)
+	}
+	return newFile(fd, name, kindPipe, false)
+}
+
 func openDirNolog(name string) (*File, error) {
 	var (
 		r int
@@ -329,6 +530,11 @@ func (file *file) close() error {
 	if info := file.dirinfo.Swap(nil); info != nil {
 		info.close()
 	}
+	if file.resetBlock {
+		// The descriptor shares its mode with the one it was
+		// duplicated from; see stdinPollable.
+		file.pfd.SetBlocking()
+	}
 	var err error
 	if e := file.pfd.Close(); e != nil {
 		if e == poll.ErrFileClosing {
@@ -352,6 +558,9 @@ func (f *File) seek(offset int64, whence int) (ret int64, err error) {
 		// access this file as a directory again. See #35767 and #37161.
 		info.close()
 	}
+	if e := f.extra.Load(); e != nil {
+		e.sorted.Store(nil)
+	}
 	ret, err = f.pfd.Seek(offset, whence)
 	runtime.KeepAlive(f)
 	return ret, err
@@ -432,6 +641,7 @@ func Link(oldname, newname string) error {
 // Symlink creates newname as a symbolic link to oldname.
 // On Windows, a symlink to a non-existent oldname creates a file symlink;
 // if oldname is later created as a directory the symlink will not work.
+// Use [SymlinkDir] to create a link to a directory that does not exist yet.
 // If there is an error, it will be of type *LinkError.
 func Symlink(oldname, newname string) error {
 	e := ignoringEINTR(func() error {
@@ -444,6 +654,24 @@ func Symlink(oldname, newname string) error {
 }
 
 func readlink(name string) (string, error) {
+	s, e := readlinkBuf(func(b []byte) (int, error) {
+		return syscall.Readlink(name, b)
+	})
+	if e != nil {
+		return "", &PathError{Op: "readlink", Path: name, Err: e}
+	}
+	return s, nil
+}
+
+// maxReadlinkLen is the size of the largest buffer readlinkBuf will try.
+// Link targets are limited to PATH_MAX bytes on most systems,
+// but some file systems permit longer ones.
+const maxReadlinkLen = 1 << 16
+
+// readlinkBuf calls readlink with growing buffers until the link target fits.
+// A result that fills the buffer may have been truncated, so the target
+// is only known to be complete when it is shorter than the buffer.
+func readlinkBuf(readlink func(b []byte) (int, error)) (string, error) {
 	for len := 128; ; len *= 2 {
 		b := make([]byte, len)
 		var (
@@ -451,21 +679,25 @@ func readlink(name string) (string, error) {
 			e error
 		)
 		for {
-			n, e = fixCount(syscall.Readlink(name, b))
+			n, e = fixCount(readlink(b))
 			if e != syscall.EINTR {
 				break
 			}
 		}
 		// buffer too small
 		if (runtime.GOOS == "aix" || runtime.GOOS == "wasip1") && e == syscall.ERANGE {
-			continue
+			e = nil
+			n = len
 		}
 		if e != nil {
-			return "", &PathError{Op: "readlink", Path: name, Err: e}
+			return "", e
 		}
 		if n < len {
 			return string(b[0:n]), nil
 		}
+		if len >= maxReadlinkLen {
+			return "", syscall.ENAMETOOLONG
+		}
 	}
 }
 
@@ -491,12 +723,20 @@ func (d *unixDirent) String() string {
 	return fs.FormatDirEntry(d)
 }
 
-func newUnixDirent(parent, name string, typ FileMode) (DirEntry, error) {
+// newUnixDirent returns the DirEntry for the named entry of parent.
+// If typ is ^FileMode(0), meaning that the directory does not record
+// the type, newUnixDirent calls lstat to determine it, unless noStat is
+// set, in which case the type is reported as ModeIrregular.
+func newUnixDirent(parent, name string, typ FileMode, noStat bool) (DirEntry, error) {
 	ude := &unixDirent{
 		parent: parent,
 		name:   name,
 		typ:    typ,
 	}
+	if typ == ^FileMode(0) && noStat {
+		ude.typ = ModeIrregular
+		return ude, nil
+	}
 	if typ != ^FileMode(0) && !testingForceReadDirLstat {
 		return ude, nil
 	}
@@ -510,3 +750,7 @@ func newUnixDirent(parent, name string, typ FileMode) (DirEntry, error) {
 	ude.info = info
 	return ude, nil
 }
+
+func umask(mask int) int {
+	return syscall.Umask(mask)
+}
@@ -27,8 +27,9 @@ const _UTIME_OMIT = -1
 type file struct {
 	pfd        poll.FD
 	name       string
-	dirinfo    atomic.Pointer[dirInfo] // nil unless directory being read
-	appendMode bool                    // whether file is opened for appending
+	dirinfo    atomic.Pointer[dirInfo]   // nil unless directory being read
+	appendMode bool                      // whether file is opened for appending
+	extra      atomic.Pointer[fileExtra] // nil unless needed; see fileExtra
 }
 
 // Fd returns the Windows handle referencing the open file.
@@ -93,10 +94,76 @@ func NewFile(fd uintptr, name string) *File {
 func epipecheck(file *File, e error) {
 }
 
+func isTerminal(fd uintptr) bool {
+	var m uint32
+	return syscall.GetConsoleMode(syscall.Handle(fd), &m) == nil
+}
+
+func (f *File) terminalSize() (cols, rows int, err error) {
+	var info windows.ConsoleScreenBufferInfo
+	if cerr := f.pfd.RawControl(func(fd uintptr) {
+		err = windows.GetConsoleScreenBufferInfo(syscall.Handle(fd), &info)
+	}); cerr != nil {
+		return 0, 0, cerr
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(info.Window.Right-info.Window.Left) + 1, int(info.Window.Bottom-info.Window.Top) + 1, nil
+}
+
+func newFileWithKind(fd uintptr, name string) (*File, FileKind, error) {
+	h := syscall.Handle(fd)
+	t, err := syscall.GetFileType(h)
+	if err != nil {
+		return nil, FileKindUnknown, &PathError{Op: "GetFileType", Path: name, Err: err}
+	}
+	fk := FileKindUnknown
+	switch t {
+	case syscall.FILE_TYPE_DISK:
+		var d syscall.ByHandleFileInformation
+		if err := syscall.GetFileInformationByHandle(h, &d); err != nil {
+			return nil, FileKindUnknown, &PathError{Op: "GetFileInformationByHandle", Path: name, Err: err}
+		}
+		fk = FileKindRegular
+		if d.FileAttributes&syscall.FILE_ATTRIBUTE_DIRECTORY != 0 {
+			fk = FileKindDir
+		}
+	case syscall.FILE_TYPE_PIPE:
+		// Sockets are reported as pipes too.
+		fk = FileKindPipe
+		if _, err := syscall.GetsockoptInt(h, syscall.SOL_SOCKET, windows.SO_TYPE); err == nil {
+			fk = FileKindSocket
+		}
+	case syscall.FILE_TYPE_CHAR:
+		fk = FileKindDevice
+		if isTerminal(fd) {
+			fk = FileKindTerminal
+		}
+	}
+	return newFile(h, name, "file"), fk, nil
+}
+
 // DevNull is the name of the operating system's “null device.”
 // On Unix-like systems, it is "/dev/null"; on Windows, "NUL".
 const DevNull = "NUL"
 
+// stdinPollable is the Windows implementation of StdinPollable.
+func stdinPollable() (*File, error) {
+	return nil, &PathError{Op: "stdinpollable", Path: Stdin.name, Err: errors.ErrUnsupported}
+}
+
+// deadlineError returns err, the error from setting a deadline on f.pfd.
+// Only the Unix StdinPollable files need to change it.
+func (f *File) deadlineError(err error) error {
+	return err
+}
+
+// openFileNonblock is the Windows implementation of OpenFileNonblock.
+func openFileNonblock(name string, flag int, perm FileMode) (*File, error) {
+	return OpenFile(name, flag, perm)
+}
+
 // openFileNolog is the Windows implementation of OpenFile.
 func openFileNolog(name string, flag int, perm FileMode) (*File, error) {
 	if name == "" {
@@ -155,6 +222,9 @@ func (f *File) seek(offset int64, whence int) (ret int64, err error) {
 		// access this file as a directory again. See #35767 and #37161.
 		info.close()
 	}
+	if e := f.extra.Load(); e != nil {
+		e.sorted.Store(nil)
+	}
 	ret, err = f.pfd.Seek(offset, whence)
 	runtime.KeepAlive(f)
 	return ret, err
@@ -222,6 +292,12 @@ func rename(oldname, newname string) error {
 	return nil
 }
 
+// isCrossDeviceError reports whether err, as returned by rename,
+// means that oldname and newname are on different volumes.
+func isCrossDeviceError(err error) bool {
+	return underlyingErrorIs(err, windows.ERROR_NOT_SAME_DEVICE)
+}
+
 // Pipe returns a connected pair of Files; reads from r return bytes written to w.
 // It returns the files and an error, if any. The Windows handles underlying
 // the returned files are marked as inheritable by child processes.
@@ -234,6 +310,24 @@ func Pipe() (r *File, w *File, err error) {
 	return newFile(p[0], "|0", "pipe"), newFile(p[1], "|1", "pipe"), nil
 }
 
+func pipe2(flags int) (r *File, w *File, err error) {
+	var p [2]syscall.Handle
+	e := syscall.Pipe(p[:])
+	if e != nil {
+		return nil, nil, NewSyscallError("pipe", e)
+	}
+	if flags&PipeCloexec != 0 {
+		for _, h := range p {
+			if e := syscall.SetHandleInformation(h, syscall.HANDLE_FLAG_INHERIT, 0); e != nil {
+				syscall.CloseHandle(p[0])
+				syscall.CloseHandle(p[1])
+				return nil, nil, NewSyscallError("sethandleinformation", e)
+			}
+		}
+	}
+	return newFile(p[0], "|0", "pipe"), newFile(p[1], "|1", "pipe"), nil
+}
+
 var (
 	useGetTempPath2Once sync.Once
 	useGetTempPath2     bool
@@ -285,6 +379,7 @@ func Link(oldname, newname string) error {
 // Symlink creates newname as a symbolic link to oldname.
 // On Windows, a symlink to a non-existent oldname creates a file symlink;
 // if oldname is later created as a directory the symlink will not work.
+// Use [SymlinkDir] to create a link to a directory that does not exist yet.
 // If there is an error, it will be of type *LinkError.
 func Symlink(oldname, newname string) error {
 	// '/' does not work in link's content
@@ -308,6 +403,14 @@ func Symlink(oldname, newname string) error {
 
 	fi, err := Stat(destpath)
 	isdir := err == nil && fi.IsDir()
+	return symlinkAs(oldname, newname, isdir)
+}
+
+// symlinkAs creates newname as a symbolic link to oldname, of the
+// directory type if isdir is set and the file type otherwise.
+func symlinkAs(oldname, newname string, isdir bool) error {
+	// '/' does not work in link's content
+	oldname = filepathlite.FromSlash(oldname)
 
 	n, err := syscall.UTF16PtrFromString(fixLongPath(newname))
 	if err != nil {
@@ -425,37 +528,66 @@ func normaliseLinkPath(path string) (string, error) {
 }
 
 func readReparseLink(path string) (string, error) {
-	h, err := openSymlink(path)
+	s, tag, err := readReparsePoint(path)
 	if err != nil {
 		return "", err
 	}
+	switch tag {
+	case syscall.IO_REPARSE_TAG_SYMLINK, windows.IO_REPARSE_TAG_MOUNT_POINT:
+		return s, nil
+	default:
+		// the path is not a symlink or junction but another type of reparse
+		// point
+		return "", syscall.ENOENT
+	}
+}
+
+// readReparsePoint returns the tag of the reparse point at path, and its
+// target for symlinks, junctions and app execution aliases.
+func readReparsePoint(path string) (target string, tag uint32, err error) {
+	h, err := openSymlink(path)
+	if err != nil {
+		return "", 0, err
+	}
 	defer syscall.CloseHandle(h)
 
 	rdbbuf := make([]byte, syscall.MAXIMUM_REPARSE_DATA_BUFFER_SIZE)
 	var bytesReturned uint32
 	err = syscall.DeviceIoControl(h, syscall.FSCTL_GET_REPARSE_POINT, nil, 0, &rdbbuf[0], uint32(len(rdbbuf)), &bytesReturned, nil)
 	if err != nil {
-		return "", err
+		return "", 0, err
 	}
 
 	rdb := (*windows.REPARSE_DATA_BUFFER)(unsafe.Pointer(&rdbbuf[0]))
-	switch rdb.ReparseTag {
+	tag = rdb.ReparseTag
+	switch tag {
 	case syscall.IO_REPARSE_TAG_SYMLINK:
 		rb := (*windows.SymbolicLinkReparseBuffer)(unsafe.Pointer(&rdb.DUMMYUNIONNAME))
 		s := rb.Path()
 		if rb.Flags&windows.SYMLINK_FLAG_RELATIVE != 0 {
-			return s, nil
+			return s, tag, nil
 		}
-		return normaliseLinkPath(s)
+		s, err = normaliseLinkPath(s)
+		return s, tag, err
 	case windows.IO_REPARSE_TAG_MOUNT_POINT:
-		return normaliseLinkPath((*windows.MountPointReparseBuffer)(unsafe.Pointer(&rdb.DUMMYUNIONNAME)).Path())
+		s, err := normaliseLinkPath((*windows.MountPointReparseBuffer)(unsafe.Pointer(&rdb.DUMMYUNIONNAME)).Path())
+		return s, tag, err
+	case windows.IO_REPARSE_TAG_APPEXECLINK:
+		rb := (*windows.AppExecLinkReparseBuffer)(unsafe.Pointer(&rdb.DUMMYUNIONNAME))
+		return rb.Path(rdb.ReparseDataLength), tag, nil
 	default:
-		// the path is not a symlink or junction but another type of reparse
-		// point
-		return "", syscall.ENOENT
+		return "", tag, nil
 	}
 }
 
+func reparsePointTarget(name string) (string, uint32, error) {
+	s, tag, err := readReparsePoint(fixLongPath(name))
+	if err != nil {
+		return "", 0, &PathError{Op: "readlink", Path: name, Err: err}
+	}
+	return s, tag, nil
+}
+
 func readlink(name string) (string, error) {
 	s, err := readReparseLink(fixLongPath(name))
 	if err != nil {
@@ -463,3 +595,7 @@ func readlink(name string) (string, error) {
 	}
 	return s, nil
 }
+
+func umask(mask int) int {
+	return 0
+}
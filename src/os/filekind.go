@@ -0,0 +1,52 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "internal/itoa"
+
+// A FileKind is the type of file that a file descriptor refers to,
+// as reported by [NewFileKind].
+type FileKind int
+
+const (
+	FileKindUnknown  FileKind = iota // none of the kinds below
+	FileKindRegular                  // a regular file
+	FileKindDir                      // a directory
+	FileKindPipe                     // a pipe or FIFO
+	FileKindSocket                   // a socket
+	FileKindDevice                   // a device other than a terminal
+	FileKindTerminal                 // a terminal
+)
+
+var fileKindNames = [...]string{
+	FileKindUnknown:  "unknown",
+	FileKindRegular:  "regular",
+	FileKindDir:      "dir",
+	FileKindPipe:     "pipe",
+	FileKindSocket:   "socket",
+	FileKindDevice:   "device",
+	FileKindTerminal: "terminal",
+}
+
+func (k FileKind) String() string {
+	if uint(k) < uint(len(fileKindNames)) {
+		return fileKindNames[k]
+	}
+	return "FileKind(" + itoa.Itoa(int(k)) + ")"
+}
+
+// NewFileKind is like [NewFile], but also reports the kind of file fd
+// refers to, such as a descriptor inherited from a parent process or
+// passed by systemd socket activation. Unlike NewFile, on Unix systems
+// NewFileKind adds pipes and sockets to the runtime poller, putting
+// them in non-blocking mode, as for files returned by [Pipe].
+//
+// Terminals are only told apart from other devices on Linux, the BSDs,
+// macOS and Windows. If the kind cannot be determined, because fd is
+// not valid, NewFileKind returns an error of type *PathError, and fd
+// is left as it was.
+func NewFileKind(fd uintptr, name string) (*File, FileKind, error) {
+	return newFileWithKind(fd, name)
+}
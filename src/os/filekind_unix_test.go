@@ -0,0 +1,109 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package os_test
+
+import (
+	. "os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNewFileKind(t *testing.T) {
+	t.Parallel()
+
+	var p [2]int
+	if err := syscall.Pipe(p[:]); err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Close(p[1])
+	s, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Close(s[1])
+	reg, err := syscall.Open(filepath.Join(t.TempDir(), "file"), syscall.O_RDWR|syscall.O_CREAT, 0o666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := syscall.Open(t.TempDir(), syscall.O_RDONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	null, err := syscall.Open(DevNull, syscall.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		fd       int
+		want     FileKind
+		pollable bool
+	}{
+		{"pipe", p[0], FileKindPipe, runtime.GOOS != "darwin" && runtime.GOOS != "ios"},
+		{"socket", s[0], FileKindSocket, true},
+		{"regular", reg, FileKindRegular, false},
+		{"dir", dir, FileKindDir, false},
+		{"null", null, FileKindDevice, false},
+	} {
+		f, kind, err := NewFileKind(uintptr(tt.fd), tt.name)
+		if err != nil {
+			t.Errorf("NewFileKind(%s): %v", tt.name, err)
+			continue
+		}
+		if kind != tt.want {
+			t.Errorf("NewFileKind(%s) kind = %v, want %v", tt.name, kind, tt.want)
+		}
+		// Files in the poller support deadlines.
+		if err := f.SetReadDeadline(time.Now().Add(time.Hour)); (err == nil) != tt.pollable {
+			t.Errorf("NewFileKind(%s): SetReadDeadline = %v, want pollable %v", tt.name, err, tt.pollable)
+		}
+		f.Close()
+	}
+
+	if _, _, err := NewFileKind(^uintptr(0), "invalid"); err == nil {
+		t.Error("NewFileKind of an invalid descriptor succeeded")
+	} else if _, ok := err.(*PathError); !ok {
+		t.Errorf("NewFileKind of an invalid descriptor: got %T, want *PathError", err)
+	}
+}
+
+func TestIsTerminalPty(t *testing.T) {
+	switch runtime.GOOS {
+	case "aix", "solaris", "illumos":
+		t.Skipf("terminals are not detected on %s", runtime.GOOS)
+	}
+	t.Parallel()
+
+	f, err := OpenFile("/dev/ptmx", O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("no pseudo-terminal: %v", err)
+	}
+	defer f.Close()
+	if !f.IsTerminal() {
+		t.Errorf("%s.IsTerminal() = false, want true", f.Name())
+	}
+
+	fd, err := syscall.Dup(int(f.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsTerminal(uintptr(fd)) {
+		t.Errorf("IsTerminal(%d) = false, want true", fd)
+	}
+	g, kind, err := NewFileKind(uintptr(fd), "pty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+	if kind != FileKindTerminal {
+		t.Errorf("NewFileKind(pty) kind = %v, want %v", kind, FileKindTerminal)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// FilesystemType returns the type of the file system holding the named
+// file, such as "ext4", "tmpfs", "apfs" or "NTFS".
+//
+// On Linux, the type is derived from the magic number reported by
+// statfs, and only common file systems are recognized by name; the
+// magic numbers of others are returned in hexadecimal, such as
+// "0x1234". The ext2, ext3 and ext4 file systems share a magic number
+// and are all reported as "ext4", and FUSE file systems are reported
+// as "fuse". On Darwin and the BSDs, the type is the file system type
+// name reported by statfs, or by statvfs on NetBSD. On Solaris and
+// illumos, it is the base type reported by statvfs, such as "zfs". On
+// Windows, it is the file system name reported by
+// GetVolumeInformationByHandleW, such as "NTFS" or "FAT32".
+// If there is an error, it will be of type [*PathError].
+//
+// On systems where FilesystemType is not implemented, it returns an
+// error wrapping [errors.ErrUnsupported].
+func FilesystemType(name string) (string, error) {
+	typ, err := filesystemType(name)
+	if err != nil {
+		return "", &PathError{Op: "fstype", Path: name, Err: err}
+	}
+	return typ, nil
+}
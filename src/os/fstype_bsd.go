@@ -0,0 +1,26 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd
+
+package os
+
+import "syscall"
+
+func filesystemType(name string) (string, error) {
+	var st syscall.Statfs_t
+	if err := ignoringEINTR(func() error {
+		return syscall.Statfs(name, &st)
+	}); err != nil {
+		return "", err
+	}
+	typ := make([]byte, 0, len(st.Fstypename))
+	for _, c := range st.Fstypename {
+		if c == 0 {
+			break
+		}
+		typ = append(typ, byte(c))
+	}
+	return string(typ), nil
+}
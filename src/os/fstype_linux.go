@@ -0,0 +1,62 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/itoa"
+	"syscall"
+)
+
+// linuxFilesystemTypes maps the statfs magic numbers of common Linux
+// file systems, as defined in linux/magic.h, to their names.
+var linuxFilesystemTypes = map[uint32]string{
+	0x0187:     "autofs",
+	0x01021994: "tmpfs",
+	0x01021997: "9p",
+	0x1cd1:     "devpts",
+	0x2011bab0: "exfat",
+	0x27e0eb:   "cgroup",
+	0x2fc12fc1: "zfs",
+	0x4d44:     "vfat",
+	0x52654973: "reiserfs",
+	0x5346544e: "ntfs",
+	0x58465342: "xfs",
+	0x62656572: "sysfs",
+	0x63677270: "cgroup2",
+	0x64626720: "debugfs",
+	0x65735546: "fuse",
+	0x6969:     "nfs",
+	0x6e736673: "nsfs",
+	0x73636673: "securityfs",
+	0x73717368: "squashfs",
+	0x794c7630: "overlay",
+	0x858458f6: "ramfs",
+	0x9123683e: "btrfs",
+	0x958458f6: "hugetlbfs",
+	0x9660:     "iso9660",
+	0x9fa0:     "proc",
+	0xca451a4e: "bcachefs",
+	0xcafe4a11: "bpf",
+	0xe0f5e1e2: "erofs",
+	0xef53:     "ext4",
+	0xf15f:     "ecryptfs",
+	0xf2f52010: "f2fs",
+	0xfe534d42: "smb2",
+	0xff534d42: "cifs",
+}
+
+func filesystemType(name string) (string, error) {
+	var st syscall.Statfs_t
+	if err := ignoringEINTR(func() error {
+		return syscall.Statfs(name, &st)
+	}); err != nil {
+		return "", err
+	}
+	magic := uint32(st.Type)
+	if typ, ok := linuxFilesystemTypes[magic]; ok {
+		return typ, nil
+	}
+	return itoa.Uitox(uint(magic)), nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	. "os"
+	"strings"
+	"testing"
+)
+
+// TestFilesystemTypeMounts checks FilesystemType against the types
+// of the mounts listed in /proc/self/mounts.
+func TestFilesystemTypeMounts(t *testing.T) {
+	t.Parallel()
+
+	b, err := ReadFile("/proc/self/mounts")
+	if err != nil {
+		t.Skipf("skipping: %v", err)
+	}
+	// Later mounts hide earlier ones at the same mount point.
+	mounts := make(map[string]string)
+	for _, line := range strings.Split(string(b), "\n") {
+		if f := strings.Fields(line); len(f) >= 3 {
+			mounts[f[1]] = f[2]
+		}
+	}
+	checked := 0
+	for dir, want := range mounts {
+		// These types have magic numbers of their own,
+		// unlike, say, devtmpfs, which is reported as tmpfs.
+		switch want {
+		case "tmpfs", "proc", "sysfs":
+		default:
+			continue
+		}
+		got, err := FilesystemType(dir)
+		if err != nil {
+			t.Logf("FilesystemType(%q): %v", dir, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("FilesystemType(%q) = %q, want %q", dir, got, want)
+		}
+		checked++
+	}
+	if checked == 0 {
+		t.Skip("skipping: no tmpfs, proc or sysfs mount found")
+	}
+}
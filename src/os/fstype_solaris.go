@@ -0,0 +1,24 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "internal/syscall/unix"
+
+func filesystemType(name string) (string, error) {
+	var st unix.Statvfs_t
+	if err := ignoringEINTR(func() error {
+		return unix.Statvfs(name, &st)
+	}); err != nil {
+		return "", err
+	}
+	typ := make([]byte, 0, len(st.Basetype))
+	for _, c := range st.Basetype {
+		if c == 0 {
+			break
+		}
+		typ = append(typ, byte(c))
+	}
+	return string(typ), nil
+}
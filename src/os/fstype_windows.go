@@ -0,0 +1,23 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/windows"
+	"syscall"
+)
+
+func filesystemType(name string) (string, error) {
+	f, err := Open(name)
+	if err != nil {
+		return "", underlyingError(err)
+	}
+	defer f.Close()
+	var buf [syscall.MAX_PATH + 1]uint16
+	if err := windows.GetVolumeInformationByHandle(f.pfd.Sysfd, nil, 0, nil, nil, nil, &buf[0], uint32(len(buf))); err != nil {
+		return "", err
+	}
+	return syscall.UTF16ToString(buf[:]), nil
+}
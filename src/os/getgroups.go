@@ -0,0 +1,56 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+
+package os
+
+import "internal/itoa"
+
+func getgroupsNames() ([]string, error) {
+	gids, err := Getgroups()
+	if err != nil {
+		return nil, err
+	}
+	if len(gids) == 0 {
+		return nil, nil
+	}
+	// A missing group file leaves every group named by its id.
+	data, _ := ReadFile("/etc/group")
+	return lookupGroupNames(gids, data), nil
+}
+
+// lookupGroupNames returns the names of the groups with the ids gids,
+// looked up in data, which is in the format of /etc/group. The name of
+// a group without an entry in data is its id in decimal.
+func lookupGroupNames(gids []int, data []byte) []string {
+	names := make([]string, len(gids))
+	for len(data) > 0 {
+		var line []byte
+		line, data = cutByte(data, '\n')
+		// Skip comments and NIS compat entries, as os/user does.
+		if len(line) == 0 || line[0] == '#' || line[0] == '+' || line[0] == '-' {
+			continue
+		}
+		name, rest := cutByte(line, ':')
+		_, rest = cutByte(rest, ':') // password
+		id, _ := cutByte(rest, ':')
+		gid, ok := atoi(string(id))
+		if !ok || len(name) == 0 {
+			continue
+		}
+		for i, g := range gids {
+			// The first entry for a group wins.
+			if g == gid && names[i] == "" {
+				names[i] = string(name)
+			}
+		}
+	}
+	for i, g := range gids {
+		if names[i] == "" {
+			names[i] = itoa.Itoa(g)
+		}
+	}
+	return names
+}
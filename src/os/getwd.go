@@ -10,26 +10,68 @@ import (
 	"syscall"
 )
 
+// getwdCache holds the result of the last call to Getwd, along with
+// the FileInfo of "." at the time, so that Getwd can reuse it as long
+// as "." is the same directory. On Windows, it holds the directory last
+// passed to Chdir instead, which fixLongPath uses.
 var getwdCache struct {
 	sync.Mutex
 	dir string
+	dot FileInfo
+}
+
+// clearGetwdCache forgets the cached result of Getwd. It is called
+// when the working directory or $PWD changes through this package.
+func clearGetwdCache() {
+	getwdCache.Lock()
+	getwdCache.dir = ""
+	getwdCache.dot = nil
+	getwdCache.Unlock()
 }
 
 // Getwd returns a rooted path name corresponding to the
 // current directory. If the current directory can be
 // reached via multiple paths (due to symbolic links),
 // Getwd may return any one of them.
+//
+// On Unix systems, Getwd reuses its previous result for as long as the
+// current directory is the same directory, which costs a single stat of
+// ".". The cached result is discarded by [Chdir], [File.Chdir] and
+// changes to $PWD through this package. If the current directory, or
+// one of the directories above it, is renamed or moved in any other
+// way, Getwd keeps returning the old path until one of those calls.
 func Getwd() (dir string, err error) {
 	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
 		return syscall.Getwd()
 	}
 
-	// Clumsy but widespread kludge:
-	// if $PWD is set and matches ".", use it.
 	dot, err := statNolog(".")
 	if err != nil {
 		return "", err
 	}
+
+	getwdCache.Lock()
+	dir, cached := getwdCache.dir, getwdCache.dot
+	getwdCache.Unlock()
+	if cached != nil && SameFile(dot, cached) {
+		return dir, nil
+	}
+
+	dir, err = getwd(dot)
+	if err != nil {
+		return "", err
+	}
+	getwdCache.Lock()
+	getwdCache.dir = dir
+	getwdCache.dot = dot
+	getwdCache.Unlock()
+	return dir, nil
+}
+
+// getwd implements Getwd without the cache, given the FileInfo of ".".
+func getwd(dot FileInfo) (dir string, err error) {
+	// Clumsy but widespread kludge:
+	// if $PWD is set and matches ".", use it.
 	dir = Getenv("PWD")
 	if len(dir) > 0 && dir[0] == '/' {
 		d, err := statNolog(dir)
@@ -54,17 +96,6 @@ func Getwd() (dir string, err error) {
 		return s, NewSyscallError("getwd", e)
 	}
 
-	// Apply same kludge but to cached dir instead of $PWD.
-	getwdCache.Lock()
-	dir = getwdCache.dir
-	getwdCache.Unlock()
-	if len(dir) > 0 {
-		d, err := statNolog(dir)
-		if err == nil && SameFile(dot, d) {
-			return dir, nil
-		}
-	}
-
 	// Root is a special case because it has no parent
 	// and ends in a slash.
 	root, err := statNolog("/")
@@ -117,10 +148,5 @@ func Getwd() (dir string, err error) {
 		dot = pd
 	}
 
-	// Save answer as hint to avoid the expensive path next time.
-	getwdCache.Lock()
-	getwdCache.dir = dir
-	getwdCache.Unlock()
-
 	return dir, nil
 }
@@ -0,0 +1,23 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package os
+
+import "internal/syscall/unix"
+
+func linkFollow(oldname, newname string, followSymlink bool) error {
+	flags := 0
+	if followSymlink {
+		flags = unix.AT_SYMLINK_FOLLOW
+	}
+	e := ignoringEINTR(func() error {
+		return unix.Linkat(unix.AT_FDCWD, oldname, unix.AT_FDCWD, newname, flags)
+	})
+	if e != nil {
+		return &LinkError{"link", oldname, newname, e}
+	}
+	return nil
+}
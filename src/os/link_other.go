@@ -0,0 +1,62 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !plan9
+
+package os
+
+import (
+	"errors"
+	"internal/filepathlite"
+	"runtime"
+	"syscall"
+)
+
+func linkFollow(oldname, newname string, followSymlink bool) error {
+	fi, err := Lstat(oldname)
+	if err != nil || fi.Mode()&ModeSymlink == 0 {
+		// Let Link report the error, if any.
+		return Link(oldname, newname)
+	}
+	if !followSymlink {
+		if runtime.GOOS != "windows" {
+			return &LinkError{"link", oldname, newname, errors.ErrUnsupported}
+		}
+		// CreateHardLink never follows symbolic links.
+		return Link(oldname, newname)
+	}
+	target, err := resolveSymlinks(oldname)
+	if err != nil {
+		return &LinkError{"link", oldname, newname, underlyingError(err)}
+	}
+	if err := Link(target, newname); err != nil {
+		return &LinkError{"link", oldname, newname, underlyingError(err)}
+	}
+	return nil
+}
+
+// resolveSymlinks follows symbolic links starting at name until it
+// reaches a name that is not a symbolic link, and returns that name.
+func resolveSymlinks(name string) (string, error) {
+	// Same limit as in Linux and package path/filepath.
+	const maxLinks = 255
+	for range maxLinks {
+		fi, err := Lstat(name)
+		if err != nil {
+			return "", err
+		}
+		if fi.Mode()&ModeSymlink == 0 {
+			return name, nil
+		}
+		target, err := Readlink(name)
+		if err != nil {
+			return "", err
+		}
+		if !filepathlite.IsAbs(target) && filepathlite.VolumeName(target) == "" {
+			target = joinPath(filepathlite.Dir(name), target)
+		}
+		name = target
+	}
+	return "", &PathError{Op: "readlink", Path: name, Err: syscall.ELOOP}
+}
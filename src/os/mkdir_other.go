@@ -0,0 +1,13 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !unix
+
+package os
+
+// setSpecialBits is a no-op: Windows, Plan 9 and WASI do not have the
+// special permission bits, and js/wasm keeps those passed to mkdir.
+func setSpecialBits(name string, perm FileMode) error {
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+)
+
+// setSpecialBits adds the bits of perm in modeSpecial to the permission
+// bits of the directory name, just created by Mkdir, if any of them is
+// missing. It changes the mode with fchmodat and AT_SYMLINK_NOFOLLOW,
+// so that a symbolic link swapped in for name is never followed, and
+// so that it needs no access to the directory itself, which perm may
+// not grant.
+func setSpecialBits(name string, perm FileMode) error {
+	fi, err := Lstat(name)
+	if err != nil {
+		return err
+	}
+	want := perm & modeSpecial
+	if fi.Mode()&want == want {
+		return nil
+	}
+	if !fi.IsDir() {
+		// name was replaced after Mkdir created it.
+		return &PathError{Op: "chmod", Path: name, Err: syscall.ENOTDIR}
+	}
+	mode := syscallMode(fi.Mode()&(ModePerm|modeSpecial) | want)
+	err = ignoringEINTR(func() error {
+		return unix.Fchmodat(unix.AT_FDCWD, name, mode, unix.AT_SYMLINK_NOFOLLOW)
+	})
+	if err == syscall.EOPNOTSUPP {
+		// Linux before 6.6 rejects AT_SYMLINK_NOFOLLOW even if
+		// name is not a symbolic link.
+		err = chmodAtNofollow(unix.AT_FDCWD, name, mode)
+	}
+	if err != nil {
+		return &PathError{Op: "chmod", Path: name, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+)
+
+func mkfifo(name string, perm FileMode) error {
+	return ignoringEINTR(func() error {
+		return syscall.Mknodat(unix.AT_FDCWD, name, syscall.S_IFIFO|syscallMode(perm), 0)
+	})
+}
@@ -0,0 +1,15 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix && !aix && !solaris
+
+package os
+
+import "syscall"
+
+func mkfifo(name string, perm FileMode) error {
+	return ignoringEINTR(func() error {
+		return syscall.Mkfifo(name, syscallMode(perm))
+	})
+}
@@ -0,0 +1,20 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+)
+
+func syscallMknod(name string, mode uint32, dev uint64) error {
+	return syscall.Mknodat(unix.AT_FDCWD, name, mode, int(dev))
+}
+
+func mkdev(major, minor uint32) uint64 {
+	// The high bit marks a 64-bit device number.
+	const devno64 = 1 << 63
+	return uint64(major)<<32 | uint64(minor) | devno64
+}
@@ -0,0 +1,15 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "syscall"
+
+func syscallMknod(name string, mode uint32, dev uint64) error {
+	return syscall.Mknod(name, mode, int(dev))
+}
+
+func mkdev(major, minor uint32) uint64 {
+	return uint64(major&0xff)<<8 | uint64(minor&0xff) | uint64(minor&0xffff00)<<8
+}
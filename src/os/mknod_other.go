@@ -0,0 +1,17 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !unix
+
+package os
+
+import "errors"
+
+func mknod(name string, mode FileMode, dev uint64) error {
+	return errors.ErrUnsupported
+}
+
+func mkdev(major, minor uint32) uint64 {
+	return uint64(major)<<32 | uint64(minor)
+}
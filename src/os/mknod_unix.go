@@ -0,0 +1,27 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package os
+
+import "syscall"
+
+func mknod(name string, mode FileMode, dev uint64) error {
+	m := syscallMode(mode)
+	switch mode & ModeType {
+	case ModeNamedPipe:
+		m |= syscall.S_IFIFO
+		dev = 0
+	case ModeDevice:
+		m |= syscall.S_IFBLK
+	case ModeCharDevice, ModeDevice | ModeCharDevice:
+		m |= syscall.S_IFCHR
+	default:
+		return syscall.EINVAL
+	}
+	return ignoringEINTR(func() error {
+		return syscallMknod(name, m, dev)
+	})
+}
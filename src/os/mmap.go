@@ -0,0 +1,110 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"errors"
+	"sync"
+)
+
+// Memory protection flags for [File.Map], which may be combined.
+const (
+	ProtRead = 1 << iota
+	ProtWrite
+	ProtExec
+)
+
+// Mapping flags for [File.Map]. Exactly one must be given.
+const (
+	// MapShared makes writes to the mapping visible to other mappings
+	// of the file and, eventually, in the file itself.
+	MapShared = 1 << iota
+	// MapPrivate makes writes to the mapping private copies of the
+	// affected pages, leaving the file unchanged.
+	MapPrivate
+)
+
+var errUnmapped = errors.New("os: mapping already unmapped")
+
+// A Mapping is a range of a file mapped into memory by [File.Map].
+type Mapping struct {
+	mu     sync.Mutex
+	data   []byte // the range requested from Map
+	mapped []byte // the whole mapping, starting at an aligned offset
+}
+
+// Map maps length bytes of the file, starting at byte offset off, into
+// memory. The prot argument combines [ProtRead], [ProtWrite] and [ProtExec],
+// and must be permitted by the mode f was opened with; flags is [MapShared]
+// or [MapPrivate]. The offset need not be aligned to a page boundary.
+//
+// The mapping stays valid after f is closed, until [Mapping.Unmap] is called.
+// The memory is not managed by the garbage collector: it is released only
+// by Unmap, even if the Mapping becomes unreachable, so callers must call
+// Unmap when they are done with it, and must not use the slice returned by
+// [Mapping.Data] afterwards.
+// Accessing the mapping beyond the end of the file, for instance after the
+// file has been truncated, may crash the program.
+//
+// On Windows, Map uses CreateFileMapping and MapViewOfFile, and
+// the mapped range must lie within the file.
+// On systems without mmap, Map returns an error wrapping [errors.ErrUnsupported].
+// If there is an error, it will be of type [*PathError].
+func (f *File) Map(off, length int64, prot, flags int) (*Mapping, error) {
+	if err := f.checkValid("map"); err != nil {
+		return nil, err
+	}
+	if off < 0 || length <= 0 || int64(int(length)) != length ||
+		prot&^(ProtRead|ProtWrite|ProtExec) != 0 ||
+		(flags != MapShared && flags != MapPrivate) {
+		return nil, &PathError{Op: "map", Path: f.name, Err: ErrInvalid}
+	}
+	mapped, start, err := f.mmap(off, int(length), prot, flags)
+	if err != nil {
+		return nil, f.wrapErr("map", err)
+	}
+	m := &Mapping{
+		data:   mapped[start : start+int(length) : start+int(length)],
+		mapped: mapped,
+	}
+	return m, nil
+}
+
+// Data returns the mapped bytes of the file. It returns nil once the
+// mapping has been unmapped. See [File.Map] for how long the slice
+// may be used.
+func (m *Mapping) Data() []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.data
+}
+
+// Flush writes any modified pages of a [MapShared] mapping back to the
+// file. On Unix systems it waits for them to reach stable storage, as
+// msync with MS_SYNC does. On Windows it only starts writing them back;
+// call [File.Sync] to wait for that.
+func (m *Mapping) Flush() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mapped == nil {
+		return errUnmapped
+	}
+	return m.flush()
+}
+
+// Unmap removes the mapping. The slice returned by [Mapping.Data] must
+// not be used afterwards. Unmap returns an error if called more than once.
+func (m *Mapping) Unmap() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mapped == nil {
+		return errUnmapped
+	}
+	if err := m.unmap(); err != nil {
+		return err
+	}
+	m.data, m.mapped = nil, nil
+	return nil
+}
@@ -0,0 +1,21 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !unix && !windows
+
+package os
+
+import "errors"
+
+func (f *File) mmap(off int64, length, prot, flags int) ([]byte, int, error) {
+	return nil, 0, errors.ErrUnsupported
+}
+
+func (m *Mapping) flush() error {
+	panic("unreachable")
+}
+
+func (m *Mapping) unmap() error {
+	panic("unreachable")
+}
@@ -0,0 +1,151 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	"bytes"
+	"errors"
+	. "os"
+	"path/filepath"
+	"testing"
+)
+
+// mapTestFile creates a file spanning several pages and allocation
+// granules, filled with a repeating pattern, and returns its name and
+// contents.
+func mapTestFile(t *testing.T) (string, []byte) {
+	t.Helper()
+	data := make([]byte, 200<<10)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	name := filepath.Join(t.TempDir(), "map")
+	if err := WriteFile(name, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return name, data
+}
+
+// mapOrSkip calls f.Map, skipping the test if mapping is unsupported.
+func mapOrSkip(t *testing.T, f *File, off, length int64, prot, flags int) *Mapping {
+	t.Helper()
+	m, err := f.Map(off, length, prot, flags)
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("Map(%d, %d): %v", off, length, err)
+	}
+	return m
+}
+
+func TestMapReadOnly(t *testing.T) {
+	t.Parallel()
+
+	name, want := mapTestFile(t)
+	f, err := Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for _, r := range []struct{ off, length int64 }{
+		{0, int64(len(want))},
+		{1, 10},
+		{5000, 70 << 10}, // not page aligned
+		{65 << 10, 4096}, // not aligned to an allocation granule
+		{int64(len(want)) - 1, 1},
+	} {
+		m := mapOrSkip(t, f, r.off, r.length, ProtRead, MapShared)
+		if got := m.Data(); !bytes.Equal(got, want[r.off:r.off+r.length]) {
+			t.Errorf("Map(%d, %d) data differs from file contents", r.off, r.length)
+		}
+		if err := m.Unmap(); err != nil {
+			t.Errorf("Unmap: %v", err)
+		}
+		if m.Data() != nil {
+			t.Errorf("Data after Unmap is not nil")
+		}
+		if err := m.Unmap(); err == nil {
+			t.Errorf("second Unmap succeeded")
+		}
+	}
+
+	// The mapping outlives the file.
+	m := mapOrSkip(t, f, 0, 100, ProtRead, MapPrivate)
+	defer m.Unmap()
+	f.Close()
+	if got := m.Data(); !bytes.Equal(got, want[:100]) {
+		t.Errorf("data mapped from closed file differs from file contents")
+	}
+}
+
+func TestMapWrite(t *testing.T) {
+	t.Parallel()
+
+	name, want := mapTestFile(t)
+	f, err := OpenFile(name, O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// Writes through a private mapping do not reach the file.
+	m := mapOrSkip(t, f, 100, 10, ProtRead|ProtWrite, MapPrivate)
+	copy(m.Data(), "private!!!")
+	if err := m.Unmap(); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ReadFile(name); err != nil || !bytes.Equal(got, want) {
+		t.Errorf("write through MapPrivate mapping changed the file (err %v)", err)
+	}
+
+	// Writes through a shared mapping do, once it is flushed.
+	m = mapOrSkip(t, f, 5000, 10, ProtRead|ProtWrite, MapShared)
+	copy(m.Data(), "shared!!!!")
+	if err := m.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	copy(want[5000:], "shared!!!!")
+	if got, err := ReadFile(name); err != nil || !bytes.Equal(got, want) {
+		t.Errorf("write through MapShared mapping not visible after Flush (err %v)", err)
+	}
+	if err := m.Unmap(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Flush(); err == nil {
+		t.Errorf("Flush after Unmap succeeded")
+	}
+}
+
+func TestMapInvalid(t *testing.T) {
+	t.Parallel()
+
+	name, _ := mapTestFile(t)
+	f, err := Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for _, tt := range []struct {
+		name        string
+		off, length int64
+		prot, flags int
+	}{
+		{"NegativeOffset", -1, 1, ProtRead, MapShared},
+		{"ZeroLength", 0, 0, ProtRead, MapShared},
+		{"NoFlags", 0, 1, ProtRead, 0},
+		{"BothFlags", 0, 1, ProtRead, MapShared | MapPrivate},
+		{"BadProt", 0, 1, 1 << 10, MapShared},
+	} {
+		if m, err := f.Map(tt.off, tt.length, tt.prot, tt.flags); err == nil {
+			m.Unmap()
+			t.Errorf("%s: Map succeeded", tt.name)
+		} else if _, ok := err.(*PathError); !ok {
+			t.Errorf("%s: Map error %v has type %T, want *PathError", tt.name, err, err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+)
+
+// mmap maps length bytes of f from off, and returns the mapping along
+// with the index of off in it; mmap needs a page-aligned offset.
+func (f *File) mmap(off int64, length, prot, flags int) ([]byte, int, error) {
+	start := int(off % int64(syscall.Getpagesize()))
+
+	sysprot := syscall.PROT_NONE
+	if prot&ProtRead != 0 {
+		sysprot |= syscall.PROT_READ
+	}
+	if prot&ProtWrite != 0 {
+		sysprot |= syscall.PROT_WRITE
+	}
+	if prot&ProtExec != 0 {
+		sysprot |= syscall.PROT_EXEC
+	}
+	sysflags := syscall.MAP_SHARED
+	if flags == MapPrivate {
+		sysflags = syscall.MAP_PRIVATE
+	}
+
+	var (
+		b []byte
+		e error
+	)
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		b, e = syscall.Mmap(int(fd), off-int64(start), start+length, sysprot, sysflags)
+	})
+	if cerr != nil {
+		return nil, 0, cerr
+	}
+	if e != nil {
+		return nil, 0, NewSyscallError("mmap", e)
+	}
+	return b, start, nil
+}
+
+func (m *Mapping) flush() error {
+	return NewSyscallError("msync", unix.Msync(m.mapped, unix.MS_SYNC))
+}
+
+func (m *Mapping) unmap() error {
+	return NewSyscallError("munmap", syscall.Munmap(m.mapped))
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// allocationGranularity is the alignment MapViewOfFile requires of
+// offsets, as reported by GetSystemInfo. It is 64 KiB on all versions
+// of Windows.
+const allocationGranularity = 64 << 10
+
+// Memory protection constants for CreateFileMapping not defined by syscall.
+const (
+	_PAGE_EXECUTE_READ      = 0x20
+	_PAGE_EXECUTE_READWRITE = 0x40
+	_PAGE_EXECUTE_WRITECOPY = 0x80
+)
+
+// mmap maps length bytes of f from off, and returns the mapping along
+// with the index of off in it; MapViewOfFile needs an offset aligned to
+// allocationGranularity.
+func (f *File) mmap(off int64, length, prot, flags int) ([]byte, int, error) {
+	start := int(off % allocationGranularity)
+	off -= int64(start)
+	size := start + length
+
+	var protect, access uint32
+	switch {
+	case prot&ProtWrite != 0 && flags == MapPrivate:
+		protect, access = syscall.PAGE_WRITECOPY, syscall.FILE_MAP_COPY
+		if prot&ProtExec != 0 {
+			protect = _PAGE_EXECUTE_WRITECOPY
+		}
+	case prot&ProtWrite != 0:
+		protect, access = syscall.PAGE_READWRITE, syscall.FILE_MAP_WRITE
+		if prot&ProtExec != 0 {
+			protect = _PAGE_EXECUTE_READWRITE
+		}
+	default:
+		protect, access = syscall.PAGE_READONLY, syscall.FILE_MAP_READ
+		if prot&ProtExec != 0 {
+			protect = _PAGE_EXECUTE_READ
+		}
+	}
+	if prot&ProtExec != 0 {
+		access |= syscall.FILE_MAP_EXECUTE
+	}
+
+	var (
+		addr uintptr
+		e    error
+	)
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		// A maximum size of zero maps the file as it is,
+		// rather than extending it to fit the view.
+		var h syscall.Handle
+		h, e = syscall.CreateFileMapping(syscall.Handle(fd), nil, protect, 0, 0, nil)
+		if e != nil {
+			e = NewSyscallError("CreateFileMapping", e)
+			return
+		}
+		addr, e = syscall.MapViewOfFile(h, access, uint32(off>>32), uint32(off), uintptr(size))
+		if e != nil {
+			e = NewSyscallError("MapViewOfFile", e)
+		}
+		// The view keeps the file mapping object alive.
+		syscall.CloseHandle(h)
+	})
+	if cerr != nil {
+		return nil, 0, cerr
+	}
+	if e != nil {
+		return nil, 0, e
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(addr)), size), start, nil
+}
+
+func (m *Mapping) flush() error {
+	addr := uintptr(unsafe.Pointer(unsafe.SliceData(m.mapped)))
+	return NewSyscallError("FlushViewOfFile", syscall.FlushViewOfFile(addr, uintptr(len(m.mapped))))
+}
+
+func (m *Mapping) unmap() error {
+	addr := uintptr(unsafe.Pointer(unsafe.SliceData(m.mapped)))
+	return NewSyscallError("UnmapViewOfFile", syscall.UnmapViewOfFile(addr))
+}
@@ -6,6 +6,7 @@ package os_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -20,6 +21,7 @@ import (
 	"runtime"
 	"runtime/debug"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -295,6 +297,55 @@ func TestLstat(t *testing.T) {
 	}
 }
 
+func TestStatOpt(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	t.Parallel()
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := WriteFile(target, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := Symlink("target", link); err != nil {
+		t.Fatal(err)
+	}
+
+	fi, err := StatOpt(link, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.Mode().IsRegular() || fi.Size() != 5 {
+		t.Errorf("StatOpt(%q, true): mode %v, size %d; want a regular file of size 5", link, fi.Mode(), fi.Size())
+	}
+	if want, err := Stat(target); err != nil {
+		t.Fatal(err)
+	} else if !SameFile(fi, want) {
+		t.Errorf("StatOpt(%q, true) does not describe %q", link, target)
+	}
+
+	fi, err = StatOpt(link, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode()&ModeSymlink == 0 {
+		t.Errorf("StatOpt(%q, false): mode %v; want ModeSymlink set", link, fi.Mode())
+	}
+	if fi.Name() != "link" {
+		t.Errorf("StatOpt(%q, false).Name() = %q; want %q", link, fi.Name(), "link")
+	}
+
+	if err := Remove(target); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := StatOpt(link, true); !IsNotExist(err) {
+		t.Errorf("StatOpt of a dangling link with followSymlinks: got %v, want a not-exist error", err)
+	}
+	if _, err := StatOpt(link, false); err != nil {
+		t.Errorf("StatOpt of a dangling link without followSymlinks: %v", err)
+	}
+}
+
 // Read with length 0 should not return EOF.
 func TestRead0(t *testing.T) {
 	t.Parallel()
@@ -491,6 +542,363 @@ func TestFileReadDir(t *testing.T) {
 	t.Run("TempDir", testReadDir(t.TempDir(), nil))
 }
 
+func TestReadDirSeq(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	var want []string
+	for i := range 600 {
+		name := fmt.Sprintf("f%03d", i)
+		if err := WriteFile(filepath.Join(dir, name), nil, 0o666); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, name)
+	}
+
+	var got []string
+	for d, err := range ReadDirSeq(dir) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, d.Name())
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, want) {
+		t.Errorf("ReadDirSeq returned %d entries, want %d", len(got), len(want))
+	}
+
+	missing := filepath.Join(dir, "missing")
+	n := 0
+	for d, err := range ReadDirSeq(missing) {
+		n++
+		if d != nil || !IsNotExist(err) {
+			t.Errorf("ReadDirSeq(%q) yielded %v, %v; want nil, not-exist error", missing, d, err)
+		}
+	}
+	if n != 1 {
+		t.Errorf("ReadDirSeq(%q) yielded %d values, want 1", missing, n)
+	}
+}
+
+func TestReadDirSeqBreakCloses(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("test uses /proc/self/fd")
+	}
+	openFDs := func() []string {
+		names, err := ReadDir("/proc/self/fd")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fds []string
+		for _, n := range names {
+			fds = append(fds, n.Name())
+		}
+		return fds
+	}
+
+	dir := t.TempDir()
+	for i := range 10 {
+		if err := WriteFile(filepath.Join(dir, fmt.Sprint(i)), nil, 0o666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before := openFDs()
+	for _, err := range ReadDirSeq(dir) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if during := openFDs(); len(during) <= len(before) {
+			t.Errorf("no descriptor open during iteration")
+		}
+		break
+	}
+	if after := openFDs(); !slices.Equal(after, before) {
+		t.Errorf("open descriptors after breaking out of ReadDirSeq = %v, want %v", after, before)
+	}
+}
+
+func TestReadDirTyped(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := WriteFile(filepath.Join(dir, "file"), nil, 0o666); err != nil {
+		t.Fatal(err)
+	}
+	if err := Mkdir(filepath.Join(dir, "dir"), 0o777); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]FileMode{"dir": ModeDir, "file": 0}
+	if testenv.HasSymlink() {
+		if err := Symlink("file", filepath.Join(dir, "link")); err != nil {
+			t.Fatal(err)
+		}
+		want["link"] = ModeSymlink
+	}
+
+	dirs, err := ReadDirTyped(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) != len(want) {
+		t.Fatalf("ReadDirTyped returned %v, want %d entries", dirs, len(want))
+	}
+	for _, d := range dirs {
+		typ := d.Type()
+		if typ == ModeIrregular {
+			// The file system does not record the type:
+			// only Info tells.
+			fi, err := d.Info()
+			if err != nil {
+				t.Fatal(err)
+			}
+			typ = fi.Mode().Type()
+		}
+		if w, ok := want[d.Name()]; !ok || typ != w {
+			t.Errorf("entry %q has type %v, want %v", d.Name(), typ, w)
+		}
+	}
+}
+
+func BenchmarkReadDirSeq(b *testing.B) {
+	dir := b.TempDir()
+	for i := range 100000 {
+		f, err := Create(filepath.Join(dir, fmt.Sprint(i)))
+		if err != nil {
+			b.Fatal(err)
+		}
+		f.Close()
+	}
+	b.ResetTimer()
+
+	b.Run("ReadDir", func(b *testing.B) {
+		for range b.N {
+			if _, err := ReadDir(dir); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("ReadDirSeq", func(b *testing.B) {
+		for range b.N {
+			for _, err := range ReadDirSeq(dir) {
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("ReadDirSeqFirst", func(b *testing.B) {
+		for range b.N {
+			for _, err := range ReadDirSeq(dir) {
+				if err != nil {
+					b.Fatal(err)
+				}
+				break
+			}
+		}
+	})
+}
+
+func TestCountDirEntries(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []int{0, 1, 600} {
+		dir := t.TempDir()
+		for i := range n {
+			if err := WriteFile(filepath.Join(dir, fmt.Sprint(i)), nil, 0o666); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got, err := CountDirEntries(dir); got != n || err != nil {
+			t.Errorf("CountDirEntries with %d entries = %d, %v; want %d, nil", n, got, err, n)
+		}
+		if got, err := IsDirEmpty(dir); got != (n == 0) || err != nil {
+			t.Errorf("IsDirEmpty with %d entries = %v, %v; want %v, nil", n, got, err, n == 0)
+		}
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing")
+	if _, err := CountDirEntries(missing); !IsNotExist(err) {
+		t.Errorf("CountDirEntries(%q): got %v, want a not-exist error", missing, err)
+	}
+	if empty, err := IsDirEmpty(missing); empty || !IsNotExist(err) {
+		t.Errorf("IsDirEmpty(%q) = %v, %v; want false, a not-exist error", missing, empty, err)
+	}
+}
+
+func BenchmarkCountDirEntries(b *testing.B) {
+	dir := b.TempDir()
+	for i := range 100000 {
+		f, err := Create(filepath.Join(dir, fmt.Sprint(i)))
+		if err != nil {
+			b.Fatal(err)
+		}
+		f.Close()
+	}
+	b.ResetTimer()
+
+	b.Run("ReadDir", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			if _, err := ReadDir(dir); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("CountDirEntries", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			if _, err := CountDirEntries(dir); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("IsDirEmpty", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			if _, err := IsDirEmpty(dir); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// makeWalkTree creates a tree under root with depth levels of fanout
+// directories, each holding fanout files.
+func makeWalkTree(tb testing.TB, root string, depth, fanout int) {
+	tb.Helper()
+	for i := range fanout {
+		if err := WriteFile(filepath.Join(root, fmt.Sprintf("f%d", i)), nil, 0o666); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	if depth == 0 {
+		return
+	}
+	for i := range fanout {
+		dir := filepath.Join(root, fmt.Sprintf("d%d", i))
+		if err := Mkdir(dir, 0o777); err != nil {
+			tb.Fatal(err)
+		}
+		makeWalkTree(tb, dir, depth-1, fanout)
+	}
+}
+
+// walkPaths returns the paths visited by walk on root, calling skip
+// for each to choose the result of the WalkDirFunc.
+func walkPaths(walk func(string, fs.WalkDirFunc) error, root string, skip func(path string, d fs.DirEntry) error) ([]string, error) {
+	var paths []string
+	err := walk(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		paths = append(paths, path)
+		return skip(path, d)
+	})
+	return paths, err
+}
+
+func TestWalkDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	makeWalkTree(t, dir, 2, 3)
+	if err := Symlink("d0", filepath.Join(dir, "link")); err != nil && !testenv.SyscallIsNotSupported(err) {
+		t.Fatal(err)
+	}
+
+	noSkip := func(string, fs.DirEntry) error { return nil }
+	skipDir := func(path string, d fs.DirEntry) error {
+		if d.IsDir() && filepath.Base(path) == "d1" {
+			return fs.SkipDir
+		}
+		return nil
+	}
+	skipFile := func(path string, d fs.DirEntry) error {
+		// Skip the rest of the parent directory.
+		if filepath.Base(path) == "f1" {
+			return fs.SkipDir
+		}
+		return nil
+	}
+	skipAll := func(path string, d fs.DirEntry) error {
+		if strings.HasSuffix(path, filepath.Join("d1", "d1")) {
+			return fs.SkipAll
+		}
+		return nil
+	}
+
+	for _, root := range []string{dir, dir + string(PathSeparator), filepath.Join(dir, "d0", "..", "d2")} {
+		for _, tt := range []struct {
+			name string
+			skip func(string, fs.DirEntry) error
+		}{
+			{"NoSkip", noSkip},
+			{"SkipDir", skipDir},
+			{"SkipDirOnFile", skipFile},
+			{"SkipAll", skipAll},
+		} {
+			want, err := walkPaths(filepath.WalkDir, root, tt.skip)
+			if err != nil {
+				t.Fatalf("filepath.WalkDir(%q): %v", root, err)
+			}
+			got, err := walkPaths(WalkDir, root, tt.skip)
+			if err != nil {
+				t.Errorf("%s: WalkDir(%q): %v", tt.name, root, err)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("%s: WalkDir(%q) visited\n\t%q\nwant\n\t%q", tt.name, root, got, want)
+			}
+		}
+	}
+
+	// The WalkDirFunc's error is returned.
+	errStop := errors.New("stop")
+	err := WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if filepath.Base(path) == "f2" {
+			return errStop
+		}
+		return err
+	})
+	if err != errStop {
+		t.Errorf("WalkDir returned %v, want %v", err, errStop)
+	}
+
+	// A missing root is reported to the WalkDirFunc.
+	missing := filepath.Join(dir, "missing")
+	var gotErr error
+	err = WalkDir(missing, func(path string, d fs.DirEntry, err error) error {
+		if path != missing || d != nil {
+			t.Errorf("WalkDirFunc called with %q, %v; want %q, nil", path, d, missing)
+		}
+		gotErr = err
+		return nil
+	})
+	if err != nil || !IsNotExist(gotErr) {
+		t.Errorf("WalkDir(%q) = %v, with WalkDirFunc error %v; want nil and a not-exist error", missing, err, gotErr)
+	}
+}
+
+func BenchmarkWalkDir(b *testing.B) {
+	dir := b.TempDir()
+	makeWalkTree(b, dir, 5, 4)
+	walk := func(path string, d fs.DirEntry, err error) error { return err }
+	b.Run("os", func(b *testing.B) {
+		for range b.N {
+			if err := WalkDir(dir, walk); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("filepath", func(b *testing.B) {
+		for range b.N {
+			if err := filepath.WalkDir(dir, walk); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
 func benchmarkReaddirname(path string, b *testing.B) {
 	var nentries int
 	for i := 0; i < b.N; i++ {
@@ -665,61 +1073,243 @@ func TestReaddirnamesOneAtATime(t *testing.T) {
 	}
 }
 
-func TestReaddirNValues(t *testing.T) {
-	if testing.Short() {
-		t.Skip("test.short; skipping")
-	}
+func TestReadDirInto(t *testing.T) {
 	t.Parallel()
 
 	dir := t.TempDir()
-	for i := 1; i <= 105; i++ {
-		f, err := Create(filepath.Join(dir, fmt.Sprintf("%d", i)))
-		if err != nil {
-			t.Fatalf("Create: %v", err)
+	var want []string
+	for i := range 105 {
+		name := fmt.Sprintf("f%03d", i)
+		if err := WriteFile(filepath.Join(dir, name), nil, 0o666); err != nil {
+			t.Fatal(err)
 		}
-		f.Write([]byte(strings.Repeat("X", i)))
-		f.Close()
+		want = append(want, name)
 	}
 
-	var d *File
-	openDir := func() {
-		var err error
-		d, err = Open(dir)
-		if err != nil {
-			t.Fatalf("Open directory: %v", err)
-		}
+	f, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if n, err := f.ReadDirInto(nil, 10); n != 0 || err != nil {
+		t.Errorf("ReadDirInto(nil, 10) = %d, %v; want 0, nil", n, err)
 	}
 
-	readdirExpect := func(n, want int, wantErr error) {
-		t.Helper()
-		fi, err := d.Readdir(n)
-		if err != wantErr {
-			t.Fatalf("Readdir of %d got error %v, want %v", n, err, wantErr)
+	dst := make([]DirEntry, 10)
+	var got []DirEntry
+	for _, n := range []int{1, 3, 0, 20, -1} {
+		wantN := n
+		if n <= 0 || n > len(dst) {
+			wantN = len(dst)
 		}
-		if g, e := len(fi), want; g != e {
-			t.Errorf("Readdir of %d got %d files, want %d", n, g, e)
+		m, err := f.ReadDirInto(dst, n)
+		if err != nil {
+			t.Fatalf("ReadDirInto(dst, %d): %v", n, err)
 		}
+		if m != wantN {
+			t.Errorf("ReadDirInto(dst, %d) = %d, want %d", n, m, wantN)
+		}
+		got = append(got, dst[:m]...)
 	}
-
-	readDirExpect := func(n, want int, wantErr error) {
-		t.Helper()
-		de, err := d.ReadDir(n)
-		if err != wantErr {
-			t.Fatalf("ReadDir of %d got error %v, want %v", n, err, wantErr)
+	for {
+		m, err := f.ReadDirInto(dst, 0)
+		got = append(got, dst[:m]...)
+		if err == io.EOF {
+			if m != 0 {
+				t.Errorf("ReadDirInto returned %d entries with io.EOF, want 0", m)
+			}
+			break
 		}
-		if g, e := len(de), want; g != e {
-			t.Errorf("ReadDir of %d got %d files, want %d", n, g, e)
+		if err != nil {
+			t.Fatal(err)
 		}
 	}
 
-	readdirnamesExpect := func(n, want int, wantErr error) {
-		t.Helper()
-		fi, err := d.Readdirnames(n)
-		if err != wantErr {
-			t.Fatalf("Readdirnames of %d got error %v, want %v", n, err, wantErr)
+	// The entries collected from earlier calls must not have been
+	// changed by later calls reusing dst.
+	var names []string
+	for _, d := range got {
+		names = append(names, d.Name())
+		if !d.Type().IsRegular() {
+			t.Errorf("%s: type %v, want regular file", d.Name(), d.Type())
 		}
-		if g, e := len(fi), want; g != e {
-			t.Errorf("Readdirnames of %d got %d files, want %d", n, g, e)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, want) {
+		t.Errorf("ReadDirInto returned %v, want %v", names, want)
+	}
+}
+
+func TestReadDirSorted(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	var want []string
+	// Create the files in reverse order, so that directory order
+	// is less likely to match sorted order by accident.
+	for i := 104; i >= 0; i-- {
+		name := fmt.Sprintf("f%03d", i)
+		if err := WriteFile(filepath.Join(dir, name), nil, 0o666); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, name)
+	}
+	slices.Sort(want)
+
+	f, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var got []string
+	for {
+		dirents, err := f.ReadDirSorted(10)
+		if len(dirents) > 10 {
+			t.Fatalf("ReadDirSorted(10) returned %d entries", len(dirents))
+		}
+		for _, d := range dirents {
+			got = append(got, d.Name())
+		}
+		if err == io.EOF {
+			if len(dirents) != 0 {
+				t.Errorf("ReadDirSorted returned %d entries with io.EOF, want 0", len(dirents))
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ReadDirSorted returned %v, want %v", got, want)
+	}
+
+	// Seeking discards the buffered entries and rereads the directory.
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	dirents, err := f.ReadDirSorted(-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirents) != len(want) {
+		t.Errorf("ReadDirSorted(-1) after Seek returned %d entries, want %d", len(dirents), len(want))
+	}
+	dirents, err = f.ReadDirSorted(-1)
+	if len(dirents) != 0 || err != nil || dirents == nil {
+		t.Errorf("ReadDirSorted(-1) at end of directory = %v, %v; want empty slice, nil", dirents, err)
+	}
+}
+
+func BenchmarkReadDirInto(b *testing.B) {
+	dir := b.TempDir()
+	for i := range 50000 {
+		f, err := Create(filepath.Join(dir, fmt.Sprint(i)))
+		if err != nil {
+			b.Fatal(err)
+		}
+		f.Close()
+	}
+	const batch = 256
+
+	b.Run("ReadDir", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			f, err := Open(dir)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for {
+				_, err := f.ReadDir(batch)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			f.Close()
+		}
+	})
+	b.Run("ReadDirInto", func(b *testing.B) {
+		b.ReportAllocs()
+		dst := make([]DirEntry, batch)
+		for range b.N {
+			f, err := Open(dir)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for {
+				_, err := f.ReadDirInto(dst, 0)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			f.Close()
+		}
+	})
+}
+
+func TestReaddirNValues(t *testing.T) {
+	if testing.Short() {
+		t.Skip("test.short; skipping")
+	}
+	t.Parallel()
+
+	dir := t.TempDir()
+	for i := 1; i <= 105; i++ {
+		f, err := Create(filepath.Join(dir, fmt.Sprintf("%d", i)))
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		f.Write([]byte(strings.Repeat("X", i)))
+		f.Close()
+	}
+
+	var d *File
+	openDir := func() {
+		var err error
+		d, err = Open(dir)
+		if err != nil {
+			t.Fatalf("Open directory: %v", err)
+		}
+	}
+
+	readdirExpect := func(n, want int, wantErr error) {
+		t.Helper()
+		fi, err := d.Readdir(n)
+		if err != wantErr {
+			t.Fatalf("Readdir of %d got error %v, want %v", n, err, wantErr)
+		}
+		if g, e := len(fi), want; g != e {
+			t.Errorf("Readdir of %d got %d files, want %d", n, g, e)
+		}
+	}
+
+	readDirExpect := func(n, want int, wantErr error) {
+		t.Helper()
+		de, err := d.ReadDir(n)
+		if err != wantErr {
+			t.Fatalf("ReadDir of %d got error %v, want %v", n, err, wantErr)
+		}
+		if g, e := len(de), want; g != e {
+			t.Errorf("ReadDir of %d got %d files, want %d", n, g, e)
+		}
+	}
+
+	readdirnamesExpect := func(n, want int, wantErr error) {
+		t.Helper()
+		fi, err := d.Readdirnames(n)
+		if err != wantErr {
+			t.Fatalf("Readdirnames of %d got error %v, want %v", n, err, wantErr)
+		}
+		if g, e := len(fi), want; g != e {
+			t.Errorf("Readdirnames of %d got %d files, want %d", n, g, e)
 		}
 	}
 
@@ -908,6 +1498,72 @@ func TestHardLink(t *testing.T) {
 	}
 }
 
+func TestLinkFollow(t *testing.T) {
+	testenv.MustHaveLink(t)
+	testenv.MustHaveSymlink(t)
+	t.Parallel()
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := WriteFile(target, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sym := filepath.Join(dir, "sym")
+	if err := Symlink("target", sym); err != nil {
+		t.Fatal(err)
+	}
+	// chain is a relative symlink to sym in a different directory.
+	if err := Mkdir(filepath.Join(dir, "sub"), 0o777); err != nil {
+		t.Fatal(err)
+	}
+	chain := filepath.Join(dir, "sub", "chain")
+	if err := Symlink(filepath.Join("..", "sym"), chain); err != nil {
+		t.Fatal(err)
+	}
+
+	lstat := func(name string) FileInfo {
+		t.Helper()
+		fi, err := Lstat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fi
+	}
+
+	for _, old := range []string{target, sym, chain} {
+		newname := filepath.Join(dir, "follow-"+filepath.Base(old))
+		if err := LinkFollow(old, newname, true); err != nil {
+			t.Fatalf("LinkFollow(%q, %q, true): %v", old, newname, err)
+		}
+		if !SameFile(lstat(newname), lstat(target)) {
+			t.Errorf("LinkFollow(%q, %q, true) did not link to %q", old, newname, target)
+		}
+	}
+
+	newname := filepath.Join(dir, "nofollow")
+	err := LinkFollow(sym, newname, false)
+	if runtime.GOOS != "linux" && runtime.GOOS != "windows" && errors.Is(err, errors.ErrUnsupported) {
+		t.Logf("LinkFollow(%q, %q, false): %v", sym, newname, err)
+	} else if err != nil {
+		t.Fatalf("LinkFollow(%q, %q, false): %v", sym, newname, err)
+	} else {
+		fi := lstat(newname)
+		if fi.Mode()&ModeSymlink == 0 || !SameFile(fi, lstat(sym)) {
+			t.Errorf("LinkFollow(%q, %q, false) did not link to the symlink itself", sym, newname)
+		}
+	}
+
+	// Errors are reported as *LinkError.
+	err = LinkFollow(filepath.Join(dir, "none"), filepath.Join(dir, "none2"), true)
+	if lerr, ok := err.(*LinkError); !ok || !IsNotExist(lerr.Err) {
+		t.Errorf("LinkFollow of missing file returned %v, want *LinkError wrapping a not-exist error", err)
+	}
+	err = LinkFollow(sym, filepath.Join(dir, "follow-target"), true)
+	if lerr, ok := err.(*LinkError); !ok || !IsExist(lerr.Err) || lerr.Old != sym {
+		t.Errorf("LinkFollow to existing name returned %v, want *LinkError wrapping an exist error", err)
+	}
+}
+
 // chtmpdir changes the working directory to a new temporary directory and
 // provides a cleanup function.
 func chtmpdir(t *testing.T) func() {
@@ -1012,6 +1668,75 @@ func TestLongSymlink(t *testing.T) {
 	}
 }
 
+func TestRemoveIfExists(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "file")
+	if err := WriteFile(file, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	subdir := filepath.Join(dir, "dir")
+	if err := Mkdir(subdir, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{file, subdir} {
+		removed, err := RemoveIfExists(name)
+		if !removed || err != nil {
+			t.Errorf("RemoveIfExists(%q) = %v, %v; want true, nil", name, removed, err)
+		}
+		if _, err := Lstat(name); !IsNotExist(err) {
+			t.Errorf("Lstat(%q) after RemoveIfExists = %v, want not-exist error", name, err)
+		}
+		removed, err = RemoveIfExists(name)
+		if removed || err != nil {
+			t.Errorf("RemoveIfExists(%q) of absent name = %v, %v; want false, nil", name, removed, err)
+		}
+	}
+
+	// A non-empty directory is not removed.
+	if err := Mkdir(subdir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(filepath.Join(subdir, "file"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := RemoveIfExists(subdir); removed || err == nil {
+		t.Errorf("RemoveIfExists(%q) of non-empty directory = %v, %v; want false, error", subdir, removed, err)
+	}
+}
+
+func TestRemoveIfExistsPermission(t *testing.T) {
+	switch runtime.GOOS {
+	case "windows", "plan9", "js", "wasip1":
+		t.Skipf("skipping test on %s", runtime.GOOS)
+	}
+	if Getuid() == 0 {
+		t.Skip("skipping test when running as root")
+	}
+	t.Parallel()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := WriteFile(file, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := Chmod(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	defer Chmod(dir, 0777)
+
+	removed, err := RemoveIfExists(file)
+	if removed || !IsPermission(err) {
+		t.Errorf("RemoveIfExists(%q) in read-only directory = %v, %v; want false, permission error", file, removed, err)
+	}
+	var pe *PathError
+	if !errors.As(err, &pe) {
+		t.Errorf("RemoveIfExists error is %T, want *PathError", err)
+	}
+}
+
 func TestRename(t *testing.T) {
 	defer chtmpdir(t)()
 	from, to := "renamefrom", "renameto"
@@ -1341,6 +2066,41 @@ func TestTruncate(t *testing.T) {
 	}
 }
 
+func TestTruncateTo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		mode       TruncateMode
+		size, want int64
+	}{
+		{TruncateExact, 5, 5},
+		{TruncateExact, 20, 20},
+		{TruncateGrowOnly, 5, 10},
+		{TruncateGrowOnly, 10, 10},
+		{TruncateGrowOnly, 20, 20},
+		{TruncateShrinkOnly, 5, 5},
+		{TruncateShrinkOnly, 10, 10},
+		{TruncateShrinkOnly, 20, 10},
+	}
+	for _, tt := range tests {
+		f := newFile(t)
+		if _, err := f.Write([]byte("0123456789")); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.TruncateTo(tt.size, tt.mode); err != nil {
+			t.Errorf("TruncateTo(%d, %d) of 10-byte file: %v", tt.size, tt.mode, err)
+			continue
+		}
+		checkSize(t, f, tt.want)
+	}
+
+	f := newFile(t)
+	var pe *PathError
+	if err := f.TruncateTo(0, TruncateMode(-1)); !errors.As(err, &pe) || !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("TruncateTo with invalid mode: got %v, want PathError wrapping EINVAL", err)
+	}
+}
+
 func TestTruncateNonexistentFile(t *testing.T) {
 	t.Parallel()
 
@@ -1567,66 +2327,346 @@ func TestChtimesToUnixZero(t *testing.T) {
 	}
 }
 
-func TestFileChdir(t *testing.T) {
-	wd, err := Getwd()
-	if err != nil {
-		t.Fatalf("Getwd: %s", err)
+func TestChtimesFull(t *testing.T) {
+	t.Parallel()
+	name := filepath.Join(t.TempDir(), "file")
+	if err := WriteFile(name, []byte("hi"), 0o666); err != nil {
+		t.Fatal(err)
 	}
-	defer Chdir(wd)
 
-	fd, err := Open(".")
+	btime := time.Date(2001, time.February, 3, 4, 5, 6, 0, time.UTC)
+	atime := btime.Add(24 * time.Hour)
+	mtime := btime.Add(48 * time.Hour)
+	if err := ChtimesFull(name, atime, mtime, btime); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := Stat(name)
 	if err != nil {
-		t.Fatalf("Open .: %s", err)
+		t.Fatal(err)
+	}
+	_, gotM, _, gotB, _ := StatTimes(fi)
+	if !gotM.Equal(mtime) {
+		t.Errorf("mtime = %v, want %v", gotM, mtime)
+	}
+	switch runtime.GOOS {
+	case "darwin", "windows":
+		if !gotB.Equal(btime) {
+			t.Errorf("btime = %v, want %v", gotB, btime)
+		}
 	}
-	defer fd.Close()
 
-	if err := Chdir("/"); err != nil {
-		t.Fatalf("Chdir /: %s", err)
+	// A zero time leaves the birth time unchanged.
+	if err := ChtimesFull(name, time.Time{}, time.Time{}, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	fi, err = Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, m, _, b, _ := StatTimes(fi); !m.Equal(gotM) || !b.Equal(gotB) {
+		t.Errorf("after ChtimesFull with zero times: mtime %v, btime %v; want %v, %v", m, b, gotM, gotB)
 	}
 
-	if err := fd.Chdir(); err != nil {
-		t.Fatalf("fd.Chdir: %s", err)
+	if err := ChtimesFull(filepath.Join(t.TempDir(), "missing"), atime, mtime, btime); !IsNotExist(err) {
+		t.Errorf("ChtimesFull on missing file = %v, want not-exist error", err)
 	}
+}
 
-	wdNew, err := Getwd()
+func TestFileOpenAt(t *testing.T) {
+	t.Parallel()
+	base := t.TempDir()
+	dirName := filepath.Join(base, "dir")
+	if err := Mkdir(dirName, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	dir, err := Open(dirName)
 	if err != nil {
-		t.Fatalf("Getwd: %s", err)
+		t.Fatal(err)
 	}
+	defer dir.Close()
 
-	wdInfo, err := fd.Stat()
+	f, err := dir.OpenAt("file", O_RDWR|O_CREATE|O_EXCL, 0o666)
 	if err != nil {
 		t.Fatal(err)
 	}
-	newInfo, err := Stat(wdNew)
-	if err != nil {
+	if want := filepath.Join(dirName, "file"); f.Name() != want {
+		t.Errorf("OpenAt file name = %q, want %q", f.Name(), want)
+	}
+	if _, err := f.WriteString("hello"); err != nil {
 		t.Fatal(err)
 	}
-	if !SameFile(wdInfo, newInfo) {
-		t.Fatalf("fd.Chdir failed: got %s, want %s", wdNew, wd)
+	f.Close()
+	if b, err := ReadFile(filepath.Join(dirName, "file")); err != nil || string(b) != "hello" {
+		t.Errorf("ReadFile = %q, %v; want %q", b, err, "hello")
 	}
-}
 
-func TestChdirAndGetwd(t *testing.T) {
-	fd, err := Open(".")
+	if err := dir.MkdirAt("sub", 0o777); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := dir.StatAt("sub", true)
 	if err != nil {
-		t.Fatalf("Open .: %s", err)
+		t.Fatal(err)
 	}
-	// These are chosen carefully not to be symlinks on a Mac
-	// (unlike, say, /var, /etc), except /tmp, which we handle below.
-	dirs := []string{"/", "/usr/bin", "/tmp"}
-	// /usr/bin does not usually exist on Plan 9 or Android.
-	switch runtime.GOOS {
-	case "android":
-		dirs = []string{"/system/bin"}
-	case "plan9":
-		dirs = []string{"/", "/usr"}
-	case "ios", "windows", "wasip1":
-		dirs = nil
-		for _, dir := range []string{t.TempDir(), t.TempDir()} {
-			// Expand symlinks so path equality tests work.
-			dir, err = filepath.EvalSymlinks(dir)
-			if err != nil {
-				t.Fatalf("EvalSymlinks: %v", err)
+	if !fi.IsDir() || fi.Name() != "sub" {
+		t.Errorf("StatAt(%q) = %q, mode %v; want directory", "sub", fi.Name(), fi.Mode())
+	}
+	if err := dir.MkdirAt("sub", 0o777); !IsExist(err) {
+		t.Errorf("MkdirAt of existing directory = %v, want exist error", err)
+	}
+
+	if testenv.HasSymlink() {
+		if err := Symlink("file", filepath.Join(dirName, "link")); err != nil {
+			t.Fatal(err)
+		}
+		fi, err := dir.StatAt("link", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode()&ModeSymlink == 0 {
+			t.Errorf("StatAt(%q, false) mode = %v, want symlink", "link", fi.Mode())
+		}
+		fi, err = dir.StatAt("link", true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !fi.Mode().IsRegular() || fi.Size() != 5 {
+			t.Errorf("StatAt(%q, true) = mode %v size %d, want 5-byte regular file", "link", fi.Mode(), fi.Size())
+		}
+	}
+
+	abs := filepath.Join(base, "abs")
+	if _, err := dir.OpenAt(abs, O_RDWR|O_CREATE, 0o666); err == nil {
+		t.Errorf("OpenAt(%q) succeeded, want error for absolute name", abs)
+	}
+	if _, err := dir.StatAt(dirName, true); err == nil {
+		t.Errorf("StatAt(%q) succeeded, want error for absolute name", dirName)
+	}
+	if err := dir.MkdirAt(abs, 0o777); err == nil {
+		t.Errorf("MkdirAt(%q) succeeded, want error for absolute name", abs)
+	}
+	if _, err := Stat(abs); !IsNotExist(err) {
+		t.Errorf("absolute name was created: %v", err)
+	}
+	if _, err := dir.StatAt("missing", true); !IsNotExist(err) {
+		t.Errorf("StatAt of missing file = %v, want not-exist error", err)
+	}
+
+	switch runtime.GOOS {
+	case "windows", "plan9", "js", "wasip1":
+		// OpenAt is emulated using the directory's name.
+		return
+	}
+	if err := Rename(dirName, filepath.Join(base, "renamed")); err != nil {
+		t.Fatal(err)
+	}
+	f, err = dir.OpenAt("file", O_RDONLY, 0)
+	if err != nil {
+		t.Fatalf("OpenAt after renaming directory: %v", err)
+	}
+	b, err := io.ReadAll(f)
+	f.Close()
+	if err != nil || string(b) != "hello" {
+		t.Errorf("read %q, %v after renaming directory; want %q", b, err, "hello")
+	}
+	if err := dir.MkdirAt("sub2", 0o777); err != nil {
+		t.Fatalf("MkdirAt after renaming directory: %v", err)
+	}
+	if _, err := Stat(filepath.Join(base, "renamed", "sub2")); err != nil {
+		t.Errorf("MkdirAt did not create the directory in its new location: %v", err)
+	}
+	if _, err := dir.StatAt("sub", true); err != nil {
+		t.Errorf("StatAt after renaming directory: %v", err)
+	}
+}
+
+func TestReadlinkAt(t *testing.T) {
+	t.Parallel()
+	dirName := t.TempDir()
+	dir, err := Open(dirName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dir.Close()
+
+	if runtime.GOOS == "windows" {
+		if _, err := dir.ReadlinkAt("link"); !errors.Is(err, errors.ErrUnsupported) {
+			t.Errorf("ReadlinkAt = %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	testenv.MustHaveSymlink(t)
+
+	// Targets longer than the initial buffer must not be truncated,
+	// including ones exactly filling a doubled buffer.
+	for _, n := range []int{10, 127, 128, 129, 256, 1000, 4000} {
+		target := strings.Repeat("abcdefghi/", n/10+1)[:n]
+		name := "link" + strconv.Itoa(n)
+		if err := Symlink(target, filepath.Join(dirName, name)); err != nil {
+			t.Fatal(err)
+		}
+		got, err := dir.ReadlinkAt(name)
+		if err != nil {
+			t.Fatalf("ReadlinkAt(%q): %v", name, err)
+		}
+		if got != target {
+			t.Errorf("ReadlinkAt(%q) returned %d bytes, want %d: %q", name, len(got), len(target), got)
+		}
+		if got, err := Readlink(filepath.Join(dirName, name)); err != nil || got != target {
+			t.Errorf("Readlink(%q) returned %d bytes, %v; want %d bytes", name, len(got), err, len(target))
+		}
+	}
+
+	if _, err := dir.ReadlinkAt("missing"); !IsNotExist(err) {
+		t.Errorf("ReadlinkAt of missing link = %v, want not-exist error", err)
+	}
+	if _, err := dir.ReadlinkAt(filepath.Join(dirName, "link10")); err == nil {
+		t.Errorf("ReadlinkAt succeeded with an absolute name, want error")
+	}
+}
+
+// Test that Getwd does not return a cached result when the current
+// directory was changed behind the back of this package.
+func TestGetwdCacheChdirBypass(t *testing.T) {
+	wd, err := Getwd()
+	if err != nil {
+		t.Fatalf("Getwd: %s", err)
+	}
+	defer Chdir(wd)
+
+	dir := t.TempDir()
+	if err := syscall.Chdir(dir); err != nil {
+		t.Fatalf("syscall.Chdir: %s", err)
+	}
+	got, err := Getwd()
+	if err != nil {
+		t.Fatalf("Getwd: %s", err)
+	}
+	gotInfo, err := Stat(got)
+	if err != nil {
+		t.Fatalf("Stat %s: %s", got, err)
+	}
+	dirInfo, err := Stat(dir)
+	if err != nil {
+		t.Fatalf("Stat %s: %s", dir, err)
+	}
+	if !SameFile(gotInfo, dirInfo) {
+		t.Errorf("Getwd after syscall.Chdir(%q) = %q, want %q", dir, got, dir)
+	}
+}
+
+// Test that Getwd keeps returning a cached path after the current
+// directory is renamed, as documented, and that Chdir discards it.
+func TestGetwdCacheRename(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skipf("Getwd does not cache its result on %s", runtime.GOOS)
+	}
+	wd, err := Getwd()
+	if err != nil {
+		t.Fatalf("Getwd: %s", err)
+	}
+	defer Chdir(wd)
+
+	dir := t.TempDir()
+	old := filepath.Join(dir, "old")
+	if err := Mkdir(old, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	if err := Chdir(old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Getwd(); err != nil {
+		t.Fatalf("Getwd: %s", err)
+	}
+	if err := Rename(old, filepath.Join(dir, "new")); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Getwd()
+	if err != nil {
+		t.Fatalf("Getwd: %s", err)
+	}
+	if filepath.Base(got) != "old" {
+		t.Errorf("Getwd after renaming the current directory = %q, want the cached path ending in %q", got, "old")
+	}
+	if err := Chdir("."); err != nil {
+		t.Fatal(err)
+	}
+	got, err = Getwd()
+	if err != nil {
+		t.Fatalf("Getwd: %s", err)
+	}
+	if filepath.Base(got) != "new" {
+		t.Errorf("Getwd after Chdir(\".\") = %q, want a path ending in %q", got, "new")
+	}
+}
+
+func BenchmarkGetwd(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := Getwd(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestFileChdir(t *testing.T) {
+	wd, err := Getwd()
+	if err != nil {
+		t.Fatalf("Getwd: %s", err)
+	}
+	defer Chdir(wd)
+
+	fd, err := Open(".")
+	if err != nil {
+		t.Fatalf("Open .: %s", err)
+	}
+	defer fd.Close()
+
+	if err := Chdir("/"); err != nil {
+		t.Fatalf("Chdir /: %s", err)
+	}
+
+	if err := fd.Chdir(); err != nil {
+		t.Fatalf("fd.Chdir: %s", err)
+	}
+
+	wdNew, err := Getwd()
+	if err != nil {
+		t.Fatalf("Getwd: %s", err)
+	}
+
+	wdInfo, err := fd.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newInfo, err := Stat(wdNew)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !SameFile(wdInfo, newInfo) {
+		t.Fatalf("fd.Chdir failed: got %s, want %s", wdNew, wd)
+	}
+}
+
+func TestChdirAndGetwd(t *testing.T) {
+	fd, err := Open(".")
+	if err != nil {
+		t.Fatalf("Open .: %s", err)
+	}
+	// These are chosen carefully not to be symlinks on a Mac
+	// (unlike, say, /var, /etc), except /tmp, which we handle below.
+	dirs := []string{"/", "/usr/bin", "/tmp"}
+	// /usr/bin does not usually exist on Plan 9 or Android.
+	switch runtime.GOOS {
+	case "android":
+		dirs = []string{"/system/bin"}
+	case "plan9":
+		dirs = []string{"/", "/usr"}
+	case "ios", "windows", "wasip1":
+		dirs = nil
+		for _, dir := range []string{t.TempDir(), t.TempDir()} {
+			// Expand symlinks so path equality tests work.
+			dir, err = filepath.EvalSymlinks(dir)
+			if err != nil {
+				t.Fatalf("EvalSymlinks: %v", err)
 			}
 			dirs = append(dirs, dir)
 		}
@@ -2003,6 +3043,35 @@ func TestHostname(t *testing.T) {
 	}
 }
 
+func TestFQDN(t *testing.T) {
+	t.Parallel()
+
+	hostname, err := Hostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fqdn, err := FQDN()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("Hostname() = %q, FQDN() = %q", hostname, fqdn)
+	if fqdn == "" {
+		t.Fatal("FQDN returned empty string and no error")
+	}
+	if strings.Contains(fqdn, "\x00") {
+		t.Fatalf("unexpected zero byte in FQDN: %q", fqdn)
+	}
+	// The fully qualified name extends the host name, except on Windows
+	// where Hostname reports the physical rather than the DNS name.
+	if runtime.GOOS != "windows" {
+		host, _, _ := strings.Cut(fqdn, ".")
+		short, _, _ := strings.Cut(hostname, ".")
+		if !strings.EqualFold(host, short) {
+			t.Errorf("FQDN() = %q does not start with host name %q", fqdn, hostname)
+		}
+	}
+}
+
 func TestReadAt(t *testing.T) {
 	t.Parallel()
 
@@ -2234,6 +3303,107 @@ func TestSameFile(t *testing.T) {
 	}
 }
 
+func TestFileID(t *testing.T) {
+	testenv.MustHaveLink(t)
+	t.Parallel()
+
+	dir := t.TempDir()
+	a, b, link := filepath.Join(dir, "a"), filepath.Join(dir, "b"), filepath.Join(dir, "link")
+	for _, name := range []string{a, b} {
+		if err := WriteFile(name, nil, 0o666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Link(a, link); err != nil {
+		t.Fatal(err)
+	}
+
+	id := func(name string) [2]uint64 {
+		t.Helper()
+		fi, err := Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dev, ino, ok := FileID(fi)
+		if !ok {
+			t.Fatalf("FileID(Stat(%q)) not available", name)
+		}
+		return [2]uint64{dev, ino}
+	}
+	if ida, idl := id(a), id(link); ida != idl {
+		t.Errorf("hard links have different identities %v and %v", ida, idl)
+	}
+	if ida, idb := id(a), id(b); ida == idb {
+		t.Errorf("distinct files have the same identity %v", ida)
+	}
+
+	mapInfo, err := fs.Stat(fstest.MapFS{"x": {}}, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := FileID(mapInfo); ok {
+		t.Errorf("FileID of a FileInfo not from this package: ok = true, want false")
+	}
+}
+
+func TestSameFileEntry(t *testing.T) {
+	testenv.MustHaveLink(t)
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"a", "c"} {
+		if err := WriteFile(filepath.Join(dir, name), []byte(name), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Link(filepath.Join(dir, "a"), filepath.Join(dir, "b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := Mkdir(filepath.Join(dir, "d"), 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("ReadDir returned %d entries, want 4", len(entries))
+	}
+	a, b, c, d := entries[0], entries[1], entries[2], entries[3]
+
+	for _, tt := range []struct {
+		x, y DirEntry
+		want bool
+	}{
+		{a, b, true},
+		{b, a, true},
+		{a, a, true},
+		{d, d, true},
+		{a, c, false},
+		{b, c, false},
+		{a, d, false},
+	} {
+		same, err := SameFileEntry(tt.x, tt.y)
+		if err != nil {
+			t.Errorf("SameFileEntry(%s, %s): %v", tt.x.Name(), tt.y.Name(), err)
+		} else if same != tt.want {
+			t.Errorf("SameFileEntry(%s, %s) = %v, want %v", tt.x.Name(), tt.y.Name(), same, tt.want)
+		}
+
+		fi, err := Lstat(filepath.Join(dir, tt.x.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		same, err = SameFileInfoEntry(fi, tt.y)
+		if err != nil {
+			t.Errorf("SameFileInfoEntry(%s, %s): %v", tt.x.Name(), tt.y.Name(), err)
+		} else if same != tt.want {
+			t.Errorf("SameFileInfoEntry(%s, %s) = %v, want %v", tt.x.Name(), tt.y.Name(), same, tt.want)
+		}
+	}
+}
+
 func testDevNullFileInfo(t *testing.T, statname, devNullName string, fi FileInfo) {
 	pre := fmt.Sprintf("%s(%q): ", statname, devNullName)
 	if fi.Size() != 0 {
@@ -2588,36 +3758,241 @@ func TestGetppid(t *testing.T) {
 	}
 }
 
-func TestKillFindProcess(t *testing.T) {
-	testKillProcess(t, func(p *Process) {
-		p2, err := FindProcess(p.Pid)
-		if err != nil {
-			t.Fatalf("Failed to find test process: %v", err)
-		}
-		err = p2.Kill()
-		if err != nil {
-			t.Fatalf("Failed to kill test process: %v", err)
+func TestProcessStateRusage(t *testing.T) {
+	const size = 64 << 20
+	if Getenv("GO_WANT_HELPER_PROCESS") == "1" {
+		b := make([]byte, size)
+		for i := 0; i < len(b); i += 4096 {
+			b[i] = 1
 		}
-	})
-}
+		Exit(0)
+	}
 
-var nilFileMethodTests = []struct {
+	testenv.MustHaveExec(t)
+	t.Parallel()
+
+	cmd := testenv.Command(t, Args[0], "-test.run=^TestProcessStateRusage$")
+	cmd.Env = append(Environ(), "GO_WANT_HELPER_PROCESS=1")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to run child process: %v %q", err, output)
+	}
+
+	ps := cmd.ProcessState
+	rss := ps.MaxRSS()
+	minflt, majflt := ps.MinorPageFaults(), ps.MajorPageFaults()
+	vcsw, ivcsw := ps.ContextSwitches()
+	t.Logf("MaxRSS %d, page faults %d minor %d major, context switches %d voluntary %d involuntary", rss, minflt, majflt, vcsw, ivcsw)
+	if rss < 0 || minflt < 0 || majflt < 0 || vcsw < 0 || ivcsw < 0 {
+		t.Errorf("negative resource usage")
+	}
+	if runtime.GOOS == "linux" {
+		if rss < size {
+			t.Errorf("MaxRSS = %d, want at least %d", rss, size)
+		}
+		if minflt == 0 {
+			t.Errorf("MinorPageFaults = 0, want non-zero")
+		}
+	}
+
+	var nilPS *ProcessState
+	if nilPS.MaxRSS() != 0 {
+		t.Errorf("MaxRSS of nil ProcessState is non-zero")
+	}
+}
+
+func TestProcessWaitContext(t *testing.T) {
+	testProcessWaitContext(t, func(p *Process) *Process { return p })
+}
+
+// testProcessWaitContext starts a process that exits once its standard
+// input is closed, and checks that WaitContext gives up waiting on it
+// without reaping it. wrap may replace the started Process with another
+// Process for the same child.
+func testProcessWaitContext(t *testing.T, wrap func(*Process) *Process) {
+	testenv.MustHaveExec(t)
+	t.Parallel()
+
+	r, w, err := Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	attr := &ProcAttr{
+		Env:   append(Environ(), "GO_OS_TEST_DRAIN_STDIN=1"),
+		Files: []*File{r, nil, Stderr},
+	}
+	p, err := StartProcess(Args[0], []string{Args[0]}, attr)
+	r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p = wrap(p)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.WaitContext(canceled); err != context.Canceled {
+		t.Errorf("WaitContext with canceled context: got %v, want %v", err, context.Canceled)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = p.WaitContext(ctx)
+	if errors.Is(err, errors.ErrUnsupported) {
+		p.Kill()
+		p.Wait()
+		t.Skipf("skipping: %v", err)
+	}
+	if err != context.DeadlineExceeded {
+		t.Errorf("WaitContext with timeout: got %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// The process must still be waitable once it exits.
+	w.Close()
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	ps, err := p.WaitContext(ctx)
+	if err != nil {
+		t.Fatalf("WaitContext after exit: %v", err)
+	}
+	if !ps.Success() {
+		t.Errorf("process exited with %v, want success", ps)
+	}
+	if _, err := p.Wait(); err == nil {
+		t.Errorf("Wait after WaitContext succeeded; process was not reaped")
+	}
+}
+
+func TestProcessKillGroup(t *testing.T) {
+	switch Getenv("GO_WANT_HELPER_PROCESS") {
+	case "1":
+		// Start a grandchild sharing our standard output, then wait
+		// to be killed.
+		attr := &ProcAttr{
+			Env:   append(Environ(), "GO_WANT_HELPER_PROCESS=2"),
+			Files: []*File{nil, Stdout, Stderr},
+		}
+		if _, err := StartProcess(Args[0], []string{Args[0], "-test.run=^TestProcessKillGroup$"}, attr); err != nil {
+			fmt.Fprintln(Stderr, err)
+			Exit(1)
+		}
+		Stdout.WriteString("ready")
+		time.Sleep(time.Hour)
+		Exit(0)
+	case "2":
+		time.Sleep(time.Hour)
+		Exit(0)
+	}
+
+	testenv.MustHaveExec(t)
+	t.Parallel()
+
+	r, w, err := Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	attr := &ProcAttr{
+		Env:             append(Environ(), "GO_WANT_HELPER_PROCESS=1"),
+		Files:           []*File{nil, w, Stderr},
+		NewProcessGroup: true,
+	}
+	p, err := StartProcess(Args[0], []string{Args[0], "-test.run=^TestProcessKillGroup$"}, attr)
+	w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ready := make([]byte, len("ready"))
+	if _, err := io.ReadFull(r, ready); err != nil {
+		p.Kill()
+		p.Wait()
+		t.Fatalf("reading from child: %v", err)
+	}
+
+	if p2, err := FindProcess(p.Pid); err == nil {
+		if err := p2.KillGroup(); err == nil {
+			t.Errorf("KillGroup succeeded on a Process not started in a new group")
+		}
+	}
+
+	if err := p.KillGroup(); err != nil {
+		p.Kill()
+		p.Wait()
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skipf("skipping: %v", err)
+		}
+		t.Fatalf("KillGroup: %v", err)
+	}
+
+	// Both the child and the grandchild hold the write end of the pipe,
+	// so reading reaches EOF only once neither is alive.
+	if _, err := io.ReadAll(r); err != nil {
+		t.Errorf("reading from child: %v", err)
+	}
+	ps, err := p.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ps.Success() {
+		t.Errorf("child exited successfully, want it killed")
+	}
+	if err := p.KillGroup(); err != ErrProcessDone {
+		t.Errorf("KillGroup after Wait = %v, want %v", err, ErrProcessDone)
+	}
+}
+
+func TestKillFindProcess(t *testing.T) {
+	testKillProcess(t, func(p *Process) {
+		p2, err := FindProcess(p.Pid)
+		if err != nil {
+			t.Fatalf("Failed to find test process: %v", err)
+		}
+		err = p2.Kill()
+		if err != nil {
+			t.Fatalf("Failed to kill test process: %v", err)
+		}
+	})
+}
+
+var nilFileMethodTests = []struct {
 	name string
 	f    func(*File) error
 }{
+	{"Allocate", func(f *File) error { return f.Allocate(0, 1) }},
 	{"Chdir", func(f *File) error { return f.Chdir() }},
 	{"Close", func(f *File) error { return f.Close() }},
+	{"Fadvise", func(f *File) error { return f.Fadvise(0, 0, FadviceNormal) }},
 	{"Chmod", func(f *File) error { return f.Chmod(0) }},
 	{"Chown", func(f *File) error { return f.Chown(0, 0) }},
+	{"Datasync", func(f *File) error { return f.Datasync() }},
+	{"LinkTo", func(f *File) error { return f.LinkTo("x") }},
+	{"Lock", func(f *File) error { return f.Lock() }},
+	{"Map", func(f *File) error { _, err := f.Map(0, 1, ProtRead, MapShared); return err }},
+	{"MkdirAt", func(f *File) error { return f.MkdirAt("x", 0o777) }},
+	{"OpenAt", func(f *File) error { _, err := f.OpenAt("x", O_RDONLY, 0); return err }},
 	{"Read", func(f *File) error { _, err := f.Read(make([]byte, 0)); return err }},
 	{"ReadAt", func(f *File) error { _, err := f.ReadAt(make([]byte, 0), 0); return err }},
+	{"ReadDirInto", func(f *File) error { _, err := f.ReadDirInto(make([]DirEntry, 1), 1); return err }},
+	{"ReadDirSorted", func(f *File) error { _, err := f.ReadDirSorted(1); return err }},
 	{"Readdir", func(f *File) error { _, err := f.Readdir(1); return err }},
 	{"Readdirnames", func(f *File) error { _, err := f.Readdirnames(1); return err }},
+	{"ReadlinkAt", func(f *File) error { _, err := f.ReadlinkAt("x"); return err }},
+	{"Readv", func(f *File) error { _, err := f.Readv(nil); return err }},
+	{"ReadvAt", func(f *File) error { _, err := f.ReadvAt(nil, 0); return err }},
+	{"Removexattr", func(f *File) error { return f.Removexattr("user.x") }},
 	{"Seek", func(f *File) error { _, err := f.Seek(0, io.SeekStart); return err }},
+	{"Setxattr", func(f *File) error { return f.Setxattr("user.x", nil, 0) }},
 	{"Stat", func(f *File) error { _, err := f.Stat(); return err }},
+	{"StatAt", func(f *File) error { _, err := f.StatAt("x", true); return err }},
 	{"Sync", func(f *File) error { return f.Sync() }},
+	{"SyncRange", func(f *File) error { return f.SyncRange(0, 0, SyncRangeWrite) }},
 	{"Truncate", func(f *File) error { return f.Truncate(0) }},
+	{"TruncateTo", func(f *File) error { return f.TruncateTo(0, TruncateGrowOnly) }},
+	{"TryLock", func(f *File) error { _, err := f.TryLock(); return err }},
+	{"Unlock", func(f *File) error { return f.Unlock() }},
 	{"Write", func(f *File) error { _, err := f.Write(make([]byte, 0)); return err }},
+	{"Writev", func(f *File) error { _, err := f.Writev(nil); return err }},
+	{"WritevAt", func(f *File) error { _, err := f.WritevAt(nil, 0); return err }},
 	{"WriteAt", func(f *File) error { _, err := f.WriteAt(make([]byte, 0), 0); return err }},
 	{"WriteString", func(f *File) error { _, err := f.WriteString(""); return err }},
 }
@@ -2837,6 +4212,60 @@ func TestUserConfigDir(t *testing.T) {
 	}
 }
 
+func TestUserStateDir(t *testing.T) {
+	env, value, want := "HOME", "/home/gopher", "/home/gopher/.local/state"
+	switch runtime.GOOS {
+	case "windows":
+		env, value, want = "LocalAppData", `C:\state`, `C:\state`
+	case "darwin", "ios":
+		want = "/home/gopher/Library/Application Support"
+	case "plan9":
+		env, value, want = "home", "/usr/gopher", "/usr/gopher/lib/state"
+	default:
+		t.Setenv("XDG_STATE_HOME", "/state")
+		if dir, err := UserStateDir(); err != nil || dir != "/state" {
+			t.Errorf("UserStateDir() = %q, %v; want %q, <nil>", dir, err, "/state")
+		}
+		t.Setenv("XDG_STATE_HOME", "")
+	}
+
+	t.Setenv(env, value)
+	if dir, err := UserStateDir(); err != nil || dir != want {
+		t.Errorf("UserStateDir() = %q, %v; want %q, <nil>", dir, err, want)
+	}
+
+	t.Setenv(env, "")
+	if dir, err := UserStateDir(); err == nil {
+		t.Errorf("UserStateDir() with $%s unset = %q, want error", env, dir)
+	}
+}
+
+func TestUserRuntimeDir(t *testing.T) {
+	var env string
+	switch runtime.GOOS {
+	case "windows", "plan9":
+		dir, err := UserRuntimeDir()
+		if err != nil || dir == "" {
+			t.Fatalf("UserRuntimeDir() = %q, %v; want non-empty path", dir, err)
+		}
+		return
+	case "darwin", "ios":
+		env = "TMPDIR"
+	default:
+		env = "XDG_RUNTIME_DIR"
+	}
+
+	t.Setenv(env, "/run/user/1000")
+	if dir, err := UserRuntimeDir(); err != nil || dir != "/run/user/1000" {
+		t.Errorf("UserRuntimeDir() = %q, %v; want %q, <nil>", dir, err, "/run/user/1000")
+	}
+
+	t.Setenv(env, "")
+	if dir, err := UserRuntimeDir(); err == nil {
+		t.Errorf("UserRuntimeDir() with $%s unset = %q, want error", env, dir)
+	}
+}
+
 func TestUserHomeDir(t *testing.T) {
 	t.Parallel()
 
@@ -3060,6 +4489,91 @@ func TestDirFS(t *testing.T) {
 	}
 }
 
+func TestDirFSReadLink(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := Mkdir(filepath.Join(dir, "sub"), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(filepath.Join(dir, "sub", "file"), []byte("hello"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := Symlink("file", filepath.Join(dir, "sub", "link")); err != nil {
+		t.Fatal(err)
+	}
+	forceMFTUpdateOnWindows(t, dir)
+
+	fsys := DirFS(dir)
+	if _, ok := fsys.(fs.StatFS); !ok {
+		t.Error("expected DirFS result to implement fs.StatFS")
+	}
+	if _, ok := fsys.(fs.ReadLinkFS); !ok {
+		t.Fatal("expected DirFS result to implement fs.ReadLinkFS")
+	}
+	if _, ok := fsys.(fs.SubFS); !ok {
+		t.Fatal("expected DirFS result to implement fs.SubFS")
+	}
+
+	sub, err := fs.Sub(fsys, "sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fstest.TestFS(sub, "file", "link"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		fsys fs.FS
+		name string
+	}{
+		{fsys, "sub/link"},
+		{sub, "link"},
+	} {
+		fi, err := fs.Lstat(tt.fsys, tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode()&ModeSymlink == 0 {
+			t.Errorf("Lstat(%q).Mode() = %v, want a symlink", tt.name, fi.Mode())
+		}
+		fi, err = fs.Stat(tt.fsys, tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !fi.Mode().IsRegular() {
+			t.Errorf("Stat(%q).Mode() = %v, want a regular file", tt.name, fi.Mode())
+		}
+		target, err := fs.ReadLink(tt.fsys, tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if target != "file" {
+			t.Errorf("ReadLink(%q) = %q, want %q", tt.name, target, "file")
+		}
+	}
+
+	// Errors report the name within the DirFS, and names leaving it
+	// are rejected as by Open.
+	if _, err := fs.ReadLink(fsys, "sub/file"); err == nil {
+		t.Error("ReadLink of a regular file succeeded")
+	} else if pe, ok := err.(*PathError); !ok || pe.Path != "sub/file" {
+		t.Errorf("ReadLink of a regular file: got error %#v, want a *PathError for %q", err, "sub/file")
+	}
+	for _, name := range []string{"../x", "/sub/link", "sub/../sub/link"} {
+		if _, err := fs.ReadLink(fsys, name); err == nil {
+			t.Errorf("ReadLink(%q) succeeded", name)
+		}
+		if _, err := fs.Lstat(fsys, name); err == nil {
+			t.Errorf("Lstat(%q) succeeded", name)
+		}
+		if _, err := fs.Sub(fsys, name); err == nil {
+			t.Errorf("Sub(%q) succeeded", name)
+		}
+	}
+}
+
 func TestDirFSRootDir(t *testing.T) {
 	t.Parallel()
 
@@ -3145,6 +4659,56 @@ func TestReadFileProc(t *testing.T) {
 	}
 }
 
+func TestReadFileProcLarge(t *testing.T) {
+	t.Parallel()
+
+	// Like the file in TestReadFileProc, /proc/self/maps reports size 0,
+	// but it is longer than the first read of ReadFile, so the buffer
+	// has to grow.
+	name := "/proc/self/maps"
+	fi, err := Stat(name)
+	if err != nil {
+		t.Skip(err)
+	}
+	if fi.Size() != 0 {
+		t.Skipf("%s reports size %d, want 0", name, fi.Size())
+	}
+	data, err := ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) <= 512 {
+		t.Skipf("%s is only %d bytes long", name, len(data))
+	}
+	if data[len(data)-1] != '\n' || !bytes.Contains(data, []byte("[stack]")) {
+		t.Fatalf("read %s: got %d bytes, not newline-terminated or without the stack mapping:\n%s", name, len(data), data)
+	}
+}
+
+func BenchmarkReadFile(b *testing.B) {
+	for _, size := range []int{4 << 10, 100 << 20} {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			name := filepath.Join(b.TempDir(), "file")
+			if err := WriteFile(name, make([]byte, size), 0666); err != nil {
+				b.Fatal(err)
+			}
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				data, err := ReadFile(name)
+				if err != nil {
+					b.Fatal(err)
+				}
+				// The buffer is sized from Stat, with one byte for
+				// the read at EOF, so it is never grown.
+				if cap(data) != size+1 {
+					b.Fatalf("ReadFile returned a buffer of capacity %d, want %d", cap(data), size+1)
+				}
+			}
+		})
+	}
+}
+
 func TestDirFSReadFileProc(t *testing.T) {
 	t.Parallel()
 
@@ -3409,6 +4973,148 @@ func TestCopyFS(t *testing.T) {
 	}
 }
 
+// linkMapFS is a MapFS in which entries with ModeSymlink
+// are symbolic links whose Data is the link target.
+type linkMapFS struct {
+	fstest.MapFS
+}
+
+func (fsys linkMapFS) ReadLink(name string) (string, error) {
+	f, ok := fsys.MapFS[name]
+	if !ok || f.Mode&ModeSymlink == 0 {
+		return "", &PathError{Op: "readlink", Path: name, Err: ErrInvalid}
+	}
+	return string(f.Data), nil
+}
+
+func (fsys linkMapFS) Lstat(name string) (fs.FileInfo, error) {
+	return fs.Stat(fsys.MapFS, name)
+}
+
+func TestCopyFSMapFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"top":                 {Data: []byte("top\n")},
+		"dir/sub/nested":      {Data: []byte("nested\n")},
+		"dir/script":          {Data: []byte("#!/bin/sh\n"), Mode: 0o755},
+		"dir/sub/link-nested": {Data: []byte("nested"), Mode: ModeSymlink},
+		"dir/link-top":        {Data: []byte("../top"), Mode: ModeSymlink},
+	}
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		// These systems may not allow creating symbolic links.
+		delete(fsys, "dir/sub/link-nested")
+		delete(fsys, "dir/link-top")
+	} else {
+		testenv.MustHaveSymlink(t)
+	}
+	dir := t.TempDir()
+	if err := CopyFS(dir, linkMapFS{fsys}); err != nil {
+		t.Fatal("CopyFS:", err)
+	}
+
+	for name, f := range fsys {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		fi, err := Lstat(path)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if f.Mode&ModeSymlink != 0 {
+			target, err := Readlink(path)
+			if err != nil || target != string(f.Data) {
+				t.Errorf("Readlink(%s) = %q, %v; want %q", name, target, err, f.Data)
+			}
+			continue
+		}
+		if !fi.Mode().IsRegular() {
+			t.Errorf("%s: mode %v; want a regular file", name, fi.Mode())
+		}
+		if data, err := ReadFile(path); err != nil || !bytes.Equal(data, f.Data) {
+			t.Errorf("ReadFile(%s) = %q, %v; want %q", name, data, err, f.Data)
+		}
+		if runtime.GOOS != "windows" && runtime.GOOS != "plan9" && f.Mode&0o111 != 0 && fi.Mode()&0o111 == 0 {
+			t.Errorf("%s: mode %v; want execute permission from source mode %v", name, fi.Mode(), f.Mode)
+		}
+	}
+}
+
+type escapeFS struct {
+	fstest.MapFS
+}
+
+func (fsys escapeFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fsys.MapFS.ReadDir(name)
+	if err != nil || name != "." {
+		return entries, err
+	}
+	// Pretend there is a file named "..".
+	info, err := fs.Stat(fsys.MapFS, "file")
+	if err != nil {
+		return nil, err
+	}
+	return append(entries, dotDotEntry{fs.FileInfoToDirEntry(info)}), nil
+}
+
+type dotDotEntry struct {
+	fs.DirEntry
+}
+
+func (dotDotEntry) Name() string { return ".." }
+
+func TestCopyFSRejectsEscapes(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	err := CopyFS(filepath.Join(dir, "dst"), escapeFS{fstest.MapFS{"file": {Data: []byte("x")}}})
+	if err == nil {
+		t.Error("CopyFS of a file named \"..\" succeeded")
+	}
+	if _, err := Stat(filepath.Join(dir, "file")); err == nil {
+		t.Error("CopyFS wrote outside the destination directory")
+	}
+
+	for _, link := range []struct{ name, target string }{
+		{"link", "../outside"},
+		{"dir/link", "../../outside"},
+		{"dir/link", "sub/../../../outside"},
+		{"dir/link", "/etc/passwd"},
+	} {
+		fsys := linkMapFS{fstest.MapFS{
+			link.name: {Data: []byte(link.target), Mode: ModeSymlink},
+		}}
+		if err := CopyFS(t.TempDir(), fsys); !errors.Is(err, ErrInvalid) {
+			t.Errorf("CopyFS of %s linking to %q: got %v, want ErrInvalid", link.name, link.target, err)
+		}
+	}
+
+	// Each of these links is local on its own, but together they lead
+	// outside the copy, whichever comes first.
+	for _, fsys := range []fstest.MapFS{
+		{
+			"x/y": {Data: []byte(".."), Mode: ModeSymlink},
+			"z":   {Data: []byte("x/y/../outside"), Mode: ModeSymlink},
+		},
+		{
+			"a":   {Data: []byte("d/e/../outside"), Mode: ModeSymlink},
+			"d/e": {Data: []byte(".."), Mode: ModeSymlink},
+		},
+		{
+			"loop": {Data: []byte("loop/x"), Mode: ModeSymlink},
+		},
+	} {
+		dst := t.TempDir()
+		if err := CopyFS(dst, linkMapFS{fsys}); !errors.Is(err, ErrInvalid) {
+			t.Errorf("CopyFS of chained links %v: got %v, want ErrInvalid", fsys, err)
+		}
+		for name := range fsys {
+			if _, err := Lstat(filepath.Join(dst, filepath.FromSlash(name))); err == nil {
+				t.Errorf("CopyFS created link %s despite the error", name)
+			}
+		}
+	}
+}
+
 func TestCopyFSWithSymlinks(t *testing.T) {
 	// Test it with absolute and relative symlinks that point inside and outside the tree.
 	testenv.MustHaveSymlink(t)
@@ -3479,17 +5185,26 @@ func TestCopyFSWithSymlinks(t *testing.T) {
 		t.Fatalf("MkdirTemp: %v", err)
 	}
 
-	// TODO(panjf2000): symlinks are currently not supported, and a specific error
-	// 			will be returned. Verify that error and skip the subsequent test,
-	//			revisit this once #49580 is closed.
+	// The links pointing outside the tree cannot be recreated in the copy.
 	if err := CopyFS(tmpDupDir, fsys); !errors.Is(err, ErrInvalid) {
 		t.Fatalf("got %v, want ErrInvalid", err)
 	}
-	t.Skip("skip the subsequent test and wait for #49580")
+
+	// Without them, the copy succeeds and keeps the remaining link.
+	if err := RemoveAll(linkOutDir); err != nil {
+		t.Fatal(err)
+	}
+	tmpDupDir, err = MkdirTemp(tmpDir, "copyfs_dup_")
+	if err != nil {
+		t.Fatalf("MkdirTemp: %v", err)
+	}
+	if err := CopyFS(tmpDupDir, fsys); err != nil {
+		t.Fatalf("CopyFS: %v", err)
+	}
 
 	forceMFTUpdateOnWindows(t, tmpDupDir)
 	tmpFsys := DirFS(tmpDupDir)
-	if err := fstest.TestFS(tmpFsys, "file.in.txt", "out_symlinks/file.abs.out.link", "out_symlinks/file.rel.out.link", "in_symlinks/file.rel.in.link"); err != nil {
+	if err := fstest.TestFS(tmpFsys, "file.in.txt", "in_symlinks/file.rel.in.link"); err != nil {
 		t.Fatal("TestFS:", err)
 	}
 	if err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
@@ -3497,24 +5212,31 @@ func TestCopyFSWithSymlinks(t *testing.T) {
 			return nil
 		}
 
-		fi, err := d.Info()
-		if err != nil {
-			return err
-		}
 		if filepath.Ext(path) == ".link" {
-			if fi.Mode()&ModeSymlink == 0 {
+			if d.Type() != ModeSymlink {
 				return errors.New("original file " + path + " should be a symlink")
 			}
-			tmpfi, err := fs.Stat(tmpFsys, path)
+			tmpfi, err := fs.Lstat(tmpFsys, path)
 			if err != nil {
 				return err
 			}
-			if tmpfi.Mode()&ModeSymlink != 0 {
-				return errors.New("copied file " + path + " should not be a symlink")
+			if tmpfi.Mode()&ModeSymlink == 0 {
+				return errors.New("copied file " + path + " should be a symlink")
 			}
-		}
-
-		data, err := fs.ReadFile(fsys, path)
+			target, err := fs.ReadLink(fsys, path)
+			if err != nil {
+				return err
+			}
+			newTarget, err := fs.ReadLink(tmpFsys, path)
+			if err != nil {
+				return err
+			}
+			if target != newTarget {
+				return errors.New("copied link " + path + " points to " + newTarget + ", want " + target)
+			}
+		}
+
+		data, err := fs.ReadFile(fsys, path)
 		if err != nil {
 			return err
 		}
@@ -3525,26 +5247,749 @@ func TestCopyFSWithSymlinks(t *testing.T) {
 		if !bytes.Equal(data, newData) {
 			return errors.New("file " + path + " contents differ")
 		}
+		return nil
+	}); err != nil {
+		t.Fatal("comparing two directories:", err)
+	}
+}
+
+func TestReadAtFile(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "file")
+	if err := WriteFile(name, []byte("hello, world\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	b := make([]byte, 5)
+	n, err := ReadAtFile(name, b, 7)
+	if n != 5 || err != nil || string(b) != "world" {
+		t.Errorf("ReadAtFile(%q, 5 bytes, 7) = %d, %v, %q; want 5, nil, %q", name, n, err, b, "world")
+	}
+
+	n, err = ReadAtFile(name, b, 10)
+	if n != 3 || err != io.EOF || string(b[:n]) != "ld\n" {
+		t.Errorf("ReadAtFile(%q, 5 bytes, 10) = %d, %v, %q; want 3, io.EOF, %q", name, n, err, b[:n], "ld\n")
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing")
+	if _, err := ReadAtFile(missing, b, 0); !IsNotExist(err) {
+		t.Errorf("ReadAtFile(%q) = %v, want a not-exist error", missing, err)
+	} else if pe, ok := err.(*PathError); !ok || pe.Path != missing {
+		t.Errorf("ReadAtFile(%q) error = %#v, want a *PathError for the file", missing, err)
+	}
+}
+
+func TestWriteAtFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "file")
+	if err := WriteFile(name, []byte("hello, world\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := WriteAtFile(name, []byte("there"), 7); n != 5 || err != nil {
+		t.Fatalf("WriteAtFile = %d, %v; want 5, nil", n, err)
+	}
+	if got, err := ReadFile(name); err != nil {
+		t.Fatal(err)
+	} else if string(got) != "hello, there\n" {
+		t.Errorf("file contents = %q, want %q", got, "hello, there\n")
+	}
+
+	// WriteAtFile does not create the file.
+	missing := filepath.Join(dir, "missing")
+	if _, err := WriteAtFile(missing, []byte("x"), 0); !IsNotExist(err) {
+		t.Errorf("WriteAtFile(%q) = %v, want a not-exist error", missing, err)
+	}
+	if _, err := Stat(missing); !IsNotExist(err) {
+		t.Errorf("WriteAtFile created %q", missing)
+	}
+
+	// WriteAtFileFlag does, when asked to.
+	if n, err := WriteAtFileFlag(missing, []byte("x"), 3, O_CREATE, 0666); n != 1 || err != nil {
+		t.Fatalf("WriteAtFileFlag(O_CREATE) = %d, %v; want 1, nil", n, err)
+	}
+	if got, err := ReadFile(missing); err != nil {
+		t.Fatal(err)
+	} else if string(got) != "\x00\x00\x00x" {
+		t.Errorf("file contents = %q, want %q", got, "\x00\x00\x00x")
+	}
+}
 
-		var target string
-		switch fileName := filepath.Base(path); fileName {
-		case "file.abs.out.link", "file.rel.out.link":
-			target = outsideFile
-		case "file.rel.in.link":
-			target = insideFile
+func TestIsTerminal(t *testing.T) {
+	t.Parallel()
+
+	r, w, err := Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	f, err := Create(filepath.Join(t.TempDir(), "file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []*File{r, w, f} {
+		if f.IsTerminal() {
+			t.Errorf("%s.IsTerminal() = true, want false", f.Name())
 		}
-		if len(target) > 0 {
-			targetData, err := ReadFile(target)
+		if IsTerminal(f.Fd()) {
+			t.Errorf("IsTerminal(%s.Fd()) = true, want false", f.Name())
+		}
+	}
+	f.Close()
+	if f.IsTerminal() {
+		t.Error("IsTerminal of a closed file = true, want false")
+	}
+	if (*File)(nil).IsTerminal() {
+		t.Error("IsTerminal of a nil file = true, want false")
+	}
+}
+
+func TestTerminalSizeNotTerminal(t *testing.T) {
+	t.Parallel()
+
+	r, w, err := Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if cols, rows, err := r.TerminalSize(); err == nil {
+		t.Errorf("TerminalSize of a pipe = %d, %d, nil; want an error", cols, rows)
+	} else if _, ok := err.(*PathError); !ok {
+		t.Errorf("TerminalSize of a pipe: got %T, want *PathError", err)
+	}
+	r.Close()
+	if _, _, err := r.TerminalSize(); !errors.Is(err, ErrClosed) {
+		t.Errorf("TerminalSize of a closed file = %v, want ErrClosed", err)
+	}
+}
+
+func TestCopyFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	data := []byte("the quick brown fox jumps over the lazy dog\n")
+	if err := WriteFile(src, data, 0o640); err != nil {
+		t.Fatal(err)
+	}
+	// Set the mode explicitly so that the umask does not interfere.
+	if err := Chmod(src, 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name     string
+		existing []byte // nil means dst does not exist
+	}{
+		{"New", nil},
+		{"ShorterDst", []byte("short")},
+		{"LongerDst", bytes.Repeat([]byte("long"), 100)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			dst := filepath.Join(dir, "dst-"+test.name)
+			if test.existing != nil {
+				if err := WriteFile(dst, test.existing, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := CopyFile(dst, src); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadFile(dst)
 			if err != nil {
-				return err
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("dst contents = %q, want %q", got, data)
 			}
-			if !bytes.Equal(targetData, newData) {
-				return errors.New("file " + path + " contents differ from target")
+			if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+				return
 			}
+			fi, err := Stat(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fi.Mode().Perm(); got != 0o640 {
+				t.Errorf("dst mode = %v, want %v", got, FileMode(0o640))
+			}
+		})
+	}
+}
+
+func TestCopyFileSparse(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	f, err := Create(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const size = 1 << 20
+	if _, err := f.WriteAt([]byte("head"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt([]byte("tail"), size-4); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "dst")
+	if err := CopyFile(dst, src); err != nil {
+		t.Fatal(err)
+	}
+	want, err := ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != size || !bytes.Equal(got, want) {
+		t.Fatalf("dst has %d bytes and differs from src", len(got))
+	}
+
+	if runtime.GOOS != "linux" {
+		return
+	}
+	// If the file system gave src a hole, the copy must keep it.
+	const seekHole = 4
+	if hole := firstHole(t, src, seekHole); hole >= size {
+		t.Skipf("file system did not leave a hole in %s", src)
+	}
+	if hole := firstHole(t, dst, seekHole); hole >= size {
+		t.Errorf("dst has no hole; first hole at %d, want < %d", hole, size)
+	}
+}
+
+// firstHole returns the offset of the first hole in the named file,
+// which is its size if it has none.
+func firstHole(t *testing.T, name string, seekHole int) int64 {
+	t.Helper()
+	f, err := Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	off, err := f.Seek(0, seekHole)
+	if err != nil {
+		t.Skipf("SEEK_HOLE: %v", err)
+	}
+	return off
+}
+
+func TestCopyFileSetuid(t *testing.T) {
+	switch runtime.GOOS {
+	case "windows", "plan9", "js", "wasip1":
+		t.Skipf("no setuid bits on %s", runtime.GOOS)
+	}
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	if err := WriteFile(src, []byte("data"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Chmod(src, 0o755|ModeSetuid|ModeSetgid); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := Stat(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode()&(ModeSetuid|ModeSetgid) == 0 {
+		t.Skip("file system dropped the setuid and setgid bits")
+	}
+
+	dst := filepath.Join(dir, "dst")
+	if err := CopyFile(dst, src); err != nil {
+		t.Fatal(err)
+	}
+	fi, err = Stat(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fi.Mode(), FileMode(0o755); got != want {
+		t.Errorf("dst mode is %v, want %v", got, want)
+	}
+}
+
+func TestCopyFileProgress(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, size := range []int{0, 100, CopyProgressChunk, 2*CopyProgressChunk + 12345} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			src := filepath.Join(dir, fmt.Sprintf("src-%d", size))
+			data := make([]byte, size)
+			for i := range data {
+				data[i] = byte(i % 251)
+			}
+			if err := WriteFile(src, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			var calls []int64
+			dst := filepath.Join(dir, fmt.Sprintf("dst-%d", size))
+			err := CopyFileProgress(dst, src, func(copied, total int64) {
+				if total != int64(size) {
+					t.Errorf("progress total = %d, want %d", total, size)
+				}
+				if len(calls) > 0 && copied <= calls[len(calls)-1] {
+					t.Errorf("progress copied = %d after %d, want it to increase", copied, calls[len(calls)-1])
+				}
+				calls = append(calls, copied)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(calls) == 0 {
+				t.Fatal("progress was never called")
+			}
+			if last := calls[len(calls)-1]; last != int64(size) {
+				t.Errorf("last progress copied = %d, want %d", last, size)
+			}
+			// On macOS and Linux, the copy may be a single clone.
+			cloned := len(calls) == 1 && (runtime.GOOS == "darwin" || runtime.GOOS == "linux")
+			if want := max(1, (size+CopyProgressChunk-1)/CopyProgressChunk); !cloned && len(calls) != want {
+				t.Errorf("progress called %d times, want once per chunk, %d times", len(calls), want)
+			}
+			if got, err := ReadFile(dst); err != nil {
+				t.Fatal(err)
+			} else if !bytes.Equal(got, data) {
+				t.Error("dst contents differ from src")
+			}
+
+			// A nil progress function is allowed.
+			if err := CopyFileProgress(dst+"-nil", src, nil); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestCopyFileErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	if err := WriteFile(src, []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	missing := filepath.Join(dir, "missing")
+	err := CopyFile(filepath.Join(dir, "dst"), missing)
+	var pe *PathError
+	if !errors.As(err, &pe) || pe.Path != missing || !IsNotExist(err) {
+		t.Errorf("CopyFile from missing file: got %v, want *PathError for %s", err, missing)
+	}
+
+	baddst := filepath.Join(dir, "nodir", "dst")
+	err = CopyFile(baddst, src)
+	if !errors.As(err, &pe) || pe.Path != baddst {
+		t.Errorf("CopyFile to missing directory: got %v, want *PathError for %s", err, baddst)
+	}
+
+	if err := CopyFile(filepath.Join(dir, "dst"), dir); err == nil {
+		t.Errorf("CopyFile from a directory succeeded")
+	}
+
+	if err := CopyFile(src, src); err == nil {
+		t.Errorf("CopyFile onto itself succeeded")
+	}
+	if got, err := ReadFile(src); err != nil || string(got) != "data" {
+		t.Errorf("after CopyFile onto itself, src = %q, %v; want %q", got, err, "data")
+	}
+}
+
+func TestFileLock(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "lock")
+	f1, err := Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f1.Close()
+	f2, err := Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f2.Close()
+
+	if err := f1.Lock(); err != nil {
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skipf("file locking not supported: %v", err)
 		}
+		t.Fatal(err)
+	}
+	if ok, err := f2.TryLock(); ok || err != nil {
+		t.Fatalf("TryLock while locked through another File = %v, %v; want false, nil", ok, err)
+	}
 
-		return nil
-	}); err != nil {
-		t.Fatal("comparing two directories:", err)
+	locked := make(chan error, 1)
+	go func() {
+		locked <- f2.Lock()
+	}()
+	select {
+	case err := <-locked:
+		t.Fatalf("Lock returned %v while lock was held through another File", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	if err := f1.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-locked; err != nil {
+		t.Fatal(err)
+	}
+	if err := f2.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := f1.TryLock(); !ok || err != nil {
+		t.Fatalf("TryLock on unlocked file = %v, %v; want true, nil", ok, err)
+	}
+	// Closing the File releases its lock.
+	if err := f1.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := f2.TryLock(); !ok || err != nil {
+		t.Fatalf("TryLock after Close of lock holder = %v, %v; want true, nil", ok, err)
+	}
+}
+
+func TestFadvise(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "fadvise")
+	data := bytes.Repeat([]byte("fadvise!"), 1<<17)
+	if err := WriteFile(name, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if err := f.Fadvise(0, 0, FadviceSequential); err != nil {
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skipf("Fadvise not supported: %v", err)
+		}
+		t.Fatal(err)
+	}
+	if _, err := io.Copy(io.Discard, f); err != nil {
+		t.Fatal(err)
+	}
+	err = f.Fadvise(0, int64(len(data)), FadviceDontNeed)
+	if err != nil && !(runtime.GOOS == "darwin" && errors.Is(err, errors.ErrUnsupported)) {
+		t.Errorf("Fadvise(FadviceDontNeed) after read: %v", err)
+	}
+
+	var pe *PathError
+	if err := f.Fadvise(0, 0, FadviceDontNeed+1); !errors.As(err, &pe) || pe.Op != "fadvise" {
+		t.Errorf("Fadvise with invalid advice: got %v, want fadvise PathError", err)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	t.Parallel()
+
+	f, err := Create(filepath.Join(t.TempDir(), "allocate"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString("start"); err != nil {
+		t.Fatal(err)
+	}
+
+	const size = 1 << 20
+	if err := f.Allocate(0, size); err != nil {
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skipf("Allocate not supported: %v", err)
+		}
+		t.Fatal(err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Size() != size {
+		t.Errorf("size after Allocate past the end = %d; want %d", fi.Size(), size)
+	}
+
+	// Allocating within the file leaves its size alone.
+	if err := f.Allocate(10, 100); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := f.Stat(); err != nil || fi.Size() != size {
+		t.Errorf("size after Allocate within the file = %d, %v; want %d", fi.Size(), err, size)
+	}
+
+	// Writes into the allocated range succeed and keep the data
+	// written before.
+	if _, err := f.WriteAt([]byte("end"), size-3); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != size || string(got[:5]) != "start" || string(got[size-3:]) != "end" {
+		t.Errorf("file has %d bytes, starting %q and ending %q", len(got), got[:5], got[len(got)-3:])
+	}
+
+	// No file system can reserve this much space; the failure
+	// should be reported as a PathError.
+	var pe *PathError
+	if err := f.Allocate(0, 1<<62); !errors.As(err, &pe) || pe.Op != "allocate" {
+		t.Errorf("Allocate of 4 EiB: got %v, want allocate PathError", err)
+	}
+	if err := f.Allocate(-1, 1); !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("Allocate at negative offset: got %v, want EINVAL", err)
+	}
+}
+
+func TestSyncRange(t *testing.T) {
+	t.Parallel()
+
+	f, err := Create(filepath.Join(t.TempDir(), "syncrange"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	data := bytes.Repeat([]byte("sync"), 1<<14)
+	if _, err := f.Write(data); err != nil {
+		t.Fatal(err)
+	}
+
+	err = f.SyncRange(0, int64(len(data)), SyncRangeWaitBefore|SyncRangeWrite|SyncRangeWaitAfter)
+	if runtime.GOOS != "linux" {
+		if !errors.Is(err, errors.ErrUnsupported) {
+			t.Errorf("SyncRange = %v; want ErrUnsupported", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pe *PathError
+	if err := f.SyncRange(-1, 0, SyncRangeWrite); !errors.As(err, &pe) || pe.Op != "sync_file_range" || pe.Err != syscall.EINVAL {
+		t.Errorf("SyncRange at negative offset: got %v, want sync_file_range EINVAL PathError", err)
+	}
+}
+
+func TestDatasync(t *testing.T) {
+	t.Parallel()
+
+	f, err := Create(filepath.Join(t.TempDir(), "datasync"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString("hello, datasync"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Datasync(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Datasync(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Datasync after Close: got %v, want ErrClosed", err)
+	}
+}
+
+func BenchmarkSyncAppend(b *testing.B) {
+	b.Run("Sync", func(b *testing.B) { benchmarkSyncAppend(b, (*File).Sync) })
+	b.Run("Datasync", func(b *testing.B) { benchmarkSyncAppend(b, (*File).Datasync) })
+}
+
+func benchmarkSyncAppend(b *testing.B, sync func(*File) error) {
+	f, err := OpenFile(filepath.Join(b.TempDir(), "append"), O_WRONLY|O_CREATE|O_APPEND, 0o644)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer f.Close()
+	record := []byte("a small log record\n")
+	b.SetBytes(int64(len(record)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := f.Write(record); err != nil {
+			b.Fatal(err)
+		}
+		if err := sync(f); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestDiskUsage(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := WriteFile(file, []byte("data"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{dir, file} {
+		total, free, avail, err := DiskUsage(name)
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skipf("DiskUsage: %v", err)
+		}
+		if err != nil {
+			t.Fatalf("DiskUsage(%q): %v", name, err)
+		}
+		t.Logf("DiskUsage(%q) = %d, %d, %d", name, total, free, avail)
+		if total == 0 || free == 0 || avail == 0 {
+			t.Errorf("DiskUsage(%q) = %d, %d, %d; want non-zero values", name, total, free, avail)
+		}
+		if free > total || avail > total {
+			t.Errorf("DiskUsage(%q) = %d, %d, %d; want free and avail no larger than total", name, total, free, avail)
+		}
+	}
+
+	_, _, _, err := DiskUsage(filepath.Join(dir, "missing"))
+	var pe *PathError
+	if !errors.As(err, &pe) || pe.Op != "diskusage" || !IsNotExist(err) {
+		t.Errorf("DiskUsage of missing file = %v, want diskusage PathError wrapping ErrNotExist", err)
+	}
+}
+
+func TestFileStatfs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	f, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	total, free, avail, blockSize, err := f.Statfs()
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skipf("Statfs: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("Statfs() = %d, %d, %d, %d", total, free, avail, blockSize)
+	if blockSize <= 0 || total%uint64(blockSize) != 0 {
+		t.Errorf("Statfs() returned block size %d; want a positive divisor of the total size %d", blockSize, total)
+	}
+
+	dtotal, dfree, davail, err := DiskUsage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Other tests may use or release space in between.
+	near := func(a, b uint64) bool {
+		return max(a, b)-min(a, b) <= dtotal/100
+	}
+	if total != dtotal || !near(free, dfree) || !near(avail, davail) {
+		t.Errorf("Statfs() = %d, %d, %d; DiskUsage(%q) = %d, %d, %d", total, free, avail, dir, dtotal, dfree, davail)
+	}
+
+	f.Close()
+	if _, _, _, _, err := f.Statfs(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Statfs of a closed file: got %v, want ErrClosed", err)
+	}
+}
+
+func TestFilesystemType(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	typ, err := FilesystemType(dir)
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skipf("FilesystemType: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("FilesystemType(%q): %v", dir, err)
+	}
+	t.Logf("FilesystemType(%q) = %q", dir, typ)
+	if typ == "" {
+		t.Errorf("FilesystemType(%q) = %q, want a non-empty type", dir, typ)
+	}
+
+	_, err = FilesystemType(filepath.Join(dir, "missing"))
+	var pe *PathError
+	if !errors.As(err, &pe) || pe.Op != "fstype" || !IsNotExist(err) {
+		t.Errorf("FilesystemType of missing file = %v, want fstype PathError wrapping ErrNotExist", err)
+	}
+}
+
+type errWriter struct{ err error }
+
+func (w errWriter) Write(b []byte) (int, error) { return 0, w.err }
+
+func TestTeeFile(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "tee")
+	f, err := Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := TeeFile(f); w != io.WriteCloser(f) {
+		t.Errorf("TeeFile with no extra writers = %T, want the *File", w)
+	}
+
+	var b1, b2 bytes.Buffer
+	w := TeeFile(f, &b1, &b2)
+	if _, err := w.Write([]byte("hello, ")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(w, "world"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	const want = "hello, world"
+	if got, err := ReadFile(name); err != nil || string(got) != want {
+		t.Errorf("file contents = %q, %v; want %q, nil", got, err, want)
+	}
+	if b1.String() != want || b2.String() != want {
+		t.Errorf("extra writers got %q and %q, want %q", b1.String(), b2.String(), want)
+	}
+	if _, err := f.Write([]byte("x")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write after closing the tee = %v, want ErrClosed", err)
+	}
+
+	// An error from an extra writer is returned, and later
+	// writers are not written to.
+	f, err = Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	errSink := errors.New("sink failed")
+	var after bytes.Buffer
+	w = TeeFile(f, errWriter{errSink}, &after)
+	if _, err := w.Write([]byte("data")); err != errSink {
+		t.Errorf("Write with a failing extra writer = %v, want %v", err, errSink)
+	}
+	if _, err := io.WriteString(w, "data"); err != errSink {
+		t.Errorf("WriteString with a failing extra writer = %v, want %v", err, errSink)
+	}
+	if after.Len() != 0 {
+		t.Errorf("writer after the failing one got %q, want nothing", after.String())
+	}
+
+	// An error from f is returned.
+	f.Close()
+	if _, err := w.Write([]byte("data")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write to closed file through the tee = %v, want ErrClosed", err)
 	}
 }
@@ -7,11 +7,17 @@
 package os_test
 
 import (
+	"errors"
+	"fmt"
+	"internal/syscall/unix"
 	"internal/testenv"
 	"io"
+	"io/fs"
 	. "os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 	"syscall"
 	"testing"
@@ -228,6 +234,202 @@ func TestReaddirRemoveRace(t *testing.T) {
 	}
 }
 
+func TestUmask(t *testing.T) {
+	if runtime.GOOS == "wasip1" || runtime.GOOS == "js" {
+		t.Skip("umask not supported on " + runtime.GOOS)
+	}
+	dir := t.TempDir()
+
+	old := Umask(0o027)
+	defer Umask(old)
+	if got := Umask(0o027); got != 0o027 {
+		t.Errorf("Umask returned %#o, want %#o", got, 0o027)
+	}
+	if got := Umask(0o7777); got != 0o027 {
+		t.Errorf("Umask returned %#o, want %#o", got, 0o027)
+	}
+	if got := Umask(0o027); got != 0o777 {
+		t.Errorf("Umask did not limit mask to permission bits: got %#o, want %#o", got, 0o777)
+	}
+
+	name := filepath.Join(dir, "file")
+	f, err := OpenFile(name, O_CREATE|O_WRONLY, 0o666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	fi, err := Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fi.Mode().Perm(), FileMode(0o640); got != want {
+		t.Errorf("file created with mode 0666 under umask 027 has mode %v, want %v", got, want)
+	}
+
+	name = filepath.Join(dir, "dir")
+	if err := Mkdir(name, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	fi, err = Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fi.Mode().Perm(), FileMode(0o750); got != want {
+		t.Errorf("directory created with mode 0777 under umask 027 has mode %v, want %v", got, want)
+	}
+}
+
+func TestReadlinkNonUTF8(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	t.Parallel()
+
+	target := "\xff\xfe/not\x80utf8"
+	link := filepath.Join(t.TempDir(), "link")
+	if err := Symlink(target, link); err != nil {
+		if errors.Is(err, syscall.EILSEQ) {
+			t.Skipf("file system rejects non-UTF-8 link targets: %v", err)
+		}
+		t.Fatal(err)
+	}
+	got, err := Readlink(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != target {
+		t.Errorf("Readlink(%q) = %q, want %q", link, got, target)
+	}
+}
+
+func TestTempMode(t *testing.T) {
+	if runtime.GOOS == "wasip1" || runtime.GOOS == "js" {
+		t.Skip("umask not supported on " + runtime.GOOS)
+	}
+	dir := t.TempDir()
+
+	old := Umask(0o027)
+	defer Umask(old)
+
+	f, err := CreateTempMode(dir, "file", 0o664)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	fi, err := Stat(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fi.Mode().Perm(), FileMode(0o640); got != want {
+		t.Errorf("CreateTempMode with mode 0664 under umask 027 created mode %v, want %v", got, want)
+	}
+
+	name, err := MkdirTempMode(dir, "dir", 0o775)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err = Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fi.Mode().Perm(), FileMode(0o750); got != want {
+		t.Errorf("MkdirTempMode with mode 0775 under umask 027 created mode %v, want %v", got, want)
+	}
+}
+
+func TestMkdirAllMode(t *testing.T) {
+	if runtime.GOOS == "wasip1" || runtime.GOOS == "js" {
+		t.Skip("umask not supported on " + runtime.GOOS)
+	}
+	dir := t.TempDir()
+
+	old := Umask(0o022)
+	defer Umask(old)
+
+	// The existing directory keeps its mode.
+	if err := Chmod(dir, 0o711); err != nil {
+		t.Fatal(err)
+	}
+	leaf := filepath.Join(dir, "a", "b", "c")
+	if err := MkdirAllMode(leaf, 0o777, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		path string
+		want FileMode
+	}{
+		{dir, 0o711},
+		{filepath.Join(dir, "a"), 0o755},
+		{filepath.Join(dir, "a", "b"), 0o755},
+		{leaf, 0o700},
+	} {
+		fi, err := Stat(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fi.Mode().Perm(); got != tt.want {
+			t.Errorf("%s has mode %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	// An existing leaf is left alone.
+	if err := MkdirAllMode(leaf, 0o777, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := Stat(leaf); err != nil {
+		t.Fatal(err)
+	} else if got := fi.Mode().Perm(); got != 0o700 {
+		t.Errorf("existing %s has mode %v after MkdirAllMode, want %v", leaf, got, FileMode(0o700))
+	}
+}
+
+func TestCreateMode(t *testing.T) {
+	if runtime.GOOS == "wasip1" || runtime.GOOS == "js" {
+		t.Skip("umask not supported on " + runtime.GOOS)
+	}
+	old := Umask(0o022)
+	defer Umask(old)
+
+	dir := t.TempDir()
+	for _, tt := range []struct {
+		perm, want FileMode
+	}{
+		{0o600, 0o600},
+		{0o640, 0o640},
+		{0o666, 0o644},
+		{0o777, 0o755},
+	} {
+		name := filepath.Join(dir, fmt.Sprintf("file%o", tt.perm))
+		f, err := CreateMode(name, tt.perm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.WriteString("hello"); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+		fi, err := Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fi.Mode(); got != tt.want {
+			t.Errorf("file created with CreateMode %v under umask 022 has mode %v, want %v", tt.perm, got, tt.want)
+		}
+
+		// An existing file is truncated, but keeps its mode.
+		f, err = CreateMode(name, 0o666)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+		fi, err = Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Size() != 0 || fi.Mode() != tt.want {
+			t.Errorf("after CreateMode of existing file: size %d, mode %v; want 0, %v", fi.Size(), fi.Mode(), tt.want)
+		}
+	}
+}
+
 // Issue 23120: respect umask when doing Mkdir with the sticky bit
 func TestMkdirStickyUmask(t *testing.T) {
 	if runtime.GOOS == "wasip1" {
@@ -270,6 +472,160 @@ func TestMkdirStickyUmask(t *testing.T) {
 	}
 }
 
+func TestMkdirStickyUnreadable(t *testing.T) {
+	if runtime.GOOS == "wasip1" {
+		t.Skip("file permissions not supported on " + runtime.GOOS)
+	}
+	t.Parallel()
+
+	// Adding the sticky bit must not need read access to the
+	// new directory, which 0o333 does not grant to its owner.
+	p := filepath.Join(t.TempDir(), "dir")
+	if err := Mkdir(p, ModeSticky|0o333); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := Lstat(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode()&ModeSticky == 0 {
+		t.Errorf("Mkdir(_, ModeSticky|0333) created dir with mode %v; want ModeSticky set", fi.Mode())
+	}
+}
+
+func TestCreateSetgid(t *testing.T) {
+	if runtime.GOOS == "wasip1" {
+		t.Skip("file permissions not supported on " + runtime.GOOS)
+	}
+	t.Parallel()
+
+	// The new files belong to the group of dir, which we are a member of
+	// unless dir has the setgid bit; in that case the test shows nothing.
+	dir := t.TempDir()
+	if fi, err := Stat(dir); err != nil {
+		t.Fatal(err)
+	} else if fi.Mode()&ModeSetgid != 0 {
+		t.Skip("skipping: temporary directory is setgid")
+	}
+
+	p := filepath.Join(dir, "dir")
+	if err := Mkdir(p, ModeSetgid|0o755); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := Stat(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode()&ModeSetgid == 0 {
+		t.Errorf("Mkdir(_, ModeSetgid|0755) created dir with mode %v; want ModeSetgid set", fi.Mode())
+	}
+
+	// Not every system lets an unprivileged user set the setgid bit of
+	// a regular file; find out with Chmod first.
+	p = filepath.Join(dir, "probe")
+	if err := WriteFile(p, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	fileSetgid := Chmod(p, ModeSetgid|0o755) == nil
+	if fileSetgid {
+		if fi, err := Stat(p); err != nil {
+			t.Fatal(err)
+		} else {
+			fileSetgid = fi.Mode()&ModeSetgid != 0
+		}
+	}
+
+	p = filepath.Join(dir, "file")
+	f, err := OpenFile(p, O_RDWR|O_CREATE|O_EXCL, ModeSetgid|ModeSticky|0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	fi, err = f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode()&(ModeSetgid|ModeSticky) != ModeSetgid|ModeSticky {
+		// Some systems do not permit the sticky bit on files.
+		t.Logf("OpenFile(_, _, ModeSetgid|ModeSticky|0755) created file with mode %v", fi.Mode())
+		if fileSetgid && fi.Mode()&ModeSetgid == 0 {
+			t.Errorf("OpenFile(_, _, ModeSetgid|ModeSticky|0755) created file with mode %v; want ModeSetgid set", fi.Mode())
+		}
+	}
+}
+
+func TestStdinPollable(t *testing.T) {
+	if Getenv("GO_WANT_HELPER_PROCESS") == "1" {
+		f, err := StdinPollable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// On Linux, f has a non-blocking mode of its own.
+		if runtime.GOOS == "linux" {
+			if flags, err := unix.Fcntl(0, syscall.F_GETFL, 0); err == nil && unix.HasNonblockFlag(flags) {
+				fmt.Println("standard input is in non-blocking mode")
+				Exit(0)
+			}
+		}
+		if err := f.SetReadDeadline(time.Now().Add(10 * time.Millisecond)); err != nil {
+			fmt.Println(err, errors.Is(err, ErrNoDeadline), errors.Is(err, errors.ErrUnsupported))
+			Exit(0)
+		}
+		_, err = f.Read(make([]byte, 1))
+		fmt.Println(err)
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+		Exit(0)
+	}
+
+	testenv.MustHaveExec(t)
+	t.Parallel()
+
+	exe, err := Executable()
+	if err != nil {
+		t.Skipf("can't find executable: %v", err)
+	}
+	run := func(stdin *File) string {
+		t.Helper()
+		cmd := testenv.Command(t, exe, "-test.run=^TestStdinPollable$")
+		cmd = testenv.CleanCmdEnv(cmd)
+		cmd.Env = append(cmd.Env, "GO_WANT_HELPER_PROCESS=1")
+		cmd.Stdin = stdin
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("child process failed: %v\n%s", err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	// A pipe that nothing is written to.
+	r, w, err := Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	out := run(r)
+	r.Close()
+	noDeadline := ErrNoDeadline.Error() + " true true"
+	switch {
+	case out == noDeadline && (runtime.GOOS == "darwin" || runtime.GOOS == "ios"):
+		// The kqueue poller does not support pipes.
+	case out != ErrDeadlineExceeded.Error() && !strings.HasSuffix(out, ": "+ErrDeadlineExceeded.Error()):
+		t.Errorf("reading a pipe with a deadline: got %q, want %q", out, ErrDeadlineExceeded)
+	}
+
+	// A regular file does not support deadlines.
+	f, err := Create(filepath.Join(t.TempDir(), "stdin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if out := run(f); out != noDeadline {
+		t.Errorf("setting a deadline on a regular file: got %q, want %q", out, noDeadline)
+	}
+}
+
 // See also issues: 22939, 24331
 func newFileTest(t *testing.T, blocking bool) {
 	if runtime.GOOS == "js" || runtime.GOOS == "wasip1" {
@@ -422,3 +778,633 @@ func TestIssue60181(t *testing.T) {
 		t.Errorf("files not concatenated: got %q, want %q", got, want)
 	}
 }
+
+// makeRenameTree creates a small tree under root for RenameAcrossFS tests
+// and returns its modification time.
+func makeRenameTree(t *testing.T, root string) time.Time {
+	t.Helper()
+	if err := Mkdir(root, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(filepath.Join(root, "file"), []byte("hello"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if err := Mkdir(filepath.Join(root, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(filepath.Join(root, "sub", "exec"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Symlink("../file", filepath.Join(root, "sub", "link")); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, name := range []string{"file", "sub/exec", "sub", "."} {
+		if err := Chtimes(filepath.Join(root, name), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return mtime
+}
+
+// checkRenameTree checks that root holds the tree made by makeRenameTree.
+func checkRenameTree(t *testing.T, root string, mtime time.Time) {
+	t.Helper()
+	for _, tt := range []struct {
+		name string
+		mode FileMode
+	}{
+		{".", ModeDir | 0o750},
+		{"file", 0o640},
+		{"sub", ModeDir | 0o755},
+		{"sub/exec", 0o755},
+	} {
+		fi, err := Lstat(filepath.Join(root, tt.name))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if fi.Mode() != tt.mode {
+			t.Errorf("%s: mode = %v, want %v", tt.name, fi.Mode(), tt.mode)
+		}
+		if !fi.ModTime().Equal(mtime) {
+			t.Errorf("%s: mtime = %v, want %v", tt.name, fi.ModTime(), mtime)
+		}
+	}
+	if data, err := ReadFile(filepath.Join(root, "sub", "link")); err != nil || string(data) != "hello" {
+		t.Errorf("reading through sub/link = %q, %v; want %q, nil", data, err, "hello")
+	}
+}
+
+func TestRenameAcrossFSFallback(t *testing.T) {
+	if runtime.GOOS == "wasip1" || runtime.GOOS == "js" {
+		t.Skip("file modes not supported on " + runtime.GOOS)
+	}
+	old := Umask(0)
+	defer Umask(old)
+
+	// Make every rename fail as it would between file systems.
+	defer func(f func(string, string) error) { *RenameAcrossFSRename = f }(*RenameAcrossFSRename)
+	*RenameAcrossFSRename = func(oldpath, newpath string) error {
+		return &LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EXDEV}
+	}
+
+	dir := t.TempDir()
+
+	t.Run("file", func(t *testing.T) {
+		src, dst := filepath.Join(dir, "src-file"), filepath.Join(dir, "dst-file")
+		if err := WriteFile(src, []byte("data"), 0o604); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteFile(dst, []byte("old contents"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := RenameAcrossFS(src, dst); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Lstat(src); !IsNotExist(err) {
+			t.Errorf("source still present after RenameAcrossFS: %v", err)
+		}
+		if data, err := ReadFile(dst); err != nil || string(data) != "data" {
+			t.Errorf("ReadFile(dst) = %q, %v; want %q, nil", data, err, "data")
+		}
+		if fi, err := Stat(dst); err != nil || fi.Mode() != 0o604 {
+			t.Errorf("Stat(dst) mode = %v, %v; want %v", fi.Mode(), err, FileMode(0o604))
+		}
+	})
+
+	t.Run("dir", func(t *testing.T) {
+		src, dst := filepath.Join(dir, "src-dir"), filepath.Join(dir, "dst-dir")
+		mtime := makeRenameTree(t, src)
+		if err := RenameAcrossFS(src, dst); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Lstat(src); !IsNotExist(err) {
+			t.Errorf("source still present after RenameAcrossFS: %v", err)
+		}
+		checkRenameTree(t, dst, mtime)
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		src, dst := filepath.Join(dir, "src-partial"), filepath.Join(dir, "dst-partial")
+		mtime := makeRenameTree(t, src)
+		// A named pipe cannot be copied, so the copy fails part way.
+		if err := Mkfifo(filepath.Join(src, "sub", "zz-fifo"), 0o600); err != nil {
+			t.Skipf("cannot create named pipe: %v", err)
+		}
+		if err := Chtimes(filepath.Join(src, "sub"), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		if err := RenameAcrossFS(src, dst); err == nil {
+			t.Fatal("RenameAcrossFS succeeded copying a named pipe")
+		}
+		checkRenameTree(t, src, mtime)
+		if _, err := Lstat(dst); !IsNotExist(err) {
+			t.Errorf("partial copy left behind after failed RenameAcrossFS: %v", err)
+		}
+
+		// An existing newpath survives the failed copy.
+		if err := Symlink("elsewhere", dst); err != nil {
+			t.Fatal(err)
+		}
+		if err := RenameAcrossFS(src, dst); err == nil {
+			t.Fatal("RenameAcrossFS succeeded copying a named pipe")
+		}
+		if target, err := Readlink(dst); err != nil || target != "elsewhere" {
+			t.Errorf("Readlink(dst) after failed RenameAcrossFS = %q, %v; want %q, nil", target, err, "elsewhere")
+		}
+	})
+
+	t.Run("symlink-newpath", func(t *testing.T) {
+		src, dst := filepath.Join(dir, "src-over-link"), filepath.Join(dir, "dst-link")
+		victim := filepath.Join(dir, "victim")
+		if err := WriteFile(src, []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteFile(victim, []byte("victim"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := Symlink(victim, dst); err != nil {
+			t.Fatal(err)
+		}
+		if err := RenameAcrossFS(src, dst); err != nil {
+			t.Fatal(err)
+		}
+		if fi, err := Lstat(dst); err != nil || !fi.Mode().IsRegular() {
+			t.Errorf("Lstat(dst) = %v, %v; want a regular file replacing the link", fi, err)
+		}
+		if data, err := ReadFile(victim); err != nil || string(data) != "victim" {
+			t.Errorf("target of the replaced link = %q, %v; want %q, nil", data, err, "victim")
+		}
+	})
+
+	entries, err := ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".rename") {
+			t.Errorf("temporary %s left behind by RenameAcrossFS", e.Name())
+		}
+	}
+}
+
+func TestWalkDirRenamed(t *testing.T) {
+	if runtime.GOOS == "wasip1" || runtime.GOOS == "js" {
+		t.Skip("openat not available on " + runtime.GOOS)
+	}
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"a/b/c", "d"} {
+		if err := MkdirAll(filepath.Join(dir, name), 0o777); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := WriteFile(filepath.Join(dir, "a", "b", "c", "file"), nil, 0o666); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rename a out from under the walk once it is being read.
+	var got []string
+	err := WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		got = append(got, filepath.ToSlash(rel))
+		if rel == filepath.Join("a", "b") {
+			if err := Rename(filepath.Join(dir, "a"), filepath.Join(dir, "moved")); err != nil {
+				t.Fatal(err)
+			}
+			// Replace it with a link, which must not be followed.
+			if err := Symlink("d", filepath.Join(dir, "a")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDir: %v", err)
+	}
+	want := []string{".", "a", "a/b", "a/b/c", "a/b/c/file", "d"}
+	if !slices.Equal(got, want) {
+		t.Errorf("WalkDir visited %q, want %q", got, want)
+	}
+}
+
+func TestPipe2Cloexec(t *testing.T) {
+	if Getenv("GO_WANT_HELPER_PROCESS") == "1" {
+		// Report whether the descriptor named in the environment is
+		// still the pipe the parent created.
+		fd, _ := strconv.Atoi(Getenv("GO_OS_TEST_PIPE_FD"))
+		var st syscall.Stat_t
+		if err := syscall.Fstat(fd, &st); err == nil && strconv.FormatUint(uint64(st.Ino), 10) == Getenv("GO_OS_TEST_PIPE_INO") {
+			fmt.Print("open")
+		} else {
+			fmt.Print("closed")
+		}
+		Exit(0)
+	}
+
+	testenv.MustHaveExec(t)
+
+	for _, tt := range []struct {
+		flags int
+		want  string
+	}{
+		{PipeNonblock, "open"},
+		{0, "open"},
+		{PipeNonblock | PipeCloexec, "closed"},
+		{PipeCloexec, "closed"},
+	} {
+		r, w, err := Pipe2(tt.flags)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fi, err := w.Stat()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ino := fi.Sys().(*syscall.Stat_t).Ino
+
+		cmd := testenv.Command(t, Args[0], "-test.run=^TestPipe2Cloexec$")
+		cmd.Env = append(Environ(),
+			"GO_WANT_HELPER_PROCESS=1",
+			fmt.Sprintf("GO_OS_TEST_PIPE_FD=%d", w.Fd()),
+			fmt.Sprintf("GO_OS_TEST_PIPE_INO=%d", ino))
+		out, err := cmd.Output()
+		r.Close()
+		w.Close()
+		if err != nil {
+			t.Fatalf("Pipe2(%#x): helper: %v", tt.flags, err)
+		}
+		if got := string(out); got != tt.want {
+			t.Errorf("Pipe2(%#x): pipe %s in child, want %s", tt.flags, got, tt.want)
+		}
+	}
+}
+
+func TestLookupHostsFQDN(t *testing.T) {
+	const hosts = `# The following lines are desirable for IPv4 capable hosts
+127.0.0.1	localhost.localdomain localhost myhost
+127.0.1.1	MyHost.Example.COM	myhost  # comment
+::1	ip6-localhost ip6-loopback
+
+192.0.2.1 other.example.org other
+192.0.2.2 notfull.example.org notfull
+192.0.2.2 notfull
+192.0.2.3 prefix.example.org prefixed
+#192.0.2.4 commented.example.org commented
+` + "192.0.2.5 crlf.example.org crlf\r\n"
+	for _, tt := range []struct {
+		name, want string
+	}{
+		{"myhost", "MyHost.Example.COM"},
+		{"MYHOST", "MyHost.Example.COM"},
+		{"other", "other.example.org"},
+		{"crlf", "crlf.example.org"},
+		{"notfull", "notfull.example.org"},
+		{"prefixed", ""},
+		{"prefix", ""},
+		{"commented", ""},
+		{"localhost", "localhost.localdomain"},
+		{"missing", ""},
+	} {
+		if got := LookupHostsFQDN(tt.name, []byte(hosts)); got != tt.want {
+			t.Errorf("LookupHostsFQDN(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetgroupsComplete(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("test uses /proc/self/status")
+	}
+	status, err := ReadFile("/proc/self/status")
+	if err != nil {
+		t.Skip(err)
+	}
+	var want []int
+	for _, line := range strings.Split(string(status), "\n") {
+		list, ok := strings.CutPrefix(line, "Groups:")
+		if !ok {
+			continue
+		}
+		for _, f := range strings.Fields(list) {
+			gid, err := strconv.Atoi(f)
+			if err != nil {
+				t.Fatalf("bad Groups line %q", line)
+			}
+			want = append(want, gid)
+		}
+	}
+	if len(want) < 2 {
+		t.Skipf("process has %d supplementary groups", len(want))
+	}
+
+	got, err := Getgroups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("Getgroups() = %v, want %v", got, want)
+	}
+}
+
+func TestGetgroupsNames(t *testing.T) {
+	gids, err := Getgroups()
+	if err != nil {
+		t.Skipf("Getgroups: %v", err)
+	}
+	names, err := GetgroupsNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != len(gids) {
+		t.Fatalf("GetgroupsNames returned %d names for %d groups", len(names), len(gids))
+	}
+	for i, name := range names {
+		if name == "" {
+			t.Errorf("GetgroupsNames returned an empty name for group %d", gids[i])
+		}
+	}
+}
+
+func TestLookupGroupNames(t *testing.T) {
+	const group = `# comment
+root:x:0:
++nis:x:1:
+-nis:x:2:
+wheel:x:10:root,admin
+bad:x:notanumber:
+users:*:100:
+:x:42:
+again:x:10:
+`
+	gids := []int{100, 0, 10, 1, 7, 42}
+	want := []string{"users", "root", "wheel", "1", "7", "42"}
+	got := LookupGroupNames(gids, []byte(group))
+	if !slices.Equal(got, want) {
+		t.Errorf("LookupGroupNames(%v) = %q, want %q", gids, got, want)
+	}
+
+	got = LookupGroupNames([]int{0}, nil)
+	if want := []string{"0"}; !slices.Equal(got, want) {
+		t.Errorf("LookupGroupNames with no data = %q, want %q", got, want)
+	}
+}
+
+func TestChownRecursive(t *testing.T) {
+	if Getuid() != 0 {
+		t.Skip("skipping: changing the owner to an arbitrary user requires CAP_CHOWN")
+	}
+	testenv.MustHaveSymlink(t)
+	t.Parallel()
+
+	// The link "outside" points out of the tree, and its target
+	// must keep its owner.
+	top := t.TempDir()
+	outside := filepath.Join(top, "outside")
+	if err := WriteFile(outside, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	root := filepath.Join(top, "root")
+	for _, dir := range []string{"", "a", "a/b"} {
+		if err := Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{"f", "a/f", "a/b/f"} {
+		if err := WriteFile(filepath.Join(root, file), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Symlink(outside, filepath.Join(root, "a", "outside")); err != nil {
+		t.Fatal(err)
+	}
+	if err := Symlink("missing", filepath.Join(root, "dangling")); err != nil {
+		t.Fatal(err)
+	}
+
+	owner := func(name string) (uid, gid uint32) {
+		t.Helper()
+		fi, err := Lstat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		st := fi.Sys().(*syscall.Stat_t)
+		return st.Uid, st.Gid
+	}
+	outsideUID, outsideGID := owner(outside)
+
+	const newUID, newGID = 65534, 65533
+	if err := ChownRecursive(root, newUID, newGID); err != nil {
+		t.Fatal(err)
+	}
+	names := []string{"", "a", "a/b", "f", "a/f", "a/b/f", "a/outside", "dangling"}
+	for _, name := range names {
+		if uid, gid := owner(filepath.Join(root, name)); uid != newUID || gid != newGID {
+			t.Errorf("%q owned by %d:%d, want %d:%d", name, uid, gid, newUID, newGID)
+		}
+	}
+	if uid, gid := owner(outside); uid != outsideUID || gid != outsideGID {
+		t.Errorf("target of link out of the tree owned by %d:%d, want %d:%d", uid, gid, outsideUID, outsideGID)
+	}
+
+	// -1 leaves the uid unchanged.
+	if err := ChownRecursive(root, -1, newUID); err != nil {
+		t.Fatal(err)
+	}
+	if uid, gid := owner(filepath.Join(root, "a/b/f")); uid != newUID || gid != newUID {
+		t.Errorf("after changing only the gid, owned by %d:%d, want %d:%d", uid, gid, newUID, newUID)
+	}
+
+	if err := ChownRecursive(filepath.Join(top, "missing"), newUID, newGID); !IsNotExist(err) {
+		t.Errorf("ChownRecursive of a missing root: got %v, want a not-exist error", err)
+	}
+
+	// A root that is a symbolic link to a directory is not followed.
+	other := filepath.Join(top, "other")
+	if err := Mkdir(other, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	otherUID, otherGID := owner(other)
+	link := filepath.Join(top, "link")
+	if err := Symlink(other, link); err != nil {
+		t.Fatal(err)
+	}
+	if err := ChownRecursive(link, newUID, newGID); err != nil {
+		t.Fatal(err)
+	}
+	if uid, gid := owner(link); uid != newUID || gid != newGID {
+		t.Errorf("root link owned by %d:%d, want %d:%d", uid, gid, newUID, newGID)
+	}
+	if uid, gid := owner(other); uid != otherUID || gid != otherGID {
+		t.Errorf("target of root link owned by %d:%d, want %d:%d", uid, gid, otherUID, otherGID)
+	}
+}
+
+func TestChownAt(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	t.Parallel()
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := WriteFile(target, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := Symlink("target", link); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := Lstat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uid, gid := int(fi.Sys().(*syscall.Stat_t).Uid), int(fi.Sys().(*syscall.Stat_t).Gid)
+
+	// Pick a different owner: any owner if we are root,
+	// otherwise one of our other groups.
+	newUID, newGID := -1, -1
+	if Getuid() == 0 {
+		newUID, newGID = 65534, 65534
+	} else {
+		groups, err := Getgroups()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, g := range groups {
+			if g != gid {
+				newGID = g
+				break
+			}
+		}
+		if newGID < 0 {
+			t.Skip("skipping: not root and not in a second group")
+		}
+	}
+	wantUID := uid
+	if newUID >= 0 {
+		wantUID = newUID
+	}
+
+	d, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	if err := d.ChownAt("link", newUID, newGID, false); err != nil {
+		if testenv.SyscallIsNotSupported(err) {
+			t.Skipf("ChownAt: %v", err)
+		}
+		t.Fatalf("ChownAt(link, false): %v", err)
+	}
+	checkUidGid(t, link, wantUID, newGID)
+	checkUidGid(t, target, uid, gid)
+
+	if err := d.ChownAt("link", newUID, newGID, true); err != nil {
+		t.Fatalf("ChownAt(link, true): %v", err)
+	}
+	checkUidGid(t, target, wantUID, newGID)
+
+	err = d.ChownAt("missing", -1, -1, false)
+	if pe, ok := err.(*PathError); !ok || pe.Op != "chownat" || !IsNotExist(err) {
+		t.Errorf("ChownAt of missing file returned %v, want *PathError with Op chownat and not-exist error", err)
+	}
+}
+
+func TestChmodAt(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	t.Parallel()
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := WriteFile(target, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Symlink("target", filepath.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+	d, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	checkMode := func(want FileMode) {
+		t.Helper()
+		fi, err := Lstat(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fi.Mode().Perm(); got != want {
+			t.Errorf("mode of target = %v, want %v", got, want)
+		}
+	}
+
+	if err := d.ChmodAt("link", 0o600, true); err != nil {
+		t.Fatalf("ChmodAt(link, true): %v", err)
+	}
+	checkMode(0o600)
+
+	// Without following, a regular file is changed as usual...
+	if err := d.ChmodAt("target", 0o640, false); err != nil {
+		t.Fatalf("ChmodAt(target, false): %v", err)
+	}
+	checkMode(0o640)
+
+	// ...but a symlink's target never is.
+	if err := d.ChmodAt("link", 0o604, false); err != nil {
+		t.Logf("ChmodAt(link, false): %v", err)
+	}
+	checkMode(0o640)
+}
+
+func TestReparsePointTargetUnsupported(t *testing.T) {
+	_, _, err := ReparsePointTarget(t.TempDir())
+	if !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("ReparsePointTarget error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestFileChdirRenamed(t *testing.T) {
+	// Not parallel: this changes the working directory.
+	wd, err := Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer Chdir(wd)
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldname := filepath.Join(dir, "old")
+	newname := filepath.Join(dir, "new")
+	if err := Mkdir(oldname, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	f, err := Open(oldname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := Rename(oldname, newname); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Chdir(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != newname {
+		t.Errorf("Getwd after Chdir to a renamed directory = %q, want %q", got, newname)
+	}
+}
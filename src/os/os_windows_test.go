@@ -23,6 +23,7 @@ import (
 	"strings"
 	"syscall"
 	"testing"
+	"time"
 	"unicode/utf16"
 	"unsafe"
 )
@@ -316,6 +317,38 @@ func TestDirectoryJunction(t *testing.T) {
 	testDirLinks(t, tests)
 }
 
+func TestRootDirectoryJunction(t *testing.T) {
+	dir := t.TempDir()
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret"), nil, 0o666); err != nil {
+		t.Fatal(err)
+	}
+	var rd reparseData
+	rd.addSubstituteName(`\??\` + outside)
+	rd.addPrintName(outside)
+	if err := createMountPoint(filepath.Join(dir, "junction"), &rd); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+	for _, name := range []string{"junction", `junction\secret`} {
+		f, err := root.Open(name)
+		if err == nil {
+			f.Close()
+		}
+		if !errors.Is(err, os.ErrPathEscapes) {
+			t.Errorf("root.Open(%q) = %v, want %v", name, err, os.ErrPathEscapes)
+		}
+		if _, err := root.Stat(name); !errors.Is(err, os.ErrPathEscapes) {
+			t.Errorf("root.Stat(%q) = %v, want %v", name, err, os.ErrPathEscapes)
+		}
+	}
+}
+
 func enableCurrentThreadPrivilege(privilegeName string) error {
 	ct, err := windows.GetCurrentThread()
 	if err != nil {
@@ -1054,6 +1087,70 @@ func TestSymlinkCreation(t *testing.T) {
 	}
 }
 
+func TestSymlinkDirDangling(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	t.Parallel()
+
+	temp := t.TempDir()
+	target := filepath.Join(temp, "target")
+	link := filepath.Join(temp, "link")
+	if err := os.SymlinkDir("target", link); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Lstat(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode()&fs.ModeSymlink == 0 {
+		t.Fatalf("Lstat(%q).Mode() = %v, want symlink", link, fi.Mode())
+	}
+	if _, err := os.Stat(link); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat of dangling symlink: got %v, want ErrNotExist", err)
+	}
+
+	// Once the target is created, the link resolves to it.
+	if err := os.Mkdir(target, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(target, "file"), []byte("hello"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	fi, err = os.Stat(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.IsDir() {
+		t.Errorf("Stat(%q).Mode() = %v, want directory", link, fi.Mode())
+	}
+	entries, err := os.ReadDir(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "file" {
+		t.Errorf("ReadDir(%q) = %v, want [file]", link, entries)
+	}
+	if got, err := os.ReadFile(filepath.Join(link, "file")); err != nil || string(got) != "hello" {
+		t.Errorf("ReadFile through link = %q, %v; want %q, nil", got, err, "hello")
+	}
+}
+
+func TestSymlinkFile(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	t.Parallel()
+
+	temp := t.TempDir()
+	link := filepath.Join(temp, "link")
+	if err := os.SymlinkFile("target", link); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(temp, "target"), []byte("hello"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := os.ReadFile(link); err != nil || string(got) != "hello" {
+		t.Errorf("ReadFile(%q) = %q, %v; want %q, nil", link, got, err, "hello")
+	}
+}
+
 // isWindowsDeveloperModeActive checks whether or not the developer mode is active on Windows 10.
 // Returns false for prior Windows versions.
 // see https://docs.microsoft.com/en-us/windows/uwp/get-started/enable-your-device-for-development
@@ -1605,3 +1702,336 @@ func TestReadDirNoFileID(t *testing.T) {
 		t.Errorf("SameFile(%v, %v) = false; want true", f2, f2s)
 	}
 }
+
+func TestTransmitFile(t *testing.T) {
+	const (
+		size   = 10 * 1024 * 1024
+		offset = 12345
+	)
+	src, err := os.Create(filepath.Join(t.TempDir(), "src"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	if _, err := io.CopyN(src, newRandReader(), size); err != nil {
+		t.Fatal(err)
+	}
+	// Start part way into the file to check that the
+	// current offset is honored.
+	if _, err := src.Seek(offset, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	var called bool
+	orig := poll.TestHookDidSendFile
+	poll.TestHookDidSendFile = func(dstFD *poll.FD, src int, written int64, err error, handled bool) {
+		called = true
+	}
+	defer func() { poll.TestHookDidSendFile = orig }()
+
+	client, server := createSocketPair(t, "tcp")
+	done := make(chan error, 1)
+	go func() {
+		defer client.Close()
+		n, err := io.Copy(client, src)
+		if err == nil && n != size-offset {
+			err = fmt.Errorf("copied %d bytes, want %d", n, size-offset)
+		}
+		done <- err
+	}()
+
+	want := newRandReader()
+	if _, err := io.CopyN(io.Discard, want, offset); err != nil {
+		t.Fatal(err)
+	}
+	if err := compareReaders(server, io.LimitReader(want, size-offset)); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if !called {
+		t.Error("io.Copy from a file to a TCP connection did not use TransmitFile")
+	}
+	if got := src.CopyStats(); got.Method != "sendfile" || got.Bytes != size-offset {
+		t.Errorf("CopyStats() = %+v, want sendfile of %d bytes", got, size-offset)
+	}
+}
+
+func TestMkfifoUnsupported(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "fifo")
+	if err := os.Mkfifo(name, 0o600); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("Mkfifo = %v; want ErrUnsupported", err)
+	}
+}
+
+func TestCreateModeReadOnly(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "file")
+	f, err := os.CreateMode(name, 0o444)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	fi, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fi.Mode().Perm(), fs.FileMode(0o444); got != want {
+		t.Errorf("file created with CreateMode 0444 has mode %v, want %v", got, want)
+	}
+	if err := os.Chmod(name, 0o666); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMknodUnsupported(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "fifo")
+	if err := os.Mknod(name, fs.ModeNamedPipe|0o600, 0); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("Mknod = %v; want ErrUnsupported", err)
+	}
+}
+
+func TestChtimesFullCreationTime(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(name, nil, 0o666); err != nil {
+		t.Fatal(err)
+	}
+	btime := time.Date(1999, time.December, 31, 23, 59, 58, 0, time.UTC)
+	if err := os.ChtimesFull(name, time.Time{}, time.Time{}, btime); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := fi.Sys().(*syscall.Win32FileAttributeData)
+	if got := time.Unix(0, d.CreationTime.Nanoseconds()); !got.Equal(btime) {
+		t.Errorf("CreationTime = %v, want %v", got, btime)
+	}
+
+	// The file may be open elsewhere.
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := os.ChtimesFull(name, time.Time{}, time.Time{}, btime.Add(time.Hour)); err != nil {
+		t.Errorf("ChtimesFull on an open file: %v", err)
+	}
+}
+
+func TestGetgroupsNamesUnsupported(t *testing.T) {
+	names, err := os.GetgroupsNames()
+	if names != nil || !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("GetgroupsNames() = %v, %v; want nil, ErrUnsupported", names, err)
+	}
+}
+
+// fileOwnerGroup returns the owner and primary group of the named file.
+func fileOwnerGroup(t *testing.T, name string) (owner, group *syscall.SID) {
+	t.Helper()
+	p, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sd syscall.Handle
+	err = windows.GetNamedSecurityInfo(p, windows.SE_FILE_OBJECT,
+		windows.OWNER_SECURITY_INFORMATION|windows.GROUP_SECURITY_INFORMATION,
+		&owner, &group, nil, nil, &sd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.LocalFree(sd)
+	if owner, err = owner.Copy(); err != nil {
+		t.Fatal(err)
+	}
+	if group, err = group.Copy(); err != nil {
+		t.Fatal(err)
+	}
+	return owner, group
+}
+
+// openWithoutSIDs opens the named file while impersonating a token
+// in which sids can only be used to deny access, and which has no
+// privileges, such as one that would bypass access checks.
+func openWithoutSIDs(t *testing.T, name string, sids ...*syscall.SID) error {
+	t.Helper()
+	runtime.LockOSThread()
+	impersonating := false
+	defer func() {
+		// If reverting failed, leave the thread locked so that it
+		// exits with the goroutine instead of impersonating.
+		if !impersonating {
+			runtime.UnlockOSThread()
+		}
+	}()
+
+	proc, err := syscall.GetCurrentProcess()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var procToken syscall.Token
+	err = syscall.OpenProcessToken(proc, syscall.TOKEN_DUPLICATE|syscall.TOKEN_QUERY, &procToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer procToken.Close()
+
+	var disable *windows.SID_AND_ATTRIBUTES
+	if len(sids) > 0 {
+		attrs := make([]windows.SID_AND_ATTRIBUTES, len(sids))
+		for i, sid := range sids {
+			attrs[i].Sid = sid
+		}
+		disable = &attrs[0]
+	}
+	var restricted syscall.Token
+	err = windows.CreateRestrictedToken(procToken, windows.DISABLE_MAX_PRIVILEGE,
+		uint32(len(sids)), disable, 0, nil, 0, nil, &restricted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restricted.Close()
+	var token syscall.Token
+	err = windows.DuplicateTokenEx(restricted, syscall.TOKEN_IMPERSONATE|syscall.TOKEN_QUERY, nil,
+		windows.SecurityImpersonation, windows.TokenImpersonation, &token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer token.Close()
+
+	if err := windows.SetThreadToken(nil, token); err != nil {
+		t.Fatal(err)
+	}
+	impersonating = true
+	f, err := os.Open(name)
+	if err := windows.RevertToSelf(); err != nil {
+		t.Fatal(err)
+	}
+	impersonating = false
+	if err == nil {
+		f.Close()
+	}
+	return err
+}
+
+func TestChmodACL(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(name, []byte("hello"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	owner, group := fileOwnerGroup(t, name)
+	sameGroup := windows.EqualSid(owner, group)
+
+	for _, mode := range []fs.FileMode{0o700, 0o750, 0o640, 0o644, 0o755, 0o604, 0o444, 0o600} {
+		if err := os.ChmodACL(name, mode); err != nil {
+			t.Fatal(err)
+		}
+		fi, err := os.StatACL(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := mode
+		if sameGroup {
+			// The group bits are those of the owner.
+			want = mode&^0o070 | mode>>3&0o070
+		}
+		if got := fi.Mode(); got != want {
+			t.Errorf("StatACL after ChmodACL(%v) reports mode %v, want %v", mode, got, want)
+		}
+	}
+
+	// StatACL reports the same file as Stat.
+	fi1, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi2, err := os.StatACL(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !os.SameFile(fi1, fi2) || fi1.Size() != fi2.Size() || !fi1.ModTime().Equal(fi2.ModTime()) {
+		t.Errorf("StatACL(%q) and Stat(%q) describe different files", name, name)
+	}
+}
+
+func TestChmodACLNonOwner(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(name, []byte("hello"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	owner, group := fileOwnerGroup(t, name)
+
+	if err := os.ChmodACL(name, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := openWithoutSIDs(t, name, owner, group); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("Open of mode 0700 file by non-owner: got %v, want permission error", err)
+	}
+	if err := openWithoutSIDs(t, name); err != nil {
+		t.Errorf("Open of mode 0700 file by owner: %v", err)
+	}
+
+	if err := os.ChmodACL(name, 0o704); err != nil {
+		t.Fatal(err)
+	}
+	if err := openWithoutSIDs(t, name, owner, group); err != nil {
+		t.Errorf("Open of mode 0704 file by non-owner: %v", err)
+	}
+}
+
+func TestReparsePointTarget(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := os.Mkdir(target, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	junction := filepath.Join(dir, "junction")
+	var rd reparseData
+	rd.addSubstituteName(`\??\` + target)
+	rd.addPrintName(target)
+	if err := createMountPoint(junction, &rd); err != nil {
+		t.Fatal(err)
+	}
+	got, tag, err := os.ReparsePointTarget(junction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag != windows.IO_REPARSE_TAG_MOUNT_POINT {
+		t.Errorf("ReparsePointTarget(%q) tag = %#x, want %#x", junction, tag, uint32(windows.IO_REPARSE_TAG_MOUNT_POINT))
+	}
+	if got != target {
+		t.Errorf("ReparsePointTarget(%q) = %q, want %q", junction, got, target)
+	}
+	if got, err := os.Readlink(junction); err != nil || got != target {
+		t.Errorf("Readlink(%q) = %q, %v; want %q, nil", junction, got, err, target)
+	}
+
+	testenv.MustHaveSymlink(t)
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink("target", link); err != nil {
+		t.Fatal(err)
+	}
+	got, tag, err = os.ReparsePointTarget(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag != syscall.IO_REPARSE_TAG_SYMLINK || got != "target" {
+		t.Errorf("ReparsePointTarget(%q) = %q, %#x; want %q, %#x", link, got, tag, "target", uint32(syscall.IO_REPARSE_TAG_SYMLINK))
+	}
+
+	if _, _, err := os.ReparsePointTarget(target); err == nil {
+		t.Errorf("ReparsePointTarget(%q) succeeded on a plain directory", target)
+	} else if _, ok := err.(*fs.PathError); !ok {
+		t.Errorf("ReparsePointTarget(%q) error = %T, want *fs.PathError", target, err)
+	}
+}
@@ -5,7 +5,9 @@
 package os
 
 import (
+	"errors"
 	"internal/filepathlite"
+	"io"
 	"syscall"
 )
 
@@ -17,6 +19,15 @@ import (
 // If path is already a directory, MkdirAll does nothing
 // and returns nil.
 func MkdirAll(path string, perm FileMode) error {
+	return MkdirAllMode(path, perm, perm)
+}
+
+// MkdirAllMode is like [MkdirAll], but creates the parents of path with
+// the permission bits intermediatePerm and path itself with finalPerm,
+// both before umask. Each mode is applied as the directory is created.
+// Directories that already exist keep their mode, including path: if
+// path is already a directory, MkdirAllMode does nothing and returns nil.
+func MkdirAllMode(path string, intermediatePerm, finalPerm FileMode) error {
 	// Fast path: if we can tell whether path is a directory or file, stop with success or error.
 	dir, err := Stat(path)
 	if err == nil {
@@ -45,14 +56,14 @@ func MkdirAll(path string, perm FileMode) error {
 	// If there is a parent directory, and it is not the volume name,
 	// recurse to ensure parent directory exists.
 	if parent := path[:i]; len(parent) > len(filepathlite.VolumeName(path)) {
-		err = MkdirAll(parent, perm)
+		err = MkdirAll(parent, intermediatePerm)
 		if err != nil {
 			return err
 		}
 	}
 
 	// Parent now exists; invoke Mkdir and use its result.
-	err = Mkdir(path, perm)
+	err = Mkdir(path, finalPerm)
 	if err != nil {
 		// Handle arguments like "foo/." by
 		// double-checking that directory doesn't exist.
@@ -65,13 +76,124 @@ func MkdirAll(path string, perm FileMode) error {
 	return nil
 }
 
+// RemoveIfExists removes the named file or (empty) directory, like
+// [Remove], and reports whether it existed. If name does not exist,
+// RemoveIfExists returns false and a nil error. Otherwise it returns
+// true if name was removed, or the error from Remove, which will be of
+// type [*PathError].
+func RemoveIfExists(name string) (removed bool, err error) {
+	err = Remove(name)
+	if err == nil {
+		return true, nil
+	}
+	if IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
 // RemoveAll removes path and any children it contains.
 // It removes everything it can but returns the first error
 // it encounters. If the path does not exist, RemoveAll
 // returns nil (no error).
 // If there is an error, it will be of type [*PathError].
+//
+// RemoveAll never follows symbolic links: a link found in the tree,
+// including one to a directory, is removed itself, and nothing outside
+// the tree is touched. On Unix systems the tree is traversed with
+// openat and unlinkat, opening each directory with O_NOFOLLOW, so a
+// directory replaced by a link during the traversal is not followed
+// either. If path itself is a symbolic link, only the link is removed.
 func RemoveAll(path string) error {
-	return removeAll(path)
+	if errs := removeAll(path); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// RemoveAllErrors removes path and any children it contains, like
+// [RemoveAll], and like RemoveAll it does not follow symbolic links.
+// Rather than returning only the first error it encounters, it continues
+// past each failure and returns all of them, joined by [errors.Join].
+// Each joined error is typically a [*PathError] naming the path that
+// could not be removed.
+// If the path does not exist, or everything was removed,
+// RemoveAllErrors returns nil.
+func RemoveAllErrors(path string) error {
+	return errors.Join(removeAll(path)...)
+}
+
+// ChownRecursive changes the numeric uid and gid of root and of every
+// file in the tree below it, as [Lchown] does. A uid or gid of -1 means
+// to not change that value. Symbolic links are never followed: each link
+// in the tree, and root if it is one, has its own owner changed instead
+// of that of its target. On Unix systems each directory is opened
+// relative to its parent, as by [File.OpenAt], and its entries are
+// changed with fchownat, so the traversal stays within the tree even if
+// parts of it are replaced by symbolic links while it runs.
+//
+// Changing the owner of a file to an arbitrary user typically requires
+// privileges, such as CAP_CHOWN on Linux.
+//
+// ChownRecursive continues past each failure and returns all the errors
+// it encounters, joined by [errors.Join]. Each joined error is typically
+// a [*PathError]. On Windows and Plan 9, it fails, as Chown does.
+//
+// ChownRecursive keeps each directory open while it works on the tree
+// below it, so it uses one file descriptor for each level of the tree.
+// In a tree deeper than the limit on open files, the directories it
+// cannot open are reported as errors, and left unchanged.
+func ChownRecursive(root string, uid, gid int) error {
+	// Open root before looking at it, so that the directory whose
+	// owner is changed is the one that is then read.
+	dir, err := openDirNoFollow(root)
+	if err != nil {
+		// root may be a symbolic link, or a file of another kind.
+		fi, lerr := Lstat(root)
+		if lerr != nil {
+			return lerr
+		}
+		if fi.IsDir() {
+			return err
+		}
+		return Lchown(root, uid, gid)
+	}
+	var errs []error
+	if err := dir.Chown(uid, gid); err != nil {
+		errs = append(errs, err)
+	}
+	errs = chownTreeAt(dir, uid, gid, errs)
+	return errors.Join(errs...)
+}
+
+// chownTreeAt changes the owner of the entries of the directory dir and
+// of the trees below them, appending the errors it encounters to errs.
+// It closes dir.
+func chownTreeAt(dir *File, uid, gid int, errs []error) []error {
+	defer dir.Close()
+	for {
+		dirs, err := dir.ReadDir(readDirSeqBatch)
+		for _, d := range dirs {
+			name := d.Name()
+			if err := dir.chownAt(name, uid, gid, false); err != nil {
+				errs = append(errs, err)
+			}
+			if d.IsDir() {
+				sub, err := dir.openDirAt(name)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				errs = chownTreeAt(sub, uid, gid, errs)
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				errs = append(errs, err)
+			}
+			return errs
+		}
+	}
 }
 
 // endsWithDot reports whether the final component of path is ".".
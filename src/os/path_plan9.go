@@ -4,6 +4,8 @@
 
 package os
 
+import "errors"
+
 const (
 	PathSeparator     = '/'    // OS-specific path separator
 	PathListSeparator = '\000' // OS-specific path list separator
@@ -13,3 +15,20 @@ const (
 func IsPathSeparator(c uint8) bool {
 	return PathSeparator == c
 }
+
+// absPath returns an absolute form of path, which must not be empty,
+// without cleaning it.
+func absPath(path string) (string, error) {
+	if IsPathSeparator(path[0]) {
+		return path, nil
+	}
+	wd, err := Getwd()
+	if err != nil {
+		return "", err
+	}
+	return joinPath(wd, path), nil
+}
+
+// errSymlinkLoop is the error of a path that goes through too many
+// symbolic links. Plan 9 has no symbolic links.
+var errSymlinkLoop = errors.New("too many levels of symbolic links")
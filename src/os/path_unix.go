@@ -6,6 +6,8 @@
 
 package os
 
+import "syscall"
+
 const (
 	PathSeparator     = '/' // OS-specific path separator
 	PathListSeparator = ':' // OS-specific path list separator
@@ -51,3 +53,20 @@ func splitPath(path string) (string, string) {
 
 	return dirname, basename
 }
+
+// absPath returns an absolute form of path, which must not be empty,
+// without cleaning it.
+func absPath(path string) (string, error) {
+	if IsPathSeparator(path[0]) {
+		return path, nil
+	}
+	wd, err := Getwd()
+	if err != nil {
+		return "", err
+	}
+	return joinPath(wd, path), nil
+}
+
+// errSymlinkLoop is the error of a path that goes through too many
+// symbolic links.
+var errSymlinkLoop error = syscall.ELOOP
@@ -150,3 +150,15 @@ func addExtendedPrefix(path string) string {
 	copy(buf, prefix)
 	return syscall.UTF16ToString(buf)
 }
+
+// absPath returns an absolute form of path, which must not be empty.
+func absPath(path string) (string, error) {
+	if filepathlite.IsAbs(path) {
+		return path, nil
+	}
+	return syscall.FullPath(path)
+}
+
+// errSymlinkLoop is the error of a path that goes through too many
+// symbolic links.
+var errSymlinkLoop error = syscall.ELOOP
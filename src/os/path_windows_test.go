@@ -271,6 +271,45 @@ func TestLongPathRel(t *testing.T) {
 	testLongPathAbs(t, target)
 }
 
+func TestLongPathReadFile(t *testing.T) {
+	t.Parallel()
+
+	// Build the hierarchy with each kind of separator, so that the
+	// extended-length prefix is also added to paths using forward slashes.
+	for _, sep := range []string{`\`, "/"} {
+		tmp := t.TempDir()
+		dir := tmp
+		for len(dir) < 300 {
+			dir += sep + strings.Repeat("d", 50)
+		}
+		if err := os.MkdirAll(dir, 0o777); err != nil {
+			t.Fatal(err)
+		}
+		name := dir + sep + "file.txt"
+		if len(name) <= syscall.MAX_PATH {
+			t.Fatalf("test path is only %d bytes long", len(name))
+		}
+		want := "hello from a long path"
+		if err := os.WriteFile(name, []byte(want), 0o666); err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("ReadFile(%q) = %q, want %q", name, got, want)
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].Name() != "file.txt" {
+			t.Errorf("ReadDir(%q) = %v, want [file.txt]", dir, entries)
+		}
+	}
+}
+
 func BenchmarkAddExtendedPrefix(b *testing.B) {
 	veryLong := `C:\l` + strings.Repeat("o", 248) + "ng"
 	b.ReportAllocs()
@@ -16,11 +16,14 @@
 package os
 
 import (
+	"context"
 	"errors"
+	"internal/poll"
 	"internal/syscall/unix"
 	"runtime"
 	"sync"
 	"syscall"
+	"time"
 	"unsafe"
 )
 
@@ -125,6 +128,102 @@ func (p *Process) pidfdWait() (*ProcessState, error) {
 	}, nil
 }
 
+// pidfdWaitContext is like pidfdWait, but returns ctx.Err() without
+// reaping the process if ctx is done before the process exits.
+func (p *Process) pidfdWaitContext(ctx context.Context) (*ProcessState, error) {
+	handle, status := p.handleTransientAcquire()
+	switch status {
+	case statusDone:
+		return nil, NewSyscallError("wait", syscall.ECHILD)
+	case statusReleased:
+		return nil, syscall.EINVAL
+	}
+	err := pidfdBlockUntilExited(ctx, handle)
+	p.handleTransientRelease()
+	if err != nil {
+		return nil, err
+	}
+	return p.pidfdWait()
+}
+
+// pidfdBlockUntilExited blocks until the process referred to by pidfd
+// has exited or ctx is done, without reaping the process.
+//
+// A pidfd becomes readable when its process exits, so the wait goes
+// through the runtime poller on a duplicate of pidfd, and canceling ctx
+// sets a read deadline in the past to wake the waiter. Unlike the PID
+// based wait, nothing is left behind once we return.
+func pidfdBlockUntilExited(ctx context.Context, pidfd uintptr) error {
+	fd, err := unix.Fcntl(int(pidfd), syscall.F_DUPFD_CLOEXEC, 0)
+	if err != nil {
+		return NewSyscallError("fcntl", err)
+	}
+	pfd := &poll.FD{Sysfd: fd}
+	if err := pfd.Init("pidfd", true); err != nil {
+		// The poller refused the pidfd. Block in a separate goroutine
+		// instead, which is left behind until the process exits if ctx
+		// is done first.
+		exited := make(chan error, 1)
+		go func() {
+			defer pfd.Close()
+			exited <- pidfdWaitid(uintptr(fd), 0)
+		}()
+		select {
+		case err := <-exited:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	defer pfd.Close()
+
+	stop := context.AfterFunc(ctx, func() {
+		pfd.SetReadDeadline(time.Unix(1, 0))
+	})
+	defer stop()
+
+	var werr error
+	err = pfd.RawRead(func(fd uintptr) bool {
+		werr = pidfdWaitid(fd, syscall.WNOHANG)
+		return werr != errNotExited
+	})
+	if err == poll.ErrDeadlineExceeded {
+		return ctx.Err()
+	}
+	if err != nil {
+		return NewSyscallError("pidfd_wait", err)
+	}
+	return werr
+}
+
+var errNotExited = errors.New("process has not exited")
+
+// pidfdWaitid waits for the process referred to by pidfd to exit without
+// reaping it. With syscall.WNOHANG in flags, it returns errNotExited
+// rather than blocking.
+func pidfdWaitid(pidfd uintptr, flags int) error {
+	var (
+		info unix.SiginfoChild
+		e    syscall.Errno
+	)
+	for {
+		_, _, e = syscall.Syscall6(syscall.SYS_WAITID, _P_PIDFD, pidfd, uintptr(unsafe.Pointer(&info)), uintptr(syscall.WEXITED|syscall.WNOWAIT|flags), 0, 0)
+		if e != syscall.EINTR {
+			break
+		}
+	}
+	if e != 0 {
+		return NewSyscallError("waitid", e)
+	}
+	// waitid leaves info zeroed if WNOHANG is set and the process
+	// has not exited yet.
+	if info.Pid == 0 {
+		return errNotExited
+	}
+	return nil
+}
+
 func (p *Process) pidfdSendSignal(s syscall.Signal) error {
 	handle, status := p.handleTransientAcquire()
 	switch status {
@@ -149,3 +149,11 @@ func TestPidfdLeak(t *testing.T) {
 		t.Errorf("got descriptor %d, want %d", got[count-1], want[count-1])
 	}
 }
+
+func TestProcessWaitContextPID(t *testing.T) {
+	// Wait through a PID-based Process for the same child, to cover the
+	// non-pidfd code path.
+	testProcessWaitContext(t, func(p *os.Process) *os.Process {
+		return os.NewPIDProcess(p.Pid)
+	})
+}
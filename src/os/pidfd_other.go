@@ -6,7 +6,10 @@
 
 package os
 
-import "syscall"
+import (
+	"context"
+	"syscall"
+)
 
 func ensurePidfd(sysAttr *syscall.SysProcAttr) (*syscall.SysProcAttr, bool) {
 	return sysAttr, false
@@ -26,6 +29,10 @@ func (_ *Process) pidfdWait() (*ProcessState, error) {
 	panic("unreachable")
 }
 
+func (_ *Process) pidfdWaitContext(_ context.Context) (*ProcessState, error) {
+	panic("unreachable")
+}
+
 func (_ *Process) pidfdSendSignal(_ syscall.Signal) error {
 	panic("unreachable")
 }
@@ -0,0 +1,46 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "syscall"
+
+// Flags to Pipe2.
+const (
+	// PipeNonblock puts the pipe into non-blocking mode and registers
+	// it with the runtime poller, as Pipe does on Unix systems.
+	PipeNonblock = 1 << iota
+	// PipeCloexec keeps the pipe from being inherited by child
+	// processes, as Pipe does on Unix systems.
+	PipeCloexec
+)
+
+// Pipe2 is like [Pipe], but flags, a combination of [PipeNonblock] and
+// [PipeCloexec], control the mode of the returned files.
+// On Unix systems, Pipe is equivalent to Pipe2(PipeNonblock|PipeCloexec).
+// On Windows, where the handles returned by Pipe are inheritable, and on
+// Plan 9, Pipe is equivalent to Pipe2(0).
+//
+// With PipeNonblock, the files are managed by the runtime poller as
+// those returned by Pipe are: a blocked Read or Write parks only the
+// calling goroutine, and deadlines are supported. Without it, the
+// descriptors are left in blocking mode, which suits a child process
+// that inherits them, but a blocked Read or Write then occupies an
+// operating system thread, and [File.SetDeadline] returns [ErrNoDeadline].
+//
+// Without PipeCloexec, the descriptors are inherited across exec.
+// A child process started with [StartProcess] or os/exec still only
+// sees the files it is explicitly passed as its standard files or
+// ExtraFiles in the expected places, but may also hold the pipe open
+// at its original descriptor number.
+//
+// On Windows, pipes do not support non-blocking mode and PipeNonblock
+// has no effect; without PipeCloexec, the handles are inheritable.
+// On Plan 9, only a flags value without PipeCloexec is supported.
+func Pipe2(flags int) (r *File, w *File, err error) {
+	if flags&^(PipeNonblock|PipeCloexec) != 0 {
+		return nil, nil, NewSyscallError("pipe2", syscall.EINVAL)
+	}
+	return pipe2(flags)
+}
@@ -10,13 +10,23 @@ import "syscall"
 
 // Pipe returns a connected pair of Files; reads from r return bytes written to w.
 // It returns the files and an error, if any.
+// The files are registered with the runtime poller, so they support
+// [File.SetReadDeadline] and [File.SetWriteDeadline].
 func Pipe() (r *File, w *File, err error) {
+	return pipe2(PipeNonblock | PipeCloexec)
+}
+
+func pipe2(flags int) (r *File, w *File, err error) {
 	var p [2]int
 
-	e := syscall.Pipe2(p[0:], syscall.O_CLOEXEC)
+	sysflags := 0
+	if flags&PipeCloexec != 0 {
+		sysflags = syscall.O_CLOEXEC
+	}
+	e := syscall.Pipe2(p[0:], sysflags)
 	if e != nil {
 		return nil, nil, NewSyscallError("pipe2", e)
 	}
 
-	return newFile(p[0], "|0", kindPipe, false), newFile(p[1], "|1", kindPipe, false), nil
+	return newPipeFile(p[0], "|0", flags), newPipeFile(p[1], "|1", flags), nil
 }
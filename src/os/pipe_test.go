@@ -11,6 +11,7 @@ package os_test
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"internal/testenv"
 	"io"
@@ -476,3 +477,35 @@ func TestFdReadRace(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestPipe2(t *testing.T) {
+	if _, _, err := os.Pipe2(1 << 10); err == nil {
+		t.Error("Pipe2 with an unknown flag succeeded")
+	}
+
+	for _, flags := range []int{0, os.PipeNonblock, os.PipeCloexec, os.PipeNonblock | os.PipeCloexec} {
+		r, w, err := os.Pipe2(flags)
+		if err != nil {
+			t.Fatalf("Pipe2(%#x): %v", flags, err)
+		}
+		if _, err := w.Write([]byte("x")); err != nil {
+			t.Errorf("Pipe2(%#x): Write: %v", flags, err)
+		}
+		var b [1]byte
+		if n, err := r.Read(b[:]); n != 1 || err != nil || b[0] != 'x' {
+			t.Errorf("Pipe2(%#x): Read = %d, %v, %q; want 1, nil, %q", flags, n, err, b[:n], "x")
+		}
+
+		if runtime.GOOS != "windows" {
+			err = r.SetReadDeadline(time.Now().Add(time.Hour))
+			if flags&os.PipeNonblock != 0 && err != nil {
+				t.Errorf("Pipe2(%#x): SetReadDeadline: %v", flags, err)
+			}
+			if flags&os.PipeNonblock == 0 && !errors.Is(err, os.ErrNoDeadline) {
+				t.Errorf("Pipe2(%#x): SetReadDeadline = %v, want %v", flags, err, os.ErrNoDeadline)
+			}
+		}
+		r.Close()
+		w.Close()
+	}
+}
@@ -10,7 +10,13 @@ import "syscall"
 
 // Pipe returns a connected pair of Files; reads from r return bytes written to w.
 // It returns the files and an error, if any.
+// The files are registered with the runtime poller, so they support
+// [File.SetReadDeadline] and [File.SetWriteDeadline].
 func Pipe() (r *File, w *File, err error) {
+	return pipe2(PipeNonblock | PipeCloexec)
+}
+
+func pipe2(flags int) (r *File, w *File, err error) {
 	var p [2]int
 
 	// See ../syscall/exec.go for description of lock.
@@ -20,9 +26,11 @@ func Pipe() (r *File, w *File, err error) {
 		syscall.ForkLock.RUnlock()
 		return nil, nil, NewSyscallError("pipe", e)
 	}
-	syscall.CloseOnExec(p[0])
-	syscall.CloseOnExec(p[1])
+	if flags&PipeCloexec != 0 {
+		syscall.CloseOnExec(p[0])
+		syscall.CloseOnExec(p[1])
+	}
 	syscall.ForkLock.RUnlock()
 
-	return newFile(p[0], "|0", kindPipe, false), newFile(p[1], "|1", kindPipe, false), nil
+	return newPipeFile(p[0], "|0", flags), newPipeFile(p[1], "|1", flags), nil
 }
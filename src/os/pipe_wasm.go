@@ -14,3 +14,7 @@ func Pipe() (r *File, w *File, err error) {
 	// Neither GOOS=js nor GOOS=wasip1 have pipes.
 	return nil, nil, NewSyscallError("pipe", syscall.ENOSYS)
 }
+
+func pipe2(flags int) (r *File, w *File, err error) {
+	return Pipe()
+}
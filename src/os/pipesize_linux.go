@@ -0,0 +1,27 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"errors"
+	"internal/syscall/unix"
+	"syscall"
+)
+
+func (f *File) setPipeSize(size int) (actual int, err error) {
+	if cerr := f.pfd.RawControl(func(fd uintptr) {
+		if _, err = unix.Fcntl(int(fd), syscall.F_SETPIPE_SZ, size); err != nil {
+			return
+		}
+		actual, err = unix.Fcntl(int(fd), syscall.F_GETPIPE_SZ, 0)
+	}); cerr != nil {
+		return 0, cerr
+	}
+	if err == syscall.EBADF {
+		// The descriptor is open, so it is not a pipe.
+		return 0, errors.ErrUnsupported
+	}
+	return actual, err
+}
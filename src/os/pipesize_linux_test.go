@@ -0,0 +1,52 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	"errors"
+	. "os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetPipeSize(t *testing.T) {
+	t.Parallel()
+
+	r, w, err := Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	// Ask for an amount that is not a multiple of the page size,
+	// and below the default limit of pipe-max-size.
+	const size = 256<<10 + 1
+	got, err := w.SetPipeSize(size)
+	if err != nil {
+		t.Fatalf("SetPipeSize(%d): %v", size, err)
+	}
+	if got < size || got%Getpagesize() != 0 {
+		t.Errorf("SetPipeSize(%d) = %d, want a multiple of the page size of at least %d", size, got, size)
+	}
+	// Both ends share the buffer.
+	if got2, err := r.SetPipeSize(got); err != nil || got2 != got {
+		t.Errorf("SetPipeSize(%d) on the read end = %d, %v; want %d, nil", got, got2, err, got)
+	}
+
+	f, err := Create(filepath.Join(t.TempDir(), "file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.SetPipeSize(size); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("SetPipeSize on a regular file = %v, want ErrUnsupported", err)
+	}
+
+	r.Close()
+	if _, err := r.SetPipeSize(size); !errors.Is(err, ErrClosed) {
+		t.Errorf("SetPipeSize on a closed pipe = %v, want ErrClosed", err)
+	}
+}
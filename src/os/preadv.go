@@ -0,0 +1,25 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build freebsd || linux
+
+package os
+
+import "runtime"
+
+// preadv reads into bufs starting at byte offset off with a single
+// preadv system call.
+func (f *File) preadv(bufs [][]byte, off int64) (int, error) {
+	n, err := f.pfd.Preadv(bufs, off)
+	runtime.KeepAlive(f)
+	return int(n), err
+}
+
+// pwritev writes bufs starting at byte offset off with a single
+// pwritev system call.
+func (f *File) pwritev(bufs [][]byte, off int64) (int, error) {
+	n, err := f.pfd.Pwritev(bufs, off)
+	runtime.KeepAlive(f)
+	return int(n), err
+}
@@ -0,0 +1,19 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !freebsd && !linux
+
+package os
+
+// preadv reads into the first buffer of bufs starting at byte offset
+// off. The caller loops to fill the remaining buffers.
+func (f *File) preadv(bufs [][]byte, off int64) (int, error) {
+	return f.pread(bufs[0], off)
+}
+
+// pwritev writes the first buffer of bufs starting at byte offset off.
+// The caller loops to write the remaining buffers.
+func (f *File) pwritev(bufs [][]byte, off int64) (int, error) {
+	return f.pwrite(bufs[0], off)
+}
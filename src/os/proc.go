@@ -54,6 +54,35 @@ func Getgroups() ([]int, error) {
 	return gids, NewSyscallError("getgroups", e)
 }
 
+// GetgroupsNames is like [Getgroups], but returns the names of the groups
+// instead of their numeric ids.
+//
+// On Unix systems, the names are looked up in /etc/group, as the pure Go
+// implementation of [os/user.LookupGroupId] does. Other sources of group
+// information, such as those configured in nsswitch.conf, are not
+// consulted. A group without an entry in /etc/group is named by its
+// numeric id in decimal.
+//
+// On Windows, it returns an error wrapping [errors.ErrUnsupported].
+func GetgroupsNames() ([]string, error) {
+	return getgroupsNames()
+}
+
+// Umask sets the file mode creation mask of the calling process to
+// mask&0o777, and returns the previous mask. The mask is cleared from
+// the permission bits requested when creating files and directories,
+// for example by [OpenFile] and [Mkdir].
+//
+// The mask is shared by all goroutines, so changing it temporarily
+// affects files created concurrently elsewhere in the program. To give
+// a single file an exact mode, use [Chmod] after creating it instead.
+//
+// On Windows and Plan 9, which have no umask, Umask does nothing and
+// returns 0.
+func Umask(mask int) (old int) {
+	return umask(mask & 0o777)
+}
+
 // Exit causes the current program to exit with the given status code.
 // Conventionally, code zero indicates success, non-zero an error.
 // The program terminates immediately; deferred functions are not run.
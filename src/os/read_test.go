@@ -6,6 +6,7 @@ package os_test
 
 import (
 	"bytes"
+	"context"
 	. "os"
 	"path/filepath"
 	"runtime"
@@ -40,6 +41,44 @@ func TestReadFile(t *testing.T) {
 	checkNamedSize(t, filename, int64(len(contents)))
 }
 
+func TestReadFileContext(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ReadFileContext(context.Background(), "rumpelstilzchen"); !IsNotExist(err) {
+		t.Errorf("ReadFileContext of missing file: got %v, want a not-exist error", err)
+	}
+
+	filename := "read_test.go"
+	want, err := ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadFileContext(context.Background(), filename)
+	if err != nil {
+		t.Fatalf("ReadFileContext %s: %v", filename, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("ReadFileContext %s returned different contents than ReadFile", filename)
+	}
+
+	// A file larger than one chunk is read completely.
+	big := filepath.Join(t.TempDir(), "big")
+	want = bytes.Repeat([]byte("0123456789abcdef"), (3<<20)/16+1)
+	if err := WriteFile(big, want, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err = ReadFileContext(context.Background(), big)
+	if err != nil || !bytes.Equal(got, want) {
+		t.Errorf("ReadFileContext %s = %d bytes, %v; want %d bytes, nil", big, len(got), err, len(want))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ReadFileContext(ctx, filename); err != context.Canceled {
+		t.Errorf("ReadFileContext with canceled context: got %v, want %v", err, context.Canceled)
+	}
+}
+
 func TestWriteFile(t *testing.T) {
 	t.Parallel()
 
@@ -68,6 +107,74 @@ func TestWriteFile(t *testing.T) {
 	}
 }
 
+func TestWriteFileAtomic(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "atomic")
+	if err := WriteFileAtomic(name, []byte("first"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ReadFile(name); err != nil || string(data) != "first" {
+		t.Fatalf("ReadFile after create = %q, %v; want %q", data, err, "first")
+	}
+
+	// Replacing the file keeps its mode, not perm.
+	if err := Chmod(name, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileAtomic(name, []byte("second"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ReadFile(name); err != nil || string(data) != "second" {
+		t.Fatalf("ReadFile after replace = %q, %v; want %q", data, err, "second")
+	}
+	if runtime.GOOS != "windows" && runtime.GOOS != "plan9" {
+		fi, err := Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode().Perm() != 0o600 {
+			t.Errorf("mode after replace = %v; want %v", fi.Mode().Perm(), FileMode(0o600))
+		}
+	}
+
+	entries, err := ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("dir contains %v; want only %s", entries, filepath.Base(name))
+	}
+}
+
+func TestWriteFileAtomicFailure(t *testing.T) {
+	// A file cannot replace a non-empty directory, so LinkTo fails
+	// after the data has been written.
+	dir := t.TempDir()
+	name := filepath.Join(dir, "atomic")
+	if err := Mkdir(name, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	inner := filepath.Join(name, "inner")
+	if err := WriteFile(inner, []byte("original"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileAtomic(name, []byte("replacement"), 0o644); err == nil {
+		t.Fatal("WriteFileAtomic over a non-empty directory succeeded")
+	}
+	if data, err := ReadFile(inner); err != nil || string(data) != "original" {
+		t.Errorf("ReadFile after failed WriteFileAtomic = %q, %v; want %q", data, err, "original")
+	}
+	entries, err := ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("dir contains %v after failure; want only %s", entries, filepath.Base(name))
+	}
+}
+
 func TestReadOnlyWriteFile(t *testing.T) {
 	if Getuid() == 0 {
 		t.Skipf("Root can write to read-only files anyway, so skip the read-only test.")
@@ -136,3 +243,76 @@ func TestReadDir(t *testing.T) {
 		t.Fatalf("ReadDir %s: exec directory not found", dirname)
 	}
 }
+
+func TestFileLines(t *testing.T) {
+	t.Parallel()
+
+	long := bytes.Repeat([]byte("x"), 100000)
+	tests := []struct {
+		name    string
+		content []byte
+		want    []string
+	}{
+		{"Empty", nil, nil},
+		{"NoTrailingNewline", []byte("one\ntwo"), []string{"one", "two"}},
+		{"TrailingNewline", []byte("one\ntwo\n"), []string{"one", "two"}},
+		{"BlankLines", []byte("\n\na\n\n"), []string{"", "", "a", ""}},
+		{"CRLF", []byte("one\r\ntwo\r\n"), []string{"one\r", "two\r"}},
+		{"LongLine", append(append([]byte("a\n"), long...), "\nb"...), []string{"a", string(long), "b"}},
+	}
+	dir := t.TempDir()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name := filepath.Join(dir, test.name)
+			if err := WriteFile(name, test.content, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			f, err := Open(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			var got []string
+			for line, err := range f.Lines() {
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, string(line))
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("got %d lines, want %d", len(got), len(test.want))
+			}
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Errorf("line %d = %.20q, want %.20q", i, got[i], test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFileLinesError(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "plan9" {
+		t.Skip("reading a directory succeeds on Plan 9")
+	}
+
+	// Reading a directory fails on the remaining systems.
+	f, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	n := 0
+	for line, err := range f.Lines() {
+		n++
+		if line != nil || err == nil {
+			t.Errorf("Lines on a directory yielded %q, %v; want nil, non-nil error", line, err)
+		}
+	}
+	if n != 1 {
+		t.Errorf("Lines on a directory yielded %d values, want 1", n)
+	}
+}
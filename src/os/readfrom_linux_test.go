@@ -14,6 +14,7 @@ import (
 	"net"
 	. "os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -60,6 +61,53 @@ func TestCopyFileRange(t *testing.T) {
 			}
 		})
 	})
+	t.Run("Offsets", func(t *testing.T) {
+		dst, src, data, hook := newCopyFileRangeTest(t, 32769)
+
+		// Start reading the source part way through, and write
+		// after some existing content in the destination.
+		const srcoff, dstoff = 1000, 333
+		prefix := bytes.Repeat([]byte{'x'}, dstoff)
+		if _, err := dst.Write(prefix); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := src.Seek(srcoff, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+
+		n, err := io.Copy(dst, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !hook.called {
+			t.Fatal("never called poll.CopyFileRange")
+		}
+		if want := int64(len(data) - srcoff); n != want {
+			t.Errorf("copied %d bytes, want %d", n, want)
+		}
+		mustSeekStart(t, dst)
+		mustContainData(t, dst, append(prefix, data[srcoff:]...))
+	})
+	t.Run("Large", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping large copy in short mode")
+		}
+		const size = 64 << 20
+		dst, src, data, hook := newCopyFileRangeTest(t, size)
+
+		n, err := io.Copy(dst, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !hook.called {
+			t.Fatal("never called poll.CopyFileRange")
+		}
+		if n != size {
+			t.Fatalf("copied %d bytes, want %d", n, size)
+		}
+		mustSeekStart(t, dst)
+		mustContainData(t, dst, data)
+	})
 	t.Run("DoesntTryInAppendMode", func(t *testing.T) {
 		dst, src, data, hook := newCopyFileRangeTest(t, 42)
 
@@ -330,6 +378,402 @@ func TestSpliceFile(t *testing.T) {
 	})
 }
 
+func TestSpliceFileToFile(t *testing.T) {
+	// Pretend that copy_file_range(2) cannot handle the copy, as is
+	// the case across file systems on old kernels, so that ReadFrom
+	// falls back to splicing through a pipe.
+	orig := *PollCopyFileRangeP
+	*PollCopyFileRangeP = func(dst, src *poll.FD, remain int64) (int64, bool, error) {
+		return 0, false, nil
+	}
+	t.Cleanup(func() { *PollCopyFileRangeP = orig })
+
+	const size = 10 << 20
+	dst, src, data, _ := newCopyFileRangeTest(t, size)
+	hook := hookSpliceFile(t)
+
+	n, err := io.Copy(dst, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hook.called {
+		t.Fatal("never called poll.Splice")
+	}
+	if hook.dstfd != int(dst.Fd()) || hook.srcfd != int(src.Fd()) {
+		t.Fatalf("wrong file descriptors: got %d <- %d, want %d <- %d", hook.dstfd, hook.srcfd, dst.Fd(), src.Fd())
+	}
+	if n != size {
+		t.Fatalf("copied %d bytes, want %d", n, size)
+	}
+	if got := dst.CopyStats().Method; got != "splice" {
+		t.Errorf("CopyStats().Method = %q, want %q", got, "splice")
+	}
+
+	// The result must match the generic user space copy byte for byte.
+	generic, err := CreateTemp(t.TempDir(), "generic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer generic.Close()
+	mustSeekStart(t, src)
+	if _, err := io.Copy(generic, struct{ io.Reader }{src}); err != nil {
+		t.Fatal(err)
+	}
+	mustSeekStart(t, generic)
+	mustContainData(t, generic, data)
+	mustSeekStart(t, dst)
+	mustContainData(t, dst, data)
+}
+
+func TestFiclone(t *testing.T) {
+	dir := t.TempDir()
+	src, err := Create(filepath.Join(dir, "src"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	dst, err := Create(filepath.Join(dir, "dst"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+	data := bytes.Repeat([]byte("reflink\n"), 1<<20)
+	if _, err := src.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	mustSeekStart(t, src)
+
+	n, err := io.Copy(dst, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := dst.CopyStats().Method; got != "ficlone" {
+		t.Skipf("file system of %s does not support reflinks: CopyStats().Method = %q", dir, got)
+	}
+	if n != int64(len(data)) {
+		t.Fatalf("copied %d bytes, want %d", n, len(data))
+	}
+	// Both offsets are at the end, as after a copy.
+	if off, err := src.Seek(0, io.SeekCurrent); err != nil || off != n {
+		t.Errorf("src offset = %d, %v; want %d", off, err, n)
+	}
+	if off, err := dst.Seek(0, io.SeekCurrent); err != nil || off != n {
+		t.Errorf("dst offset = %d, %v; want %d", off, err, n)
+	}
+	mustSeekStart(t, dst)
+	mustContainData(t, dst, data)
+
+	// The clone does not share writes with src.
+	if _, err := src.WriteAt([]byte("changed"), 0); err != nil {
+		t.Fatal(err)
+	}
+	mustSeekStart(t, dst)
+	mustContainData(t, dst, data)
+}
+
+func TestFicloneFallback(t *testing.T) {
+	var calls []string
+	ficloneErr := error(syscall.EOPNOTSUPP)
+	orig := *UnixFicloneP
+	*UnixFicloneP = func(dstfd, srcfd int) error {
+		calls = append(calls, "ficlone")
+		if ficloneErr != nil {
+			return ficloneErr
+		}
+		// Pretend to clone by copying.
+		var st syscall.Stat_t
+		if err := syscall.Fstat(srcfd, &st); err != nil {
+			return err
+		}
+		buf := make([]byte, st.Size)
+		if _, err := syscall.Pread(srcfd, buf, 0); err != nil {
+			return err
+		}
+		_, err := syscall.Pwrite(dstfd, buf, 0)
+		return err
+	}
+	t.Cleanup(func() { *UnixFicloneP = orig })
+	origCFR := *PollCopyFileRangeP
+	*PollCopyFileRangeP = func(dst, src *poll.FD, remain int64) (int64, bool, error) {
+		calls = append(calls, "copy_file_range")
+		return origCFR(dst, src, remain)
+	}
+	t.Cleanup(func() { *PollCopyFileRangeP = origCFR })
+
+	const size = 1 << 20
+	newFiles := func(t *testing.T) (dst, src *File) {
+		dir := t.TempDir()
+		src, err := Create(filepath.Join(dir, "src"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { src.Close() })
+		dst, err = Create(filepath.Join(dir, "dst"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { dst.Close() })
+		if _, err := src.Write(bytes.Repeat([]byte{'x'}, size)); err != nil {
+			t.Fatal(err)
+		}
+		mustSeekStart(t, src)
+		return dst, src
+	}
+	copyAndCheck := func(t *testing.T, dst, src *File, wantCalls []string, wantMethod string) {
+		t.Helper()
+		calls = nil
+		n, err := io.Copy(dst, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != size {
+			t.Errorf("copied %d bytes, want %d", n, size)
+		}
+		if !slices.Equal(calls, wantCalls) {
+			t.Errorf("calls = %q, want %q", calls, wantCalls)
+		}
+		if got := dst.CopyStats(); got.Method != wantMethod || got.Bytes != size {
+			t.Errorf("CopyStats() = %+v, want %s of %d bytes", got, wantMethod, size)
+		}
+		mustSeekStart(t, dst)
+		mustContainData(t, dst, bytes.Repeat([]byte{'x'}, size))
+	}
+
+	for _, err := range []error{syscall.EOPNOTSUPP, syscall.EXDEV, syscall.EINVAL} {
+		t.Run("Error="+err.Error(), func(t *testing.T) {
+			ficloneErr = err
+			dst, src := newFiles(t)
+			copyAndCheck(t, dst, src, []string{"ficlone", "copy_file_range"}, "copy_file_range")
+		})
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		ficloneErr = nil
+		dst, src := newFiles(t)
+		copyAndCheck(t, dst, src, []string{"ficlone"}, "ficlone")
+		if off, err := src.Seek(0, io.SeekCurrent); err != nil || off != size {
+			t.Errorf("src offset = %d, %v; want %d", off, err, size)
+		}
+	})
+
+	// A clone replaces the whole destination, so it is not tried
+	// unless that is what the copy does.
+	t.Run("NotEmpty", func(t *testing.T) {
+		ficloneErr = nil
+		dst, src := newFiles(t)
+		if _, err := dst.WriteString("x"); err != nil {
+			t.Fatal(err)
+		}
+		calls = nil
+		if _, err := io.Copy(dst, src); err != nil {
+			t.Fatal(err)
+		}
+		if slices.Contains(calls, "ficlone") {
+			t.Errorf("ficlone tried for a non-empty destination")
+		}
+	})
+	t.Run("Offset", func(t *testing.T) {
+		ficloneErr = nil
+		dst, src := newFiles(t)
+		if _, err := src.Seek(1, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		calls = nil
+		if _, err := io.Copy(dst, src); err != nil {
+			t.Fatal(err)
+		}
+		if slices.Contains(calls, "ficlone") {
+			t.Errorf("ficlone tried for a source not at its start")
+		}
+	})
+	t.Run("Limited", func(t *testing.T) {
+		ficloneErr = nil
+		dst, src := newFiles(t)
+		calls = nil
+		if _, err := io.Copy(dst, io.LimitReader(src, size-1)); err != nil {
+			t.Fatal(err)
+		}
+		if slices.Contains(calls, "ficlone") {
+			t.Errorf("ficlone tried for a partial copy")
+		}
+	})
+}
+
+// disableFiclone makes the FICLONE ioctl fail for the duration of the
+// test, as it does on file systems without reflinks.
+func disableFiclone(t *testing.T) {
+	orig := *UnixFicloneP
+	*UnixFicloneP = func(dstfd, srcfd int) error { return syscall.EOPNOTSUPP }
+	t.Cleanup(func() { *UnixFicloneP = orig })
+}
+
+// TestCopyFileProgressFiclone checks that CopyFileProgress clones a
+// file larger than its progress chunks in one go.
+func TestCopyFileProgressFiclone(t *testing.T) {
+	var clones int
+	orig := *UnixFicloneP
+	*UnixFicloneP = func(dstfd, srcfd int) error {
+		clones++
+		// Pretend to clone by copying.
+		var st syscall.Stat_t
+		if err := syscall.Fstat(srcfd, &st); err != nil {
+			return err
+		}
+		buf := make([]byte, st.Size)
+		if _, err := syscall.Pread(srcfd, buf, 0); err != nil {
+			return err
+		}
+		_, err := syscall.Pwrite(dstfd, buf, 0)
+		return err
+	}
+	t.Cleanup(func() { *UnixFicloneP = orig })
+
+	dir := t.TempDir()
+	src, dst := filepath.Join(dir, "src"), filepath.Join(dir, "dst")
+	data := bytes.Repeat([]byte("clone"), (2*CopyProgressChunk+12345)/5)
+	if err := WriteFile(src, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var calls [][2]int64
+	if err := CopyFileProgress(dst, src, func(copied, total int64) {
+		calls = append(calls, [2]int64{copied, total})
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if clones != 1 {
+		t.Errorf("FICLONE tried %d times, want once", clones)
+	}
+	if size := int64(len(data)); len(calls) != 1 || calls[0] != [2]int64{size, size} {
+		t.Errorf("progress calls %v, want one with [%d %d]", calls, size, size)
+	}
+	if got, err := ReadFile(dst); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("dst differs from src: %v", err)
+	}
+}
+
+func TestCopySparse(t *testing.T) {
+	// Make copySparse copy the data regions through its own buffer,
+	// as it does where copy_file_range(2) is not available.
+	orig := *PollCopyFileRangeP
+	*PollCopyFileRangeP = func(dst, src *poll.FD, remain int64) (int64, bool, error) {
+		return 0, false, nil
+	}
+	t.Cleanup(func() { *PollCopyFileRangeP = orig })
+	disableFiclone(t)
+
+	dir := t.TempDir()
+	src, err := Create(filepath.Join(dir, "src"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	const size = 64 << 20
+	head := bytes.Repeat([]byte("head"), 1024)
+	tail := bytes.Repeat([]byte("tail"), 1024)
+	if _, err := src.Write(head); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.WriteAt(tail, size/2); err != nil {
+		t.Fatal(err)
+	}
+	// Leave a trailing hole as well.
+	if err := src.Truncate(size); err != nil {
+		t.Fatal(err)
+	}
+	if hole, err := src.Seek(0, 4 /* SEEK_HOLE */); err != nil || hole >= size {
+		t.Skipf("file system does not report holes: %d, %v", hole, err)
+	}
+	mustSeekStart(t, src)
+
+	dst, err := Create(filepath.Join(dir, "dst"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+	n, err := io.Copy(dst, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != size {
+		t.Fatalf("copied %d bytes, want %d", n, size)
+	}
+	if got := dst.CopyStats().Method; got != "sparse" {
+		t.Errorf("CopyStats().Method = %q, want %q", got, "sparse")
+	}
+	if off, err := dst.Seek(0, io.SeekCurrent); err != nil || off != size {
+		t.Errorf("dst offset after copy = %d, %v; want %d", off, err, size)
+	}
+
+	fi, err := dst.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Size() != size {
+		t.Errorf("dst size = %d, want %d", fi.Size(), size)
+	}
+	if blocks := fi.Sys().(*syscall.Stat_t).Blocks * 512; blocks > 1<<20 {
+		t.Errorf("dst uses %d bytes on disk; holes were not preserved", blocks)
+	}
+
+	want, err := ReadFile(src.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustSeekStart(t, dst)
+	mustContainData(t, dst, want)
+}
+
+// TestCopySparseTruncated checks that copySparse stops cleanly at the
+// new end of a source file that is truncated during the copy.
+func TestCopySparseTruncated(t *testing.T) {
+	dir := t.TempDir()
+	src, err := Create(filepath.Join(dir, "src"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	const size = 64 << 20
+	head := bytes.Repeat([]byte("head"), 1024)
+	if _, err := src.Write(head); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Truncate(size); err != nil {
+		t.Fatal(err)
+	}
+	if hole, err := src.Seek(0, 4 /* SEEK_HOLE */); err != nil || hole >= size {
+		t.Skipf("file system does not report holes: %d, %v", hole, err)
+	}
+	mustSeekStart(t, src)
+
+	// Truncate src once copySparse has found its first data region,
+	// and make it read the region itself.
+	const short = 1000
+	orig := *PollCopyFileRangeP
+	*PollCopyFileRangeP = func(dst, src2 *poll.FD, remain int64) (int64, bool, error) {
+		if err := src.Truncate(short); err != nil {
+			t.Error(err)
+		}
+		return 0, false, nil
+	}
+	t.Cleanup(func() { *PollCopyFileRangeP = orig })
+	disableFiclone(t)
+
+	dst, err := Create(filepath.Join(dir, "dst"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+	n, err := io.Copy(dst, src)
+	if err != nil {
+		t.Fatalf("io.Copy: %v", err)
+	}
+	if n != short {
+		t.Errorf("copied %d bytes, want %d", n, short)
+	}
+	mustSeekStart(t, dst)
+	mustContainData(t, dst, head[:short])
+}
+
 func testSpliceFile(t *testing.T, proto string, size, limit int64) {
 	dst, src, data, hook, cleanup := newSpliceFileTest(t, proto, size)
 	defer cleanup()
@@ -552,6 +996,9 @@ func testCopyFileRange(t *testing.T, size int64, limit int64) {
 func newCopyFileRangeTest(t *testing.T, size int64) (dst, src *File, data []byte, hook *copyFileRangeHook) {
 	t.Helper()
 
+	// Keep a reflink clone from taking over the copies
+	// these tests are about.
+	disableFiclone(t)
 	hook = hookCopyFileRange(t)
 	tmp := t.TempDir()
 
@@ -0,0 +1,53 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !unix
+
+package os
+
+// readv emulates a vectored read with a Read call per buffer, stopping
+// at the first short read so that, as with readv, no gap is left in the
+// data returned.
+func (f *File) readv(bufs [][]byte) (n int, err error) {
+	for _, b := range bufs {
+		if len(b) == 0 {
+			continue
+		}
+		m, e := f.read(b)
+		if m < 0 {
+			m = 0
+		}
+		n += m
+		if e != nil {
+			if n > 0 {
+				// Report the data read so far; the error
+				// will recur on the next call.
+				return n, nil
+			}
+			return 0, e
+		}
+		if m < len(b) {
+			break
+		}
+	}
+	return n, nil
+}
+
+// writev emulates a vectored write with a Write call per buffer.
+func (f *File) writev(bufs [][]byte) (n int, err error) {
+	for _, b := range bufs {
+		if len(b) == 0 {
+			continue
+		}
+		m, e := f.write(b)
+		if m < 0 {
+			m = 0
+		}
+		n += m
+		if e != nil {
+			return n, e
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	"bytes"
+	"io"
+	. "os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestReadvWritev(t *testing.T) {
+	t.Parallel()
+
+	f, err := Create(filepath.Join(t.TempDir(), "vec"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	bufs := [][]byte{[]byte("hello, "), nil, []byte("vectored"), []byte(" world")}
+	orig := slices.Clone(bufs)
+	want := bytes.Join(bufs, nil)
+
+	n, err := f.Writev(bufs)
+	if err != nil {
+		t.Fatalf("Writev: %v", err)
+	}
+	if n != len(want) {
+		t.Fatalf("Writev wrote %d bytes, want %d", n, len(want))
+	}
+	if !slices.EqualFunc(bufs, orig, bytes.Equal) {
+		t.Errorf("Writev modified its argument: got %q, want %q", bufs, orig)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got := [][]byte{make([]byte, 3), make([]byte, 0), make([]byte, 10), make([]byte, 32)}
+	n, err = f.Readv(got)
+	if err != nil {
+		t.Fatalf("Readv: %v", err)
+	}
+	if n != len(want) {
+		t.Fatalf("Readv read %d bytes, want %d", n, len(want))
+	}
+	if string(got[0]) != "hel" || string(got[2]) != "lo, vector" || string(got[3][:n-13]) != "ed world" {
+		t.Errorf("Readv read %q, want %q", got, want)
+	}
+
+	n, err = f.Readv(got)
+	if n != 0 || err != io.EOF {
+		t.Errorf("Readv at end of file = %d, %v; want 0, EOF", n, err)
+	}
+
+	if n, err := f.Readv(nil); n != 0 || err != nil {
+		t.Errorf("Readv(nil) = %d, %v; want 0, nil", n, err)
+	}
+}
+
+// TestWritevPipe checks that Writev writes all of a header and a body
+// larger than the pipe buffer, which takes several partial writes.
+func TestWritevPipe(t *testing.T) {
+	// Skip on wasm, which doesn't have pipes.
+	if runtime.GOOS == "js" || runtime.GOOS == "wasip1" {
+		t.Skipf("skipping on %s: no pipes", runtime.GOOS)
+	}
+	t.Parallel()
+
+	r, w, err := Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	header := []byte("header\n")
+	body := bytes.Repeat([]byte("0123456789abcdef"), 1<<16)
+	want := append(slices.Clone(header), body...)
+
+	done := make(chan []byte)
+	go func() {
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Error(err)
+		}
+		done <- got
+	}()
+
+	n, err := w.Writev([][]byte{header, body})
+	if n != len(want) || err != nil {
+		t.Errorf("Writev = %d, %v; want %d, nil", n, err, len(want))
+	}
+	w.Close()
+	if got := <-done; !bytes.Equal(got, want) {
+		t.Errorf("read %d bytes back from the pipe, want the %d bytes written", len(got), len(want))
+	}
+}
+
+func TestReadvAtWritevAt(t *testing.T) {
+	t.Parallel()
+
+	f, err := Create(filepath.Join(t.TempDir(), "vec"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// Write records of 3 buffers each at disjoint offsets concurrently.
+	const (
+		records = 16
+		recLen  = 1 + 100 + 27
+	)
+	var wg sync.WaitGroup
+	for i := range records {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := byte('a' + i)
+			bufs := [][]byte{{c}, bytes.Repeat([]byte{c}, 100), bytes.Repeat([]byte{c}, 27)}
+			if n, err := f.WritevAt(bufs, int64(i*recLen)); n != recLen || err != nil {
+				t.Errorf("WritevAt record %d = %d, %v; want %d, nil", i, n, err, recLen)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if off, err := f.Seek(0, io.SeekCurrent); off != 0 || err != nil {
+		t.Errorf("file offset after WritevAt = %d, %v; want 0, nil", off, err)
+	}
+
+	for i := range records {
+		want := bytes.Repeat([]byte{byte('a' + i)}, recLen)
+		got := [][]byte{make([]byte, 50), nil, make([]byte, recLen-50)}
+		if n, err := f.ReadvAt(got, int64(i*recLen)); n != recLen || err != nil {
+			t.Fatalf("ReadvAt record %d = %d, %v; want %d, nil", i, n, err, recLen)
+		}
+		if g := bytes.Join(got, nil); !bytes.Equal(g, want) {
+			t.Errorf("record %d = %q, want %q", i, g, want)
+		}
+	}
+
+	// Reading past the end returns what is there and io.EOF.
+	got := [][]byte{make([]byte, 10), make([]byte, 10)}
+	n, err := f.ReadvAt(got, records*recLen-5)
+	if n != 5 || err != io.EOF {
+		t.Errorf("ReadvAt at end of file = %d, %v; want 5, EOF", n, err)
+	}
+
+	if off, err := f.Seek(0, io.SeekCurrent); off != 0 || err != nil {
+		t.Errorf("file offset after ReadvAt = %d, %v; want 0, nil", off, err)
+	}
+
+	if _, err := f.ReadvAt(got, -1); err == nil {
+		t.Error("ReadvAt with negative offset succeeded")
+	}
+	if _, err := f.WritevAt(got, -1); err == nil {
+		t.Error("WritevAt with negative offset succeeded")
+	}
+}
+
+func benchmarkVectoredWrite(b *testing.B, write func(*File, [][]byte) error) {
+	f, err := Create(filepath.Join(b.TempDir(), "vec"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer f.Close()
+
+	bufs := [][]byte{make([]byte, 16), make([]byte, 64), make([]byte, 16)}
+	b.SetBytes(96)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := write(f, bufs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWritev(b *testing.B) {
+	b.Run("Writev", func(b *testing.B) {
+		benchmarkVectoredWrite(b, func(f *File, bufs [][]byte) error {
+			_, err := f.Writev(bufs)
+			return err
+		})
+	})
+	b.Run("Write", func(b *testing.B) {
+		benchmarkVectoredWrite(b, func(f *File, bufs [][]byte) error {
+			for _, buf := range bufs {
+				if _, err := f.Write(buf); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
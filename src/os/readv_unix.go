@@ -0,0 +1,29 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package os
+
+import (
+	"runtime"
+	"slices"
+)
+
+// readv reads into bufs with a single readv system call.
+func (f *File) readv(bufs [][]byte) (int, error) {
+	n, err := f.pfd.Readv(bufs)
+	runtime.KeepAlive(f)
+	return int(n), err
+}
+
+// writev writes all of bufs using as many writev system calls as needed.
+func (f *File) writev(bufs [][]byte) (int, error) {
+	// poll.FD.Writev consumes the slice it is given as it goes,
+	// so hand it a copy to leave the caller's slice intact.
+	v := slices.Clone(bufs)
+	n, err := f.pfd.Writev(&v)
+	runtime.KeepAlive(f)
+	return int(n), err
+}
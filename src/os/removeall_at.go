@@ -12,7 +12,7 @@ import (
 	"syscall"
 )
 
-func removeAll(path string) error {
+func removeAll(path string) []error {
 	if path == "" {
 		// fail silently to retain compatibility with previous behavior
 		// of RemoveAll. See issue 28830.
@@ -22,7 +22,7 @@ func removeAll(path string) error {
 	// The rmdir system call does not permit removing ".",
 	// so we don't permit it either.
 	if endsWithDot(path) {
-		return &PathError{Op: "RemoveAll", Path: path, Err: syscall.EINVAL}
+		return []error{&PathError{Op: "RemoveAll", Path: path, Err: syscall.EINVAL}}
 	}
 
 	// Simple case: if Remove works, we're done.
@@ -41,21 +41,23 @@ func removeAll(path string) error {
 		return nil
 	}
 	if err != nil {
-		return err
+		return []error{err}
 	}
 	defer parent.Close()
 
-	if err := removeAllFrom(parent, base); err != nil {
+	errs := removeAllFrom(parent, base)
+	for _, err := range errs {
 		if pathErr, ok := err.(*PathError); ok {
 			pathErr.Path = parentDir + string(PathSeparator) + pathErr.Path
-			err = pathErr
 		}
-		return err
 	}
-	return nil
+	return errs
 }
 
-func removeAllFrom(parent *File, base string) error {
+// removeAllFrom removes base from parent, along with any children it
+// contains. It removes everything it can and returns the errors it
+// encounters, the first of which is the one RemoveAll reports.
+func removeAllFrom(parent *File, base string) []error {
 	parentFd := int(parent.Fd())
 	// Simple case: if Unlink (aka remove) works, we're done.
 	err := ignoringEINTR(func() error {
@@ -72,29 +74,34 @@ func removeAllFrom(parent *File, base string) error {
 	// whose contents need to be removed.
 	// Otherwise just return the error.
 	if err != syscall.EISDIR && err != syscall.EPERM && err != syscall.EACCES {
-		return &PathError{Op: "unlinkat", Path: base, Err: err}
+		return []error{&PathError{Op: "unlinkat", Path: base, Err: err}}
 	}
 	uErr := err
 
-	// Remove the directory's entries.
-	var recurseErr error
+	// Remove the directory's entries. Each pass after the first reads
+	// the entries that could not be removed again, so remember them,
+	// to report each failure once.
+	var recurseErrs []error
+	var failed map[string]bool
 	for {
 		const reqSize = 1024
 		var respSize int
 
 		// Open the directory to recurse into
-		file, err := openDirAt(parentFd, base)
+		fd, err := openDirAt(parentFd, base)
 		if err != nil {
 			if IsNotExist(err) {
 				return nil
 			}
 			if err == syscall.ENOTDIR || err == unix.NoFollowErrno {
 				// Not a directory; return the error from the unix.Unlinkat.
-				return &PathError{Op: "unlinkat", Path: base, Err: uErr}
+				return []error{&PathError{Op: "unlinkat", Path: base, Err: uErr}}
 			}
-			recurseErr = &PathError{Op: "openfdat", Path: base, Err: err}
+			recurseErrs = append(recurseErrs, &PathError{Op: "openfdat", Path: base, Err: err})
 			break
 		}
+		// We use kindNoPoll because we know that this is a directory.
+		file := newFile(fd, base, kindNoPoll, false)
 
 		for {
 			numErr := 0
@@ -106,21 +113,32 @@ func removeAllFrom(parent *File, base string) error {
 				if IsNotExist(readErr) {
 					return nil
 				}
-				return &PathError{Op: "readdirnames", Path: base, Err: readErr}
+				// This error comes first, as it is the one
+				// RemoveAll reports.
+				return append([]error{&PathError{Op: "readdirnames", Path: base, Err: readErr}}, recurseErrs...)
 			}
 
 			respSize = len(names)
 			for _, name := range names {
-				err := removeAllFrom(file, name)
-				if err != nil {
+				if failed[name] {
+					numErr++
+					continue
+				}
+				errs := removeAllFrom(file, name)
+				if len(errs) == 0 {
+					continue
+				}
+				if failed == nil {
+					failed = make(map[string]bool)
+				}
+				failed[name] = true
+				for _, err := range errs {
 					if pathErr, ok := err.(*PathError); ok {
 						pathErr.Path = base + string(PathSeparator) + pathErr.Path
 					}
-					numErr++
-					if recurseErr == nil {
-						recurseErr = err
-					}
 				}
+				numErr++
+				recurseErrs = append(recurseErrs, errs...)
 			}
 
 			// If we can delete any entry, break to start new iteration.
@@ -151,10 +169,10 @@ func removeAllFrom(parent *File, base string) error {
 		return nil
 	}
 
-	if recurseErr != nil {
-		return recurseErr
+	if len(recurseErrs) > 0 {
+		return recurseErrs
 	}
-	return &PathError{Op: "unlinkat", Path: base, Err: unlinkError}
+	return []error{&PathError{Op: "unlinkat", Path: base, Err: unlinkError}}
 }
 
 // openDirAt opens a directory name relative to the directory referred to by
@@ -165,7 +183,7 @@ func removeAllFrom(parent *File, base string) error {
 // This acts like openFileNolog rather than OpenFile because
 // we are going to (try to) remove the file.
 // The contents of this file are not relevant for test caching.
-func openDirAt(dirfd int, name string) (*File, error) {
+func openDirAt(dirfd int, name string) (int, error) {
 	var r int
 	for {
 		var e error
@@ -179,13 +197,12 @@ func openDirAt(dirfd int, name string) (*File, error) {
 			continue
 		}
 
-		return nil, e
+		return -1, e
 	}
 
 	if !supportsCloseOnExec {
 		syscall.CloseOnExec(r)
 	}
 
-	// We use kindNoPoll because we know that this is a directory.
-	return newFile(r, name, kindNoPoll, false), nil
+	return r, nil
 }
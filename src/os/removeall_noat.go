@@ -12,7 +12,7 @@ import (
 	"syscall"
 )
 
-func removeAll(path string) error {
+func removeAll(path string) []error {
 	if path == "" {
 		// fail silently to retain compatibility with previous behavior
 		// of RemoveAll. See issue 28830.
@@ -23,7 +23,7 @@ func removeAll(path string) error {
 	// so we don't permit it to remain consistent with the
 	// "at" implementation of RemoveAll.
 	if endsWithDot(path) {
-		return &PathError{Op: "RemoveAll", Path: path, Err: syscall.EINVAL}
+		return []error{&PathError{Op: "RemoveAll", Path: path, Err: syscall.EINVAL}}
 	}
 
 	// Simple case: if Remove works, we're done.
@@ -38,15 +38,15 @@ func removeAll(path string) error {
 		if serr, ok := serr.(*PathError); ok && (IsNotExist(serr.Err) || serr.Err == syscall.ENOTDIR) {
 			return nil
 		}
-		return serr
+		return []error{serr}
 	}
 	if !dir.IsDir() {
 		// Not a directory; return the error from Remove.
-		return err
+		return []error{err}
 	}
 
-	// Remove contents & return first error.
-	err = nil
+	// Remove contents & return the errors, first error first.
+	var errs []error
 	for {
 		fd, err := Open(path)
 		if err != nil {
@@ -54,7 +54,7 @@ func removeAll(path string) error {
 				// Already deleted by someone else.
 				return nil
 			}
-			return err
+			return append(errs, err)
 		}
 
 		const reqSize = 1024
@@ -66,11 +66,9 @@ func removeAll(path string) error {
 			names, readErr = fd.Readdirnames(reqSize)
 
 			for _, name := range names {
-				err1 := RemoveAll(path + string(PathSeparator) + name)
-				if err == nil {
-					err = err1
-				}
-				if err1 != nil {
+				errs1 := removeAll(path + string(PathSeparator) + name)
+				if len(errs1) > 0 {
+					errs = append(errs, errs1...)
 					numErr++
 				}
 			}
@@ -93,8 +91,8 @@ func removeAll(path string) error {
 			break
 		}
 		// If Readdirnames returned an error, use it.
-		if err == nil {
-			err = readErr
+		if readErr != nil {
+			errs = append(errs, readErr)
 		}
 		if len(names) == 0 {
 			break
@@ -110,7 +108,7 @@ func removeAll(path string) error {
 				return nil
 			}
 
-			if err != nil {
+			if len(errs) > 0 {
 				// We got some error removing the
 				// directory contents, and since we
 				// read fewer names than we requested
@@ -118,7 +116,7 @@ func removeAll(path string) error {
 				// remove. Don't loop around to read
 				// the directory again. We'll probably
 				// just get the same error.
-				return err
+				return errs
 			}
 		}
 	}
@@ -135,8 +133,8 @@ func removeAll(path string) error {
 			}
 		}
 	}
-	if err == nil {
-		err = err1
+	if len(errs) == 0 {
+		return []error{err1}
 	}
-	return err
+	return errs
 }
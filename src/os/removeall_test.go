@@ -11,6 +11,7 @@ import (
 	. "os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -521,3 +522,187 @@ func BenchmarkRemoveAll(b *testing.B) {
 		}
 	}
 }
+
+func TestRemoveAllErrors(t *testing.T) {
+	switch runtime.GOOS {
+	case "js", "wasip1", "windows":
+		t.Skipf("skipping test on %s", runtime.GOOS)
+	}
+
+	if Getuid() == 0 {
+		t.Skip("skipping test when running as root")
+	}
+
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	root := filepath.Join(tempDir, "root")
+	for _, d := range []string{"a", "b", "c", "c/d"} {
+		if err := MkdirAll(filepath.Join(root, d), 0777); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{"a/1", "b/keep1", "c/d/keep2", "top"} {
+		if err := WriteFile(filepath.Join(root, f), nil, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The entries in the read-only directories b and c/d can't be removed.
+	for _, ro := range []string{"b", "c/d"} {
+		d := filepath.Join(root, ro)
+		if err := Chmod(d, 0555); err != nil {
+			t.Fatal(err)
+		}
+		defer Chmod(d, 0777)
+	}
+
+	err := RemoveAllErrors(root)
+	if err == nil {
+		t.Fatalf("RemoveAllErrors(%q) succeeded with unremovable entries", root)
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("RemoveAllErrors(%q) = %v (%T), want joined errors", root, err, err)
+	}
+	var paths []string
+	for _, e := range joined.Unwrap() {
+		pathErr, ok := e.(*PathError)
+		if !ok {
+			t.Errorf("RemoveAllErrors(%q): error %v has type %T, want *fs.PathError", root, e, e)
+			continue
+		}
+		paths = append(paths, pathErr.Path)
+	}
+	want := []string{filepath.Join(root, "b", "keep1"), filepath.Join(root, "c", "d", "keep2")}
+	slices.Sort(paths)
+	if !slices.Equal(paths, want) {
+		t.Errorf("RemoveAllErrors(%q) failed for %q, want %q", root, paths, want)
+	}
+
+	// Everything else is gone.
+	for _, name := range []string{"a", "top"} {
+		if _, err := Lstat(filepath.Join(root, name)); !IsNotExist(err) {
+			t.Errorf("%s was not removed: %v", name, err)
+		}
+	}
+	for _, name := range []string{"b/keep1", "c/d/keep2"} {
+		if _, err := Lstat(filepath.Join(root, name)); err != nil {
+			t.Errorf("unremovable %s: %v", name, err)
+		}
+	}
+
+	// RemoveAll reports only the first of those errors.
+	if err := RemoveAll(root); err == nil || !slices.Contains(want, err.(*PathError).Path) {
+		t.Errorf("RemoveAll(%q) = %v, want error for one of %q", root, err, want)
+	}
+}
+
+// TestRemoveAllErrorsLarge checks that RemoveAllErrors reports each
+// failure once in a directory that takes more than one pass to read.
+func TestRemoveAllErrorsLarge(t *testing.T) {
+	switch runtime.GOOS {
+	case "js", "wasip1", "windows":
+		t.Skipf("skipping test on %s", runtime.GOOS)
+	}
+
+	if Getuid() == 0 {
+		t.Skip("skipping test when running as root")
+	}
+
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "root")
+	if err := Mkdir(root, 0777); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1100; i++ {
+		if err := WriteFile(filepath.Join(root, "file"+strconv.Itoa(i)), nil, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var want []string
+	for _, ro := range []string{"ro1", "ro2", "ro3"} {
+		d := filepath.Join(root, ro)
+		if err := Mkdir(d, 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteFile(filepath.Join(d, "keep"), nil, 0666); err != nil {
+			t.Fatal(err)
+		}
+		if err := Chmod(d, 0555); err != nil {
+			t.Fatal(err)
+		}
+		defer Chmod(d, 0777)
+		want = append(want, filepath.Join(d, "keep"))
+	}
+
+	err := RemoveAllErrors(root)
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("RemoveAllErrors(%q) = %v (%T), want joined errors", root, err, err)
+	}
+	var paths []string
+	for _, e := range joined.Unwrap() {
+		if pathErr, ok := e.(*PathError); ok {
+			paths = append(paths, pathErr.Path)
+		} else {
+			t.Errorf("RemoveAllErrors(%q): error %v has type %T, want *fs.PathError", root, e, e)
+		}
+	}
+	slices.Sort(paths)
+	if !slices.Equal(paths, want) {
+		t.Errorf("RemoveAllErrors(%q) failed for %q, want %q", root, paths, want)
+	}
+}
+
+func TestRemoveAllDoesNotFollowSymlinks(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	outside := filepath.Join(tempDir, "outside")
+	if err := MkdirAll(filepath.Join(outside, "sub"), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(filepath.Join(outside, "sub", "file"), []byte("keep"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(tempDir, "dir")
+	if err := MkdirAll(filepath.Join(dir, "a"), 0777); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "a", "link")
+	if err := Symlink(outside, link); err != nil {
+		t.Fatal(err)
+	}
+	// A link to a file outside is not followed either.
+	if err := Symlink(filepath.Join(outside, "sub", "file"), filepath.Join(dir, "filelink")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Lstat(link); !IsNotExist(err) {
+		t.Errorf("symlink %s still exists after RemoveAll: %v", link, err)
+	}
+	if _, err := Lstat(dir); !IsNotExist(err) {
+		t.Errorf("%s still exists after RemoveAll: %v", dir, err)
+	}
+	if data, err := ReadFile(filepath.Join(outside, "sub", "file")); err != nil || string(data) != "keep" {
+		t.Errorf("file outside the tree = %q, %v; want %q, nil", data, err, "keep")
+	}
+
+	// Removing the link itself leaves its target alone.
+	link = filepath.Join(tempDir, "link")
+	if err := Symlink(outside, link); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveAll(link); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Stat(filepath.Join(outside, "sub", "file")); err != nil {
+		t.Errorf("target of removed symlink: %v", err)
+	}
+}
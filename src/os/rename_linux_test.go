@@ -0,0 +1,51 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	"errors"
+	. "os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestRenameAcrossFSMounts(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test that mounts file systems in short mode")
+	}
+	dir := t.TempDir()
+	var mnts [2]string
+	for i := range mnts {
+		mnts[i] = filepath.Join(dir, "mnt"+string(rune('a'+i)))
+		if err := Mkdir(mnts[i], 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := syscall.Mount("tmpfs", mnts[i], "tmpfs", 0, ""); err != nil {
+			t.Skipf("cannot mount tmpfs: %v", err)
+		}
+		t.Cleanup(func() {
+			if err := syscall.Unmount(mnts[i], 0); err != nil {
+				t.Errorf("unmounting %s: %v", mnts[i], err)
+			}
+		})
+	}
+
+	src, dst := filepath.Join(mnts[0], "tree"), filepath.Join(mnts[1], "tree")
+	old := Umask(0)
+	mtime := makeRenameTree(t, src)
+	Umask(old)
+
+	if err := Rename(src, dst); !errors.Is(err, syscall.EXDEV) {
+		t.Fatalf("Rename across tmpfs mounts = %v, want EXDEV", err)
+	}
+	if err := RenameAcrossFS(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Lstat(src); !IsNotExist(err) {
+		t.Errorf("source still present after RenameAcrossFS: %v", err)
+	}
+	checkRenameTree(t, dst, mtime)
+}
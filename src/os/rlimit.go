@@ -0,0 +1,44 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// RlimInfinity is the value of a resource limit that imposes no limit.
+const RlimInfinity = ^uint64(0)
+
+// Getrlimit returns the soft and hard limits of the calling process
+// for the given resource, one of the Rlimit constants.
+// A limit of [RlimInfinity] means that the resource is not limited.
+//
+// On systems without resource limits, including Windows and Plan 9,
+// Getrlimit returns an error wrapping [errors.ErrUnsupported].
+func Getrlimit(resource int) (soft, hard uint64, err error) {
+	soft, hard, err = getrlimit(resource)
+	if err != nil {
+		return 0, 0, NewSyscallError("getrlimit", err)
+	}
+	return soft, hard, nil
+}
+
+// Setrlimit sets the soft and hard limits of the calling process
+// for the given resource, one of the Rlimit constants.
+// The soft limit must not exceed the hard limit, and only a privileged
+// process may raise the hard limit.
+// A limit of [RlimInfinity] removes the limit.
+//
+// On Darwin, the kernel rejects a soft [RlimitNofile] limit above the
+// kern.maxfilesperproc sysctl, even if the hard limit is [RlimInfinity];
+// Setrlimit lowers such a soft limit to kern.maxfilesperproc instead.
+//
+// Setting [RlimitNofile] stops [StartProcess] from restoring the
+// original soft limit in child processes; see [syscall.Setrlimit].
+//
+// On systems without resource limits, including Windows and Plan 9,
+// Setrlimit returns an error wrapping [errors.ErrUnsupported].
+func Setrlimit(resource int, soft, hard uint64) error {
+	if err := setrlimit(resource, soft, hard); err != nil {
+		return NewSyscallError("setrlimit", err)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || netbsd
+
+package os
+
+import "syscall"
+
+const (
+	rlimitAS     = syscall.RLIMIT_AS
+	rlimitNproc  = 0x7
+	rlimInfinity = 1<<63 - 1
+)
@@ -0,0 +1,17 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "syscall"
+
+// capNofile lowers a soft RLIMIT_NOFILE above kern.maxfilesperproc,
+// which macOS rejects.
+func capNofile(soft uint64) uint64 {
+	n, err := syscall.SysctlUint32("kern.maxfilesperproc")
+	if err != nil {
+		return soft
+	}
+	return min(soft, uint64(n))
+}
@@ -0,0 +1,15 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && (mips || mipsle || mips64 || mips64le)
+
+package os
+
+import "syscall"
+
+const (
+	rlimitAS     = syscall.RLIMIT_AS
+	rlimitNproc  = 0x8
+	rlimInfinity = ^uint64(0)
+)
@@ -0,0 +1,11 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+const (
+	rlimitAS     = -1 // OpenBSD does not limit the address space
+	rlimitNproc  = 0x7
+	rlimInfinity = 1<<63 - 1
+)
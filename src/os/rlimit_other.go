@@ -0,0 +1,29 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !unix
+
+package os
+
+import "errors"
+
+// Resources for use with [Getrlimit] and [Setrlimit].
+const (
+	RlimitCPU = iota
+	RlimitFsize
+	RlimitData
+	RlimitStack
+	RlimitCore
+	RlimitNofile
+	RlimitAS
+	RlimitNproc
+)
+
+func getrlimit(resource int) (soft, hard uint64, err error) {
+	return 0, 0, errors.ErrUnsupported
+}
+
+func setrlimit(resource int, soft, hard uint64) error {
+	return errors.ErrUnsupported
+}
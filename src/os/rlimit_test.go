@@ -0,0 +1,66 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	"errors"
+	. "os"
+	"runtime"
+	"testing"
+)
+
+func TestRlimitNofile(t *testing.T) {
+	soft, hard, err := Getrlimit(RlimitNofile)
+	switch runtime.GOOS {
+	case "windows", "plan9", "js", "wasip1":
+		if !errors.Is(err, errors.ErrUnsupported) {
+			t.Errorf("Getrlimit = %v, want ErrUnsupported", err)
+		}
+		if err := Setrlimit(RlimitNofile, 1, 1); !errors.Is(err, errors.ErrUnsupported) {
+			t.Errorf("Setrlimit = %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if soft > hard {
+		t.Fatalf("soft limit %d is above hard limit %d", soft, hard)
+	}
+	defer func() {
+		if err := Setrlimit(RlimitNofile, soft, hard); err != nil {
+			t.Errorf("restoring limit: %v", err)
+		}
+	}()
+
+	want := min(soft, 512)
+	if err := Setrlimit(RlimitNofile, want, hard); err != nil {
+		t.Fatal(err)
+	}
+	gotSoft, gotHard, err := Getrlimit(RlimitNofile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotSoft != want || gotHard != hard {
+		t.Errorf("Getrlimit after Setrlimit(%d, %d) = %d, %d", want, hard, gotSoft, gotHard)
+	}
+
+	if hard != RlimInfinity {
+		if err := Setrlimit(RlimitNofile, hard+1, hard); err == nil {
+			t.Errorf("Setrlimit with soft limit above hard limit succeeded")
+		}
+	}
+}
+
+func TestRlimitUnsupportedResource(t *testing.T) {
+	if _, _, err := Getrlimit(-1); err == nil {
+		t.Errorf("Getrlimit(-1) succeeded")
+	}
+	if RlimitNproc >= 0 {
+		if _, _, err := Getrlimit(RlimitNproc); err != nil && !errors.Is(err, errors.ErrUnsupported) {
+			t.Errorf("Getrlimit(RlimitNproc): %v", err)
+		}
+	}
+}
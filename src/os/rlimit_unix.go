@@ -0,0 +1,69 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package os
+
+import "syscall"
+
+// Resources for use with [Getrlimit] and [Setrlimit].
+// A resource that the system does not support has the value -1.
+const (
+	RlimitCPU    = syscall.RLIMIT_CPU    // CPU time, in seconds
+	RlimitFsize  = syscall.RLIMIT_FSIZE  // size of a file that may be written, in bytes
+	RlimitData   = syscall.RLIMIT_DATA   // size of the data segment, in bytes
+	RlimitStack  = syscall.RLIMIT_STACK  // size of the stack, in bytes
+	RlimitCore   = syscall.RLIMIT_CORE   // size of a core file, in bytes
+	RlimitNofile = syscall.RLIMIT_NOFILE // one more than the largest file descriptor
+	RlimitAS     = rlimitAS              // size of the address space, in bytes
+	RlimitNproc  = rlimitNproc           // number of processes of the real user ID
+)
+
+func getrlimit(resource int) (soft, hard uint64, err error) {
+	if resource < 0 {
+		return 0, 0, syscall.EINVAL
+	}
+	var lim syscall.Rlimit
+	if err := syscall.Getrlimit(resource, &lim); err != nil {
+		return 0, 0, err
+	}
+	return fromRlim(uint64(lim.Cur)), fromRlim(uint64(lim.Max)), nil
+}
+
+func setrlimit(resource int, soft, hard uint64) error {
+	if resource < 0 {
+		return syscall.EINVAL
+	}
+	if resource == RlimitNofile {
+		soft = capNofile(soft)
+	}
+	var lim syscall.Rlimit
+	setRlimField(&lim.Cur, toRlim(soft))
+	setRlimField(&lim.Max, toRlim(hard))
+	return syscall.Setrlimit(resource, &lim)
+}
+
+// fromRlim converts a limit from the system's representation,
+// in which RLIM_INFINITY varies.
+func fromRlim(v uint64) uint64 {
+	if v == rlimInfinity {
+		return RlimInfinity
+	}
+	return v
+}
+
+// toRlim converts a limit to the system's representation.
+func toRlim(v uint64) uint64 {
+	if v >= rlimInfinity {
+		return rlimInfinity
+	}
+	return v
+}
+
+// setRlimField sets a field of syscall.Rlimit,
+// which is signed on some systems.
+func setRlimField[T ~int64 | ~uint64](p *T, v uint64) {
+	*p = T(v)
+}
@@ -0,0 +1,183 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"errors"
+	"internal/filepathlite"
+	"internal/testlog"
+)
+
+// Root may be used to only access files within a single directory tree.
+//
+// Methods on Root can only access files and directories beneath a root directory.
+// If any component of a file name passed to a method of Root references a location
+// outside the root, the method returns an error.
+// File names may reference the directory itself (.).
+//
+// Methods on Root will follow symbolic links, but symbolic links may not
+// reference a location outside the root.
+// Symbolic links must not be absolute.
+//
+// Methods on Root do not prohibit traversal of filesystem boundaries,
+// Linux bind mounts, /proc special files, or access to Unix device files.
+//
+// Methods on Root are safe to be used from multiple goroutines simultaneously.
+//
+// On most platforms, creating a Root opens a file descriptor or handle referencing
+// the directory. If the directory is moved, methods on Root reference the original
+// directory in its new location.
+//
+// On Linux, Root uses openat2 with RESOLVE_BENEATH when the kernel supports it.
+// On other Unix systems, Root resolves names one component at a time using
+// openat and related system calls, checking each symbolic link as it is found.
+// On other platforms, Root resolves names by inspecting each path component
+// before operating on the full path, and is vulnerable to races in which
+// a directory is concurrently replaced by a symbolic link.
+type Root struct {
+	root *root
+}
+
+const (
+	// Maximum number of symbolic links we will follow when resolving a file in a root.
+	rootMaxSymlinks = 8
+)
+
+// errPathEscapes is returned when a name refers to a location outside a Root.
+var errPathEscapes = errors.New("path escapes from parent")
+
+// OpenRoot opens the named directory for use as a Root.
+// If there is an error, it will be of type *PathError.
+func OpenRoot(name string) (*Root, error) {
+	testlog.Open(name)
+	return openRootNolog(name)
+}
+
+// OpenInRoot opens the file name in the directory dir.
+// It is equivalent to OpenRoot(dir) followed by opening the file in the root.
+//
+// OpenInRoot returns an error if any component of the name
+// references a location outside of dir.
+func OpenInRoot(dir, name string) (*File, error) {
+	r, err := OpenRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return r.Open(name)
+}
+
+// Name returns the name of the directory presented to OpenRoot.
+//
+// It is safe to call Name after [Close].
+func (r *Root) Name() string {
+	return r.root.Name()
+}
+
+// Close closes the Root.
+// After Close is called, methods on Root return errors.
+func (r *Root) Close() error {
+	return r.root.Close()
+}
+
+// Open opens the named file in the root for reading.
+// See [Open] for more details.
+func (r *Root) Open(name string) (*File, error) {
+	return r.OpenFile(name, O_RDONLY, 0)
+}
+
+// Create creates or truncates the named file in the root.
+// See [Create] for more details.
+func (r *Root) Create(name string) (*File, error) {
+	return r.OpenFile(name, O_RDWR|O_CREATE|O_TRUNC, 0666)
+}
+
+// OpenFile opens the named file in the root.
+// See [OpenFile] for more details.
+//
+// If perm contains bits other than the nine least-significant bits (0o777),
+// OpenFile returns an error.
+func (r *Root) OpenFile(name string, flag int, perm FileMode) (*File, error) {
+	if perm&0o777 != perm {
+		return nil, &PathError{Op: "openat", Path: name, Err: errors.New("unsupported file mode")}
+	}
+	r.logOpen(name)
+	rf, err := rootOpenFileNolog(r, name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	rf.appendMode = flag&O_APPEND != 0
+	return rf, nil
+}
+
+// Stat returns a [FileInfo] describing the named file in the root.
+// See [Stat] for more details.
+func (r *Root) Stat(name string) (FileInfo, error) {
+	r.logStat(name)
+	return rootStat(r, name)
+}
+
+// Mkdir creates a new directory in the root
+// with the specified name and permission bits (before umask).
+// See [Mkdir] for more details.
+//
+// If perm contains bits other than the nine least-significant bits (0o777),
+// Mkdir returns an error.
+func (r *Root) Mkdir(name string, perm FileMode) error {
+	if perm&0o777 != perm {
+		return &PathError{Op: "mkdirat", Path: name, Err: errors.New("unsupported file mode")}
+	}
+	return rootMkdir(r, name, perm)
+}
+
+// Remove removes the named file or (empty) directory in the root.
+// See [Remove] for more details.
+func (r *Root) Remove(name string) error {
+	return rootRemove(r, name)
+}
+
+func (r *Root) logOpen(name string) {
+	if log := testlog.Logger(); log != nil {
+		// This won't be right if r's name has changed since it was opened,
+		// but it's the best we can do.
+		log.Open(joinPath(r.Name(), name))
+	}
+}
+
+func (r *Root) logStat(name string) {
+	if log := testlog.Logger(); log != nil {
+		// This won't be right if r's name has changed since it was opened,
+		// but it's the best we can do.
+		log.Stat(joinPath(r.Name(), name))
+	}
+}
+
+// splitPathInRoot splits a path relative to a Root into components
+// and joins it with the given prefix and suffix.
+// Empty and "." components are removed.
+// Absolute and volume-relative paths escape the root.
+func splitPathInRoot(s string, prefix, suffix []string) ([]string, error) {
+	if len(s) == 0 {
+		return nil, ErrNotExist
+	}
+	if IsPathSeparator(s[0]) || filepathlite.VolumeName(s) != "" {
+		return nil, errPathEscapes
+	}
+	parts := append([]string{}, prefix...)
+	for len(s) > 0 {
+		i := 0
+		for i < len(s) && !IsPathSeparator(s[i]) {
+			i++
+		}
+		if part := s[:i]; part != "" && part != "." {
+			parts = append(parts, part)
+		}
+		if i < len(s) {
+			i++
+		}
+		s = s[i:]
+	}
+	return append(parts, suffix...), nil
+}
@@ -0,0 +1,23 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	. "os"
+	"testing"
+)
+
+// TestRootWithoutOpenat2 runs the Root tests using the
+// component-by-component resolution used when openat2 is unavailable.
+func TestRootWithoutOpenat2(t *testing.T) {
+	old := Openat2Unsupported.Load()
+	Openat2Unsupported.Store(true)
+	defer Openat2Unsupported.Store(old)
+
+	t.Run("Open", TestRootOpen)
+	t.Run("OpenInRoot", TestOpenInRoot)
+	t.Run("CreateStatMkdirRemove", TestRootCreateStatMkdirRemove)
+	t.Run("OpenDot", TestRootOpenDot)
+}
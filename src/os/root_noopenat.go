@@ -0,0 +1,162 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !unix
+
+package os
+
+import (
+	"errors"
+	"internal/filepathlite"
+	"sync/atomic"
+	"syscall"
+)
+
+// root implementation for platforms with no openat.
+// Currently plan9, js, wasip1, and windows.
+type root struct {
+	name   string
+	closed atomic.Bool
+}
+
+var errTooManySymlinks = errors.New("too many levels of symbolic links")
+
+// openRootNolog is OpenRoot.
+func openRootNolog(name string) (*Root, error) {
+	fi, err := Stat(name)
+	if err != nil {
+		return nil, &PathError{Op: "openat", Path: name, Err: underlyingError(err)}
+	}
+	if !fi.IsDir() {
+		return nil, &PathError{Op: "openat", Path: name, Err: syscall.ENOTDIR}
+	}
+	return &Root{&root{name: name}}, nil
+}
+
+func (r *root) Close() error {
+	// For consistency with File.Close.
+	r.closed.Store(true)
+	return nil
+}
+
+func (r *root) Name() string {
+	return r.name
+}
+
+// rootResolve returns the name of the file name refers to in r,
+// after checking each existing component of the path so that
+// no ".." or symbolic link leads outside the root.
+// If followFinal is false, a symlink in the last component is not followed.
+//
+// The result is only valid until the file system changes:
+// a directory concurrently replaced by a symbolic link can still
+// lead outside the root.
+func rootResolve(r *Root, name string, followFinal bool) (string, error) {
+	if r.root.closed.Load() {
+		return "", ErrClosed
+	}
+	parts, err := splitPathInRoot(name, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	var resolved []string
+	symlinks := 0
+	for i := 0; i < len(parts); i++ {
+		part := parts[i]
+		if part == ".." {
+			if len(resolved) == 0 {
+				return "", errPathEscapes
+			}
+			resolved = resolved[:len(resolved)-1]
+			continue
+		}
+		if !filepathlite.IsLocal(part) {
+			return "", errPathEscapes
+		}
+		resolved = append(resolved, part)
+		if i == len(parts)-1 && !followFinal {
+			break
+		}
+		full := r.root.fullName(resolved)
+		fi, err := Lstat(full)
+		if err != nil || !rootIsLink(fi) {
+			continue
+		}
+		symlinks++
+		if symlinks > rootMaxSymlinks {
+			return "", errTooManySymlinks
+		}
+		link, err := Readlink(full)
+		if err != nil {
+			return "", underlyingError(err)
+		}
+		// Replace the symlink with its target and resolve the rest
+		// of the path relative to the directory containing it.
+		resolved = resolved[:len(resolved)-1]
+		parts, err = splitPathInRoot(link, nil, parts[i+1:])
+		if err != nil {
+			return "", err
+		}
+		i = -1
+	}
+	return r.root.fullName(resolved), nil
+}
+
+func (r *root) fullName(parts []string) string {
+	name := r.name
+	for _, part := range parts {
+		name = joinPath(name, part)
+	}
+	return name
+}
+
+// rootOpenFileNolog is Root.OpenFile.
+func rootOpenFileNolog(root *Root, name string, flag int, perm FileMode) (*File, error) {
+	full, err := rootResolve(root, name, flag&O_EXCL == 0)
+	if err != nil {
+		return nil, &PathError{Op: "openat", Path: name, Err: err}
+	}
+	f, err := openFileNolog(full, flag, perm)
+	if err != nil {
+		return nil, &PathError{Op: "openat", Path: name, Err: underlyingError(err)}
+	}
+	return f, nil
+}
+
+// rootStat is Root.Stat.
+func rootStat(r *Root, name string) (FileInfo, error) {
+	full, err := rootResolve(r, name, true)
+	if err != nil {
+		return nil, &PathError{Op: "statat", Path: name, Err: err}
+	}
+	fi, err := Stat(full)
+	if err != nil {
+		return nil, &PathError{Op: "statat", Path: name, Err: underlyingError(err)}
+	}
+	return fi, nil
+}
+
+// rootMkdir is Root.Mkdir.
+func rootMkdir(r *Root, name string, perm FileMode) error {
+	full, err := rootResolve(r, name, false)
+	if err == nil {
+		err = Mkdir(full, perm)
+	}
+	if err != nil {
+		return &PathError{Op: "mkdirat", Path: name, Err: underlyingError(err)}
+	}
+	return nil
+}
+
+// rootRemove is Root.Remove.
+func rootRemove(r *Root, name string) error {
+	full, err := rootResolve(r, name, false)
+	if err == nil {
+		err = Remove(full)
+	}
+	if err != nil {
+		return &PathError{Op: "removeat", Path: name, Err: underlyingError(err)}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"sync/atomic"
+	"syscall"
+)
+
+// openat2Unsupported is set when the kernel does not implement openat2,
+// which was added in Linux 5.6.
+var openat2Unsupported atomic.Bool
+
+// openat2InRoot opens name in r using openat2 with RESOLVE_BENEATH,
+// which has the kernel reject any resolution that leaves the root.
+// It returns errNoOpenat2 if the caller should resolve name itself.
+func openat2InRoot(r *Root, name string, flag int, perm FileMode) (int, error) {
+	if openat2Unsupported.Load() {
+		return -1, errNoOpenat2
+	}
+	if err := r.root.incref(); err != nil {
+		return -1, err
+	}
+	defer r.root.decref()
+
+	how := &unix.OpenHow{
+		Flags:   uint64(flag | syscall.O_CLOEXEC | syscall.O_LARGEFILE),
+		Resolve: unix.RESOLVE_BENEATH | unix.RESOLVE_NO_MAGICLINKS,
+	}
+	if flag&O_CREATE != 0 || flag&unix.O_TMPFILE == unix.O_TMPFILE {
+		// The kernel rejects a mode unless a file may be created.
+		how.Mode = uint64(syscallMode(perm))
+	}
+	var fd int
+	err := ignoringEINTR(func() (err error) {
+		fd, err = unix.Openat2(r.root.fd, name, how)
+		return err
+	})
+	switch err {
+	case nil:
+		return fd, nil
+	case syscall.EXDEV:
+		return -1, errPathEscapes
+	case syscall.ENOSYS:
+		openat2Unsupported.Store(true)
+		return -1, errNoOpenat2
+	case syscall.EPERM, syscall.EAGAIN:
+		// EPERM may come from a seccomp filter that does not know
+		// about openat2; EAGAIN means a concurrent rename raced with
+		// the lookup. In either case, resolve the name ourselves.
+		return -1, errNoOpenat2
+	}
+	return -1, err
+}
@@ -0,0 +1,11 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix && !linux
+
+package os
+
+func openat2InRoot(r *Root, name string, flag int, perm FileMode) (int, error) {
+	return -1, errNoOpenat2
+}
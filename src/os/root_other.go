@@ -0,0 +1,12 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !unix && !windows
+
+package os
+
+// rootIsLink reports whether rootResolve must follow fi as a link.
+func rootIsLink(fi FileInfo) bool {
+	return fi.Mode()&ModeSymlink != 0
+}
@@ -0,0 +1,257 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	"errors"
+	"internal/testenv"
+	"io"
+	. "os"
+	"path/filepath"
+	"testing"
+)
+
+// makeRootTree creates a directory tree for Root tests:
+//
+//	root/a/b            file containing "b"
+//	root/inner -> a/b   symlink within the root
+//	root/dirlink -> a   symlink to a directory within the root
+//	root/etc -> /etc    absolute symlink
+//	root/up -> ../out   symlink escaping the root
+//	root/loop -> loop   symlink loop
+//	out                 file outside the root
+func makeRootTree(t *testing.T) string {
+	t.Helper()
+	testenv.MustHaveSymlink(t)
+	base := t.TempDir()
+	dir := filepath.Join(base, "root")
+	if err := MkdirAll(filepath.Join(dir, "a"), 0o777); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(filepath.Join(dir, "a", "b"), []byte("b"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(filepath.Join(base, "out"), []byte("out"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range []struct{ name, target string }{
+		{"inner", filepath.Join("a", "b")},
+		{"dirlink", "a"},
+		{"etc", filepath.Join(string(PathSeparator), "etc")},
+		{"up", filepath.Join("..", "out")},
+		{"loop", "loop"},
+	} {
+		if err := Symlink(l.target, filepath.Join(dir, l.name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRootOpen(t *testing.T) {
+	dir := makeRootTree(t)
+	root, err := OpenRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+	if got := root.Name(); got != dir {
+		t.Errorf("root.Name() = %q, want %q", got, dir)
+	}
+
+	for _, name := range []string{
+		"a/b",
+		"./a/./b",
+		"a/../a/b",
+		"inner",
+		"dirlink/b",
+		"dirlink/../dirlink/b",
+	} {
+		f, err := root.Open(name)
+		if err != nil {
+			t.Errorf("root.Open(%q): %v", name, err)
+			continue
+		}
+		b, err := io.ReadAll(f)
+		f.Close()
+		if err != nil || string(b) != "b" {
+			t.Errorf("root.Open(%q): read %q, %v; want %q", name, b, err, "b")
+		}
+	}
+
+	for _, name := range []string{
+		"..",
+		"../out",
+		"a/../../out",
+		"dirlink/../../out",
+		"/etc/passwd",
+		"etc",
+		"etc/passwd",
+		"up",
+	} {
+		f, err := root.Open(name)
+		if err == nil {
+			f.Close()
+			t.Errorf("root.Open(%q) succeeded, want error", name)
+			continue
+		}
+		if !errors.Is(err, ErrPathEscapes) {
+			t.Errorf("root.Open(%q) = %v, want %v", name, err, ErrPathEscapes)
+		}
+		var pe *PathError
+		if !errors.As(err, &pe) || pe.Path != name {
+			t.Errorf("root.Open(%q) = %v, want *PathError for %q", name, err, name)
+		}
+	}
+
+	if f, err := root.Open("loop"); err == nil {
+		f.Close()
+		t.Errorf("root.Open(%q) succeeded, want error", "loop")
+	}
+	if f, err := root.Open("a/missing"); !IsNotExist(err) {
+		if err == nil {
+			f.Close()
+		}
+		t.Errorf("root.Open(%q) = %v, want not-exist error", "a/missing", err)
+	}
+}
+
+func TestOpenInRoot(t *testing.T) {
+	dir := makeRootTree(t)
+	f, err := OpenInRoot(dir, "dirlink/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if f, err := OpenInRoot(dir, "etc/passwd"); err == nil {
+		f.Close()
+		t.Errorf("OpenInRoot(%q, %q) succeeded, want error", dir, "etc/passwd")
+	}
+	if _, err := OpenInRoot(filepath.Join(dir, "a", "b"), "x"); err == nil {
+		t.Errorf("OpenInRoot on a regular file succeeded, want error")
+	}
+}
+
+func TestRootCreateStatMkdirRemove(t *testing.T) {
+	dir := makeRootTree(t)
+	root, err := OpenRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+
+	if err := root.Mkdir("dirlink/sub", 0o777); err != nil {
+		t.Fatal(err)
+	}
+	f, err := root.Create("a/sub/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("hello"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ReadFile(filepath.Join(dir, "a", "sub", "file"))
+	if err != nil || string(b) != "hello" {
+		t.Fatalf("ReadFile = %q, %v; want %q", b, err, "hello")
+	}
+
+	fi, err := root.Stat("a/sub/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Size() != 5 || !fi.Mode().IsRegular() {
+		t.Errorf("root.Stat: size %d, mode %v; want 5-byte regular file", fi.Size(), fi.Mode())
+	}
+	fi, err = root.Stat("dirlink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.IsDir() {
+		t.Errorf("root.Stat(%q) is not a directory: mode %v", "dirlink", fi.Mode())
+	}
+	if _, err := root.Stat("etc"); !errors.Is(err, ErrPathEscapes) {
+		t.Errorf("root.Stat(%q) = %v, want %v", "etc", err, ErrPathEscapes)
+	}
+
+	if err := root.Mkdir("../escape", 0o777); !errors.Is(err, ErrPathEscapes) {
+		t.Errorf("root.Mkdir(%q) = %v, want %v", "../escape", err, ErrPathEscapes)
+	}
+	if _, err := Stat(filepath.Join(dir, "..", "escape")); err == nil {
+		t.Errorf("root.Mkdir created a directory outside the root")
+	}
+	if f, err := root.Create("up"); err == nil {
+		f.Close()
+		t.Errorf("root.Create(%q) succeeded, want error", "up")
+	}
+	if b, err := ReadFile(filepath.Join(dir, "..", "out")); err != nil || string(b) != "out" {
+		t.Errorf("file outside the root changed: %q, %v", b, err)
+	}
+
+	if err := root.Remove("a/sub"); err == nil {
+		t.Errorf("root.Remove of non-empty directory succeeded")
+	}
+	if err := root.Remove("a/sub/file"); err != nil {
+		t.Fatal(err)
+	}
+	if err := root.Remove("a/sub"); err != nil {
+		t.Fatal(err)
+	}
+	// Removing a symlink removes the link, not its target.
+	if err := root.Remove("etc"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Lstat(filepath.Join(dir, "etc")); !IsNotExist(err) {
+		t.Errorf("symlink still present after root.Remove: %v", err)
+	}
+	if err := root.Remove("../out"); !errors.Is(err, ErrPathEscapes) {
+		t.Errorf("root.Remove(%q) = %v, want %v", "../out", err, ErrPathEscapes)
+	}
+}
+
+func TestRootClosed(t *testing.T) {
+	root, err := OpenRoot(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := root.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := root.Open("."); !errors.Is(err, ErrClosed) {
+		t.Errorf("root.Open after Close = %v, want %v", err, ErrClosed)
+	}
+	if err := root.Mkdir("x", 0o777); !errors.Is(err, ErrClosed) {
+		t.Errorf("root.Mkdir after Close = %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestRootOpenDot(t *testing.T) {
+	dir := t.TempDir()
+	root, err := OpenRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+	if err := root.Mkdir("a", 0o777); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{".", "./", "a/.."} {
+		f, err := root.Open(name)
+		if err != nil {
+			t.Errorf("root.Open(%q): %v", name, err)
+			continue
+		}
+		fi, err := f.Stat()
+		f.Close()
+		if err != nil || !fi.IsDir() {
+			t.Errorf("root.Open(%q).Stat() = %v, %v; want directory", name, fi, err)
+		}
+	}
+	if _, err := root.Open(""); err == nil {
+		t.Errorf("root.Open(%q) succeeded, want error", "")
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package os
+
+import (
+	"errors"
+	"internal/syscall/unix"
+	"runtime"
+	"slices"
+	"sync"
+	"syscall"
+)
+
+// root implementation for platforms with a function to open a file
+// relative to a directory.
+type root struct {
+	name string
+
+	// refs is incremented while an operation is using fd.
+	// closed is set when Close is called.
+	// fd is closed when closed is true and refs is 0.
+	mu     sync.Mutex
+	fd     int
+	refs   int
+	closed bool
+}
+
+func (r *root) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.closed && r.refs == 0 {
+		syscall.Close(r.fd)
+	}
+	r.closed = true
+	runtime.SetFinalizer(r, nil) // no need for a finalizer any more
+	return nil
+}
+
+func (r *root) incref() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return ErrClosed
+	}
+	r.refs++
+	return nil
+}
+
+func (r *root) decref() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.refs <= 0 {
+		panic("bad Root refcount")
+	}
+	r.refs--
+	if r.closed && r.refs == 0 {
+		syscall.Close(r.fd)
+	}
+}
+
+func (r *root) Name() string {
+	return r.name
+}
+
+// openRootNolog is OpenRoot.
+func openRootNolog(name string) (*Root, error) {
+	var fd int
+	err := ignoringEINTR(func() error {
+		var err error
+		fd, _, err = open(name, syscall.O_CLOEXEC|syscall.O_DIRECTORY, 0)
+		return err
+	})
+	if err != nil {
+		return nil, &PathError{Op: "openat", Path: name, Err: err}
+	}
+	if !supportsCloseOnExec {
+		syscall.CloseOnExec(fd)
+	}
+	r := &root{name: name, fd: fd}
+	runtime.SetFinalizer(r, (*root).Close)
+	return &Root{r}, nil
+}
+
+// errNoOpenat2 is returned by openat2InRoot when the caller
+// must resolve the name one component at a time.
+var errNoOpenat2 = errors.New("openat2 not available")
+
+// rootOpenFileNolog is Root.OpenFile.
+func rootOpenFileNolog(root *Root, name string, flag int, perm FileMode) (*File, error) {
+	fd, err := openat2InRoot(root, name, flag, perm)
+	if err == errNoOpenat2 {
+		fd, err = doInRoot(root, name, func(parent int, name string) (fd int, err error) {
+			ignoringEINTR(func() error {
+				fd, err = unix.Openat(parent, name, syscall.O_NOFOLLOW|syscall.O_CLOEXEC|flag, uint32(syscallMode(perm)))
+				return err
+			})
+			if err != nil && flag&O_EXCL == 0 && flag&syscall.O_NOFOLLOW == 0 {
+				// If the final component is a symlink, follow it.
+				err = checkSymlink(parent, name, err)
+			}
+			return fd, err
+		})
+	}
+	if err != nil {
+		return nil, &PathError{Op: "openat", Path: name, Err: err}
+	}
+	if !supportsCloseOnExec {
+		syscall.CloseOnExec(fd)
+	}
+	f := newFile(fd, joinPath(root.Name(), name), kindOpenFile, unix.HasNonblockFlag(flag))
+	return f, nil
+}
+
+// rootStat is Root.Stat.
+func rootStat(r *Root, name string) (FileInfo, error) {
+	fi, err := doInRoot(r, name, func(parent int, n string) (FileInfo, error) {
+		var fs fileStat
+		if err := ignoringEINTR(func() error {
+			return unix.Fstatat(parent, n, &fs.sys, unix.AT_SYMLINK_NOFOLLOW)
+		}); err != nil {
+			return nil, err
+		}
+		fillFileStatFromSys(&fs, n)
+		if fs.Mode()&ModeSymlink != 0 {
+			// Follow the symlink, and stat its target instead.
+			if err := checkSymlink(parent, n, nil); err != nil {
+				return nil, err
+			}
+		}
+		return &fs, nil
+	})
+	if err != nil {
+		return nil, &PathError{Op: "statat", Path: name, Err: err}
+	}
+	return fi, nil
+}
+
+// rootMkdir is Root.Mkdir.
+func rootMkdir(r *Root, name string, perm FileMode) error {
+	_, err := doInRoot(r, name, func(parent int, name string) (struct{}, error) {
+		return struct{}{}, ignoringEINTR(func() error {
+			return unix.Mkdirat(parent, name, uint32(syscallMode(perm)))
+		})
+	})
+	if err != nil {
+		return &PathError{Op: "mkdirat", Path: name, Err: err}
+	}
+	return nil
+}
+
+// rootRemove is Root.Remove.
+func rootRemove(r *Root, name string) error {
+	_, err := doInRoot(r, name, func(parent int, name string) (struct{}, error) {
+		// See the comment in Remove for why both calls are made.
+		e := ignoringEINTR(func() error {
+			return unix.Unlinkat(parent, name, 0)
+		})
+		if e == nil {
+			return struct{}{}, nil
+		}
+		e1 := ignoringEINTR(func() error {
+			return unix.Unlinkat(parent, name, unix.AT_REMOVEDIR)
+		})
+		if e1 == nil {
+			return struct{}{}, nil
+		}
+		if e1 != syscall.ENOTDIR {
+			e = e1
+		}
+		return struct{}{}, e
+	})
+	if err != nil {
+		return &PathError{Op: "removeat", Path: name, Err: err}
+	}
+	return nil
+}
+
+// errSymlink reports that the file being operated on is actually a symlink,
+// and the target of that symlink.
+type errSymlink string
+
+func (errSymlink) Error() string { panic("errSymlink is not user-visible") }
+
+// checkSymlink resolves the symlink name in parent,
+// and returns errSymlink with the link contents.
+//
+// If name is not a symlink, return origError.
+func checkSymlink(parent int, name string, origError error) error {
+	link, err := readlinkat(parent, name)
+	if err != nil {
+		return origError
+	}
+	return errSymlink(link)
+}
+
+func readlinkat(fd int, name string) (string, error) {
+	return readlinkBuf(func(b []byte) (int, error) {
+		return unix.Readlinkat(fd, name, b)
+	})
+}
+
+// rootOpenDir opens the directory name in parent
+// without following a symlink in its final component.
+func rootOpenDir(parent int, name string) (fd int, err error) {
+	ignoringEINTR(func() error {
+		fd, err = unix.Openat(parent, name, syscall.O_RDONLY|syscall.O_CLOEXEC|syscall.O_DIRECTORY|syscall.O_NOFOLLOW, 0)
+		return err
+	})
+	if err != nil {
+		// ENOTDIR or ELOOP (or, on some systems, EMLINK or EFTYPE)
+		// may indicate that name is a symlink.
+		err = checkSymlink(parent, name, err)
+	}
+	return fd, err
+}
+
+// doInRoot performs an operation on a path in a Root.
+//
+// It opens the directory containing the final element of the path,
+// and calls f with the directory fd and name of the final element.
+//
+// If the path refers to a symlink which should be followed,
+// then f must return an errSymlink.
+// doInRoot will follow the symlink and call f again.
+func doInRoot[T any](r *Root, name string, f func(parent int, name string) (T, error)) (ret T, err error) {
+	if err := r.root.incref(); err != nil {
+		return ret, err
+	}
+	defer r.root.decref()
+
+	parts, err := splitPathInRoot(name, nil, nil)
+	if err != nil {
+		return ret, err
+	}
+
+	rootfd := r.root.fd
+	dirfd := rootfd
+	defer func() {
+		if dirfd != rootfd {
+			syscall.Close(dirfd)
+		}
+	}()
+
+	// When resolving .. path components, we restart path resolution from the root.
+	// We do this to avoid needing to retain the fds of more than one directory
+	// at a time, and to make sure that a directory renamed out from under us
+	// cannot let us escape the root.
+	//
+	// i is the index of the path component being resolved.
+	// parts[:i] have been resolved and dirfd refers to their contents.
+	i := 0
+	symlinks := 0
+	for {
+		if len(parts) == 0 {
+			// The path refers to the root itself.
+			parts = []string{"."}
+		}
+		if parts[i] == ".." {
+			// Resolve one level of parent directory.
+			if i == 0 {
+				// This is the root directory; we can't go up any further.
+				return ret, errPathEscapes
+			}
+			parts = slices.Delete(parts, i-1, i+1)
+			i = 0
+			if dirfd != rootfd {
+				syscall.Close(dirfd)
+			}
+			dirfd = rootfd
+			continue
+		}
+
+		if i == len(parts)-1 {
+			// This is the last path element.
+			// Call f to decide what to do with it.
+			ret, err = f(dirfd, parts[i])
+			if _, ok := err.(errSymlink); !ok {
+				return ret, err
+			}
+		} else {
+			var fd int
+			fd, err = rootOpenDir(dirfd, parts[i])
+			if err == nil {
+				if dirfd != rootfd {
+					syscall.Close(dirfd)
+				}
+				dirfd = fd
+			} else if _, ok := err.(errSymlink); !ok {
+				return ret, err
+			}
+		}
+
+		if e, ok := err.(errSymlink); ok {
+			symlinks++
+			if symlinks > rootMaxSymlinks {
+				return ret, syscall.ELOOP
+			}
+			// Replace the symlink with its target, which is
+			// resolved relative to the directory containing it.
+			newparts, err := splitPathInRoot(string(e), parts[:i:i], parts[i+1:])
+			if err != nil {
+				return ret, err
+			}
+			parts = newparts
+			continue
+		}
+
+		i++
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// rootIsLink reports whether rootResolve must follow fi as a link.
+// Besides symbolic links, this includes the other name surrogate
+// reparse points, such as directory junctions, which Windows follows
+// when opening a path through them even though Lstat does not report
+// them as ModeSymlink.
+func rootIsLink(fi FileInfo) bool {
+	if fi.Mode()&ModeSymlink != 0 {
+		return true
+	}
+	fs, ok := fi.(*fileStat)
+	return ok && fs.isReparseTagNameSurrogate()
+}
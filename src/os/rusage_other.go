@@ -0,0 +1,15 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !unix
+
+package os
+
+func (p *ProcessState) maxRSS() int64 { return 0 }
+
+func (p *ProcessState) minorPageFaults() int64 { return 0 }
+
+func (p *ProcessState) majorPageFaults() int64 { return 0 }
+
+func (p *ProcessState) contextSwitches() (voluntary, involuntary int64) { return 0, 0 }
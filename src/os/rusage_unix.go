@@ -0,0 +1,47 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package os
+
+import "runtime"
+
+func (p *ProcessState) maxRSS() int64 {
+	if p == nil || p.rusage == nil {
+		return 0
+	}
+	rss := int64(p.rusage.Maxrss)
+	switch runtime.GOOS {
+	case "darwin", "ios":
+		// Already in bytes.
+		return rss
+	case "solaris", "illumos":
+		// In pages.
+		return rss * int64(Getpagesize())
+	}
+	// In kilobytes.
+	return rss * 1024
+}
+
+func (p *ProcessState) minorPageFaults() int64 {
+	if p == nil || p.rusage == nil {
+		return 0
+	}
+	return int64(p.rusage.Minflt)
+}
+
+func (p *ProcessState) majorPageFaults() int64 {
+	if p == nil || p.rusage == nil {
+		return 0
+	}
+	return int64(p.rusage.Majflt)
+}
+
+func (p *ProcessState) contextSwitches() (voluntary, involuntary int64) {
+	if p == nil || p.rusage == nil {
+		return 0, 0
+	}
+	return int64(p.rusage.Nvcsw), int64(p.rusage.Nivcsw)
+}
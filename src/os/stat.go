@@ -4,7 +4,13 @@
 
 package os
 
-import "internal/testlog"
+import (
+	"internal/testlog"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 // Stat returns a [FileInfo] describing the named file.
 // If there is an error, it will be of type [*PathError].
@@ -13,6 +19,21 @@ func Stat(name string) (FileInfo, error) {
 	return statNolog(name)
 }
 
+// StatACL is like [Stat], but on Windows the permission bits of the
+// returned [FileInfo] are derived from the access control list of the
+// file, as set by [ChmodACL], rather than only from its read-only
+// attribute. The owner, group and other bits reflect the access granted
+// to the file's owner, its primary group and the Everyone group by
+// entries naming them directly or naming Everyone; membership in other
+// groups is not taken into account. If the file system does not
+// support access control lists, the bits are those reported by Stat.
+//
+// On other systems, StatACL is the same as Stat.
+// If there is an error, it will be of type [*PathError].
+func StatACL(name string) (FileInfo, error) {
+	return statACL(name)
+}
+
 // Lstat returns a [FileInfo] describing the named file.
 // If the file is a symbolic link, the returned FileInfo
 // describes the symbolic link. Lstat makes no attempt to follow the link.
@@ -25,3 +46,83 @@ func Lstat(name string) (FileInfo, error) {
 	testlog.Stat(name)
 	return lstatNolog(name)
 }
+
+// StatOpt returns a [FileInfo] describing the named file. If the file
+// is a symbolic link, StatOpt follows it as [Stat] does if followSymlinks
+// is true, and describes the link itself as [Lstat] does otherwise.
+// Either way, the file is examined with a single system call, such as
+// fstatat(2) with or without AT_SYMLINK_NOFOLLOW on Unix systems.
+// If there is an error, it will be of type [*PathError].
+func StatOpt(name string, followSymlinks bool) (FileInfo, error) {
+	testlog.Stat(name)
+	if followSymlinks {
+		return statNolog(name)
+	}
+	return lstatNolog(name)
+}
+
+// StatTimes returns the access, modification, status change, and birth
+// (creation) times of the file described by fi, which should have been
+// returned by [Stat], [Lstat], [File.Stat], [File.StatAt], or [DirEntry.Info].
+// Times the platform does not record are returned as the zero [time.Time],
+// and ok reports whether all four times are available.
+//
+// The birth time is available on Darwin, FreeBSD, NetBSD, and Windows,
+// and on Linux when the kernel and file system support statx with STATX_BTIME.
+// Windows and Plan 9 do not record a status change time.
+func StatTimes(fi FileInfo) (atime, mtime, ctime, btime time.Time, ok bool) {
+	atime, ctime, btime = statTimes(fi)
+	mtime = fi.ModTime()
+	ok = !atime.IsZero() && !ctime.IsZero() && !btime.IsZero()
+	return atime, mtime, ctime, btime, ok
+}
+
+// statManyBatch is the number of names a StatMany worker
+// claims at a time.
+const statManyBatch = 64
+
+// StatMany returns a [FileInfo] describing each of the named files,
+// as if by calling [Stat] on each name.
+// Both results have the same length as names, and the results for
+// names[i] are in infos[i] and errs[i], regardless of the order in which
+// the files are examined.
+// For each i, exactly one of infos[i] and errs[i] is non-nil.
+// If errs[i] is non-nil, it will be of type [*PathError].
+//
+// StatMany may examine several files concurrently.
+func StatMany(names []string) (infos []FileInfo, errs []error) {
+	infos = make([]FileInfo, len(names))
+	errs = make([]error, len(names))
+	statRange := func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			infos[i], errs[i] = Stat(names[i])
+		}
+	}
+
+	workers := min(runtime.GOMAXPROCS(0), len(names)/statManyBatch)
+	if workers <= 1 {
+		statRange(0, len(names))
+		return infos, errs
+	}
+
+	var (
+		wg   sync.WaitGroup
+		next atomic.Int64
+	)
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			for {
+				hi := int(next.Add(statManyBatch))
+				lo := hi - statManyBatch
+				if lo >= len(names) {
+					return
+				}
+				statRange(lo, min(hi, len(names)))
+			}
+		}()
+	}
+	wg.Wait()
+	return infos, errs
+}
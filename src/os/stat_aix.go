@@ -50,3 +50,11 @@ func stTimespecToTime(ts syscall.StTimespec_t) time.Time {
 func atime(fi FileInfo) time.Time {
 	return stTimespecToTime(fi.Sys().(*syscall.Stat_t).Atim)
 }
+
+func statTimes(fi FileInfo) (atime, ctime, btime time.Time) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return
+	}
+	return stTimespecToTime(st.Atim), stTimespecToTime(st.Ctim), time.Time{}
+}
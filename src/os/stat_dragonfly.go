@@ -46,3 +46,11 @@ func fillFileStatFromSys(fs *fileStat, name string) {
 func atime(fi FileInfo) time.Time {
 	return time.Unix(fi.Sys().(*syscall.Stat_t).Atim.Unix())
 }
+
+func statTimes(fi FileInfo) (atime, ctime, btime time.Time) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return
+	}
+	return time.Unix(st.Atim.Unix()), time.Unix(st.Ctim.Unix()), time.Time{}
+}
@@ -49,3 +49,11 @@ func atime(fi FileInfo) time.Time {
 	st := fi.Sys().(*syscall.Stat_t)
 	return time.Unix(st.Atime, st.AtimeNsec)
 }
+
+func statTimes(fi FileInfo) (atime, ctime, btime time.Time) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return
+	}
+	return time.Unix(st.Atime, st.AtimeNsec), time.Unix(st.Ctime, st.CtimeNsec), time.Time{}
+}
@@ -46,3 +46,15 @@ func fillFileStatFromSys(fs *fileStat, name string) {
 func atime(fi FileInfo) time.Time {
 	return time.Unix(fi.Sys().(*syscall.Stat_t).Atim.Unix())
 }
+
+func statTimes(fi FileInfo) (atime, ctime, btime time.Time) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return
+	}
+	if fs, ok := fi.(*fileStat); ok {
+		// syscall.Stat_t has no birth time; it comes from statx.
+		btime = fs.btime
+	}
+	return time.Unix(st.Atim.Unix()), time.Unix(st.Ctim.Unix()), btime
+}
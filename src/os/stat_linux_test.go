@@ -0,0 +1,87 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	. "os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// TestStatxMatchesStat checks that the syscall.Stat_t filled in from statx
+// matches the one reported by the stat family of system calls.
+func TestStatxMatchesStat(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := WriteFile(file, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := Symlink("file", link); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		stat func(string) (FileInfo, error)
+	}{
+		{file, Stat},
+		{dir, Stat},
+		{link, Lstat},
+		{"/dev/null", Stat},
+		{file, func(name string) (FileInfo, error) {
+			f, err := Open(name)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			return f.Stat()
+		}},
+		{link, func(name string) (FileInfo, error) {
+			d, err := Open(filepath.Dir(name))
+			if err != nil {
+				return nil, err
+			}
+			defer d.Close()
+			return d.StatAt(filepath.Base(name), false)
+		}},
+	}
+	for _, tt := range tests {
+		fi, err := tt.stat(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		old := StatxUnsupported.Load()
+		StatxUnsupported.Store(true)
+		want, err := tt.stat(tt.name)
+		StatxUnsupported.Store(old)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		st, wst := fi.Sys().(*syscall.Stat_t), want.Sys().(*syscall.Stat_t)
+		if st.Dev != wst.Dev || st.Ino != wst.Ino || st.Nlink != wst.Nlink ||
+			st.Mode != wst.Mode || st.Uid != wst.Uid || st.Gid != wst.Gid ||
+			st.Rdev != wst.Rdev || st.Size != wst.Size || st.Blksize != wst.Blksize ||
+			st.Blocks != wst.Blocks || st.Atim != wst.Atim || st.Mtim != wst.Mtim ||
+			st.Ctim != wst.Ctim {
+			t.Errorf("%s: statx result\n%+v\ndiffers from stat result\n%+v", tt.name, *st, *wst)
+		}
+		if fi.Mode() != want.Mode() || !fi.ModTime().Equal(want.ModTime()) {
+			t.Errorf("%s: mode %v, mtime %v; want %v, %v", tt.name, fi.Mode(), fi.ModTime(), want.Mode(), want.ModTime())
+		}
+		if fi.Size() != want.Size() || fi.Name() != want.Name() {
+			t.Errorf("%s: size %d, name %q; want %d, %q", tt.name, fi.Size(), fi.Name(), want.Size(), want.Name())
+		}
+		// Without statx there is no birth time to report.
+		if _, _, _, btime, _ := StatTimes(want); !btime.IsZero() {
+			t.Errorf("%s: btime %v without statx, want zero time", tt.name, btime)
+		}
+		if _, _, _, btime, _ := StatTimes(fi); btime.IsZero() {
+			t.Logf("%s: btime not reported by this kernel or file system", tt.name)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build (unix && !linux) || (js && wasm) || wasip1
+
+package os
+
+import "syscall"
+
+func statFile(name string, fs *fileStat) error {
+	return syscall.Stat(name, &fs.sys)
+}
+
+func lstatFile(name string, fs *fileStat) error {
+	return syscall.Lstat(name, &fs.sys)
+}
+
+func fstatFile(f *File, fs *fileStat) error {
+	return f.pfd.Fstat(&fs.sys)
+}
@@ -112,3 +112,11 @@ func lstatNolog(name string) (FileInfo, error) {
 func atime(fi FileInfo) time.Time {
 	return time.Unix(int64(fi.Sys().(*syscall.Dir).Atime), 0)
 }
+
+func statTimes(fi FileInfo) (atime, ctime, btime time.Time) {
+	d, ok := fi.Sys().(*syscall.Dir)
+	if !ok {
+		return
+	}
+	return time.Unix(int64(d.Atime), 0), time.Time{}, time.Time{}
+}
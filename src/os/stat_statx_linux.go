@@ -0,0 +1,111 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// statxUnsupported is set when statx is unavailable, either because
+// the kernel predates Linux 4.11 or because a seccomp filter rejects it.
+// The stat family of system calls is used instead, which cannot
+// report the birth time of a file.
+var statxUnsupported atomic.Bool
+
+func statFile(name string, fs *fileStat) error {
+	if handled, err := statx(unix.AT_FDCWD, name, 0, fs); handled {
+		return err
+	}
+	return syscall.Stat(name, &fs.sys)
+}
+
+func lstatFile(name string, fs *fileStat) error {
+	if handled, err := statx(unix.AT_FDCWD, name, unix.AT_SYMLINK_NOFOLLOW, fs); handled {
+		return err
+	}
+	return syscall.Lstat(name, &fs.sys)
+}
+
+func fstatatFile(dirfd int, name string, fs *fileStat, flags int) error {
+	if handled, err := statx(dirfd, name, flags, fs); handled {
+		return err
+	}
+	return unix.Fstatat(dirfd, name, &fs.sys, flags)
+}
+
+func fstatFile(f *File, fs *fileStat) error {
+	if !statxUnsupported.Load() {
+		var (
+			err     error
+			handled bool
+		)
+		cerr := f.pfd.RawControl(func(fd uintptr) {
+			handled, err = statx(int(fd), "", unix.AT_EMPTY_PATH, fs)
+		})
+		runtime.KeepAlive(f)
+		if cerr != nil {
+			return cerr
+		}
+		if handled {
+			return err
+		}
+	}
+	return f.pfd.Fstat(&fs.sys)
+}
+
+// statx fills in fs using the statx system call.
+// It reports whether statx was available.
+func statx(dirfd int, name string, flags int, fs *fileStat) (handled bool, err error) {
+	if statxUnsupported.Load() {
+		return false, nil
+	}
+	var sx unix.Statx_t
+	// Do it the glibc way, add AT_NO_AUTOMOUNT.
+	err = unix.Statx(dirfd, name, flags|unix.AT_NO_AUTOMOUNT, unix.STATX_BASIC_STATS|unix.STATX_BTIME, &sx)
+	switch err {
+	case nil:
+	case syscall.ENOSYS, syscall.EPERM:
+		statxUnsupported.Store(true)
+		return false, nil
+	default:
+		return true, err
+	}
+
+	st := &fs.sys
+	setStatField(&st.Dev, unix.Mkdev(sx.Dev_major, sx.Dev_minor))
+	st.Ino = sx.Ino
+	st.Mode = uint32(sx.Mode)
+	setStatField(&st.Nlink, uint64(sx.Nlink))
+	st.Uid = sx.Uid
+	st.Gid = sx.Gid
+	setStatField(&st.Rdev, unix.Mkdev(sx.Rdev_major, sx.Rdev_minor))
+	st.Size = int64(sx.Size)
+	setStatField(&st.Blksize, uint64(sx.Blksize))
+	st.Blocks = int64(sx.Blocks)
+	st.Atim = timespecFromStatx(sx.Atime)
+	st.Mtim = timespecFromStatx(sx.Mtime)
+	st.Ctim = timespecFromStatx(sx.Ctime)
+	if sx.Mask&unix.STATX_BTIME != 0 {
+		fs.btime = time.Unix(sx.Btime.Sec, int64(sx.Btime.Nsec))
+	}
+	return true, nil
+}
+
+func timespecFromStatx(ts unix.StatxTimestamp) syscall.Timespec {
+	var t syscall.Timespec
+	setStatField(&t.Sec, uint64(ts.Sec))
+	setStatField(&t.Nsec, uint64(ts.Nsec))
+	return t
+}
+
+// setStatField sets a field of syscall.Stat_t,
+// whose integer types vary by architecture.
+func setStatField[T ~int32 | ~uint32 | ~int64 | ~uint64](p *T, v uint64) {
+	*p = T(v)
+}
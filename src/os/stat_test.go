@@ -11,7 +11,9 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"testing"
+	"time"
 )
 
 type testStatAndLstatParams struct {
@@ -361,3 +363,137 @@ func TestClosedStat(t *testing.T) {
 		t.Errorf("error from Stat on closed file did not match ErrClosed: %q, type %T", err, err)
 	}
 }
+
+func TestStatMany(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	var names []string
+	for i := range 1000 {
+		name := filepath.Join(dir, strconv.Itoa(i))
+		if i%3 != 0 {
+			if err := os.WriteFile(name, make([]byte, i), 0o666); err != nil {
+				t.Fatal(err)
+			}
+		}
+		names = append(names, name)
+	}
+
+	infos, errs := os.StatMany(names)
+	if len(infos) != len(names) || len(errs) != len(names) {
+		t.Fatalf("StatMany returned %d infos and %d errors for %d names", len(infos), len(errs), len(names))
+	}
+	for i, name := range names {
+		if i%3 == 0 {
+			if infos[i] != nil || !errors.Is(errs[i], fs.ErrNotExist) {
+				t.Errorf("StatMany result %d for missing file = %v, %v; want nil, not-exist error", i, infos[i], errs[i])
+			}
+			var pe *fs.PathError
+			if !errors.As(errs[i], &pe) || pe.Path != name {
+				t.Errorf("StatMany error %d = %v, want *PathError for %q", i, errs[i], name)
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Errorf("StatMany error %d: %v", i, errs[i])
+			continue
+		}
+		if infos[i].Name() != filepath.Base(name) || infos[i].Size() != int64(i) {
+			t.Errorf("StatMany result %d = %q size %d, want %q size %d", i, infos[i].Name(), infos[i].Size(), filepath.Base(name), i)
+		}
+	}
+
+	infos, errs = os.StatMany(nil)
+	if len(infos) != 0 || len(errs) != 0 {
+		t.Errorf("StatMany(nil) = %v, %v; want empty results", infos, errs)
+	}
+}
+
+func statManyNames(b *testing.B) []string {
+	dir := b.TempDir()
+	names := make([]string, 10000)
+	for i := range names {
+		names[i] = filepath.Join(dir, strconv.Itoa(i))
+		if err := os.WriteFile(names[i], nil, 0o666); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return names
+}
+
+func BenchmarkStatMany(b *testing.B) {
+	names := statManyNames(b)
+	b.ResetTimer()
+	for range b.N {
+		if _, errs := os.StatMany(names); errs[0] != nil {
+			b.Fatal(errs[0])
+		}
+	}
+}
+
+func BenchmarkStatLoop(b *testing.B) {
+	names := statManyNames(b)
+	b.ResetTimer()
+	for range b.N {
+		for _, name := range names {
+			if _, err := os.Stat(name); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestStatTimes(t *testing.T) {
+	t.Parallel()
+	name := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(name, []byte("hello"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	before := time.Now().Add(-time.Minute)
+	atime := time.Date(2020, time.March, 1, 2, 3, 4, 0, time.UTC)
+	mtime := time.Date(2021, time.April, 5, 6, 7, 8, 0, time.UTC)
+	if err := os.Chtimes(name, atime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotA, gotM, gotC, gotB, ok := os.StatTimes(fi)
+	if !gotM.Equal(mtime) {
+		t.Errorf("mtime = %v, want %v", gotM, mtime)
+	}
+	if runtime.GOOS != "plan9" && !gotA.Equal(atime) {
+		t.Errorf("atime = %v, want %v", gotA, atime)
+	}
+	switch runtime.GOOS {
+	case "windows", "plan9":
+		if !gotC.IsZero() {
+			t.Errorf("ctime = %v, want zero time", gotC)
+		}
+	default:
+		if gotC.Before(before) {
+			t.Errorf("ctime = %v, want after %v", gotC, before)
+		}
+	}
+	switch runtime.GOOS {
+	case "darwin", "freebsd", "netbsd", "windows":
+		if gotB.IsZero() {
+			t.Errorf("btime is zero, want file creation time")
+		}
+	case "linux":
+		if gotB.IsZero() {
+			t.Logf("btime not reported by this kernel or file system")
+		}
+	default:
+		if !gotB.IsZero() {
+			t.Errorf("btime = %v, want zero time", gotB)
+		}
+	}
+	if !gotB.IsZero() && gotB.Before(before) {
+		t.Errorf("btime = %v, want after %v", gotB, before)
+	}
+	if want := !gotA.IsZero() && !gotC.IsZero() && !gotB.IsZero(); ok != want {
+		t.Errorf("ok = %v, want %v", ok, want)
+	}
+}
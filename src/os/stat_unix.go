@@ -6,10 +6,6 @@
 
 package os
 
-import (
-	"syscall"
-)
-
 // Stat returns the [FileInfo] structure describing file.
 // If there is an error, it will be of type [*PathError].
 func (f *File) Stat() (FileInfo, error) {
@@ -17,7 +13,7 @@ func (f *File) Stat() (FileInfo, error) {
 		return nil, ErrInvalid
 	}
 	var fs fileStat
-	err := f.pfd.Fstat(&fs.sys)
+	err := fstatFile(f, &fs)
 	if err != nil {
 		return nil, f.wrapErr("stat", err)
 	}
@@ -29,7 +25,7 @@ func (f *File) Stat() (FileInfo, error) {
 func statNolog(name string) (FileInfo, error) {
 	var fs fileStat
 	err := ignoringEINTR(func() error {
-		return syscall.Stat(name, &fs.sys)
+		return statFile(name, &fs)
 	})
 	if err != nil {
 		return nil, &PathError{Op: "stat", Path: name, Err: err}
@@ -42,7 +38,7 @@ func statNolog(name string) (FileInfo, error) {
 func lstatNolog(name string) (FileInfo, error) {
 	var fs fileStat
 	err := ignoringEINTR(func() error {
-		return syscall.Lstat(name, &fs.sys)
+		return lstatFile(name, &fs)
 	})
 	if err != nil {
 		return nil, &PathError{Op: "lstat", Path: name, Err: err}
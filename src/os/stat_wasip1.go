@@ -39,3 +39,11 @@ func atime(fi FileInfo) time.Time {
 	st := fi.Sys().(*syscall.Stat_t)
 	return time.Unix(0, int64(st.Atime))
 }
+
+func statTimes(fi FileInfo) (atime, ctime, btime time.Time) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return
+	}
+	return time.Unix(0, int64(st.Atime)), time.Unix(0, int64(st.Ctime)), time.Time{}
+}
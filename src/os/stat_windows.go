@@ -8,6 +8,7 @@ import (
 	"internal/filepathlite"
 	"internal/syscall/windows"
 	"syscall"
+	"time"
 	"unsafe"
 )
 
@@ -136,3 +137,12 @@ func lstatNolog(name string) (FileInfo, error) {
 	}
 	return stat("Lstat", name, followSurrogates)
 }
+
+func statTimes(fi FileInfo) (atime, ctime, btime time.Time) {
+	d, ok := fi.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return
+	}
+	// Windows does not record the time of the last status change.
+	return time.Unix(0, d.LastAccessTime.Nanoseconds()), time.Time{}, time.Unix(0, d.CreationTime.Nanoseconds())
+}
@@ -0,0 +1,23 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "syscall"
+
+// reopenStdin opens the pipe or terminal on the standard input again
+// through /proc/self/fd/0 for stdinPollable. Unlike a duplicate, the
+// new descriptor has an open file description of its own, so putting
+// it into non-blocking mode does not affect Stdin, or any other
+// process reading from the same pipe or terminal. O_NONBLOCK keeps the
+// open of a pipe with no writers from blocking. It reports false if
+// the open fails, as it does when /proc is not mounted.
+func reopenStdin() (int, bool) {
+	var fd int
+	err := ignoringEINTR(func() (err error) {
+		fd, err = syscall.Open("/proc/self/fd/0", syscall.O_RDONLY|syscall.O_NONBLOCK|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+		return err
+	})
+	return fd, err == nil
+}
@@ -0,0 +1,13 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build (unix && !linux) || (js && wasm) || wasip1
+
+package os
+
+// reopenStdin is only implemented on Linux, where /proc/self/fd/0
+// opens the standard input again.
+func reopenStdin() (int, bool) {
+	return -1, false
+}
@@ -0,0 +1,19 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+
+package os
+
+import "errors"
+
+// symlinkAs creates newname as a symbolic link to oldname. Symbolic
+// links do not have a type outside Windows, so isdir is ignored.
+func symlinkAs(oldname, newname string, isdir bool) error {
+	return Symlink(oldname, newname)
+}
+
+func reparsePointTarget(name string) (string, uint32, error) {
+	return "", 0, &PathError{Op: "readlink", Path: name, Err: errors.ErrUnsupported}
+}
@@ -0,0 +1,13 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package os
+
+import "errors"
+
+func (f *File) syncRange(offset, nbytes int64, flags int) error {
+	return errors.ErrUnsupported
+}
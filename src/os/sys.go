@@ -8,3 +8,21 @@ package os
 func Hostname() (name string, err error) {
 	return hostname()
 }
+
+// FQDN returns the fully qualified domain name of the host, such as
+// "host.example.com". If no domain is configured for the host, it
+// returns the same name as [Hostname].
+//
+// On Windows, FQDN asks the system for the DNS name of the computer.
+// Elsewhere, it looks the host name up in the hosts file, /etc/hosts,
+// and returns the canonical name listed there if that name is of the
+// form hostname.domain. FQDN may read files and is slower than Hostname.
+//
+// Outside Windows, FQDN does not query DNS or the C library resolver,
+// which package os cannot depend on, so it does not see domains that
+// only the resolver knows about, such as one set by DHCP. Programs that
+// need the name the resolver reports should call [net.LookupCNAME] with
+// the host name.
+func FQDN() (string, error) {
+	return fqdn()
+}
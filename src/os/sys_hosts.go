@@ -0,0 +1,110 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+
+package os
+
+import "internal/bytealg"
+
+func fqdn() (string, error) {
+	name, err := Hostname()
+	if err != nil {
+		return "", err
+	}
+	if bytealg.IndexByteString(name, '.') >= 0 {
+		return name, nil
+	}
+	data, err := ReadFile("/etc/hosts")
+	if err != nil {
+		// No hosts file means no domain is configured for the host.
+		return name, nil
+	}
+	if full := lookupHostsFQDN(name, data); full != "" {
+		return full, nil
+	}
+	return name, nil
+}
+
+// lookupHostsFQDN looks for name among the host names in data, which
+// is in the format of /etc/hosts. If it finds name on a line whose
+// canonical name (the first name after the address) is name followed by
+// a domain, it returns the canonical name. Otherwise it returns "".
+func lookupHostsFQDN(name string, data []byte) string {
+	for len(data) > 0 {
+		var line []byte
+		line, data = cutByte(data, '\n')
+		line, _ = cutByte(line, '#')
+
+		var fields [][]byte
+		for {
+			var f []byte
+			f, line = nextField(line)
+			if f == nil {
+				break
+			}
+			fields = append(fields, f)
+		}
+		if len(fields) < 2 {
+			continue
+		}
+		canonical := fields[1]
+		if len(canonical) <= len(name)+1 || canonical[len(name)] != '.' || !equalFoldASCII(canonical[:len(name)], name) {
+			continue
+		}
+		for _, f := range fields[1:] {
+			if equalFoldASCII(f, name) {
+				return string(canonical)
+			}
+		}
+	}
+	return ""
+}
+
+// cutByte slices b around the first instance of c.
+func cutByte(b []byte, c byte) (before, after []byte) {
+	if i := bytealg.IndexByte(b, c); i >= 0 {
+		return b[:i], b[i+1:]
+	}
+	return b, nil
+}
+
+// nextField returns the first field of b, delimited by spaces, tabs
+// or carriage returns, and the rest of b. The field is nil if there is
+// none.
+func nextField(b []byte) (field, rest []byte) {
+	isSpace := func(c byte) bool { return c == ' ' || c == '\t' || c == '\r' }
+	i := 0
+	for i < len(b) && isSpace(b[i]) {
+		i++
+	}
+	if i == len(b) {
+		return nil, nil
+	}
+	j := i
+	for j < len(b) && !isSpace(b[j]) {
+		j++
+	}
+	return b[i:j], b[j:]
+}
+
+// equalFoldASCII reports whether b and s are equal, ignoring ASCII case.
+func equalFoldASCII(b []byte, s string) bool {
+	if len(b) != len(s) {
+		return false
+	}
+	for i := range b {
+		c1, c2 := b[i], s[i]
+		if 'A' <= c1 && c1 <= 'Z' {
+			c1 += 'a' - 'A'
+		}
+		if 'A' <= c2 && c2 <= 'Z' {
+			c2 += 'a' - 'A'
+		}
+		if c1 != c2 {
+			return false
+		}
+	}
+	return true
+}
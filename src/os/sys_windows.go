@@ -11,8 +11,15 @@ import (
 
 func hostname() (name string, err error) {
 	// Use PhysicalDnsHostname to uniquely identify host in a cluster
-	const format = windows.ComputerNamePhysicalDnsHostname
+	return computerName(windows.ComputerNamePhysicalDnsHostname)
+}
+
+func fqdn() (string, error) {
+	return computerName(windows.ComputerNameDnsFullyQualified)
+}
 
+// computerName returns the name of the computer in the given format.
+func computerName(format uint32) (string, error) {
 	n := uint32(64)
 	for {
 		b := make([]uint16, n)
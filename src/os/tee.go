@@ -0,0 +1,75 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "io"
+
+// TeeFile returns a writer that writes to f and duplicates each write to
+// the extra writers. Each write goes to f first and then to the extra
+// writers, in order. If a writer returns an error, the write stops there
+// and the error is returned, without writing to the remaining writers.
+// Closing the returned writer closes f, but not the extra writers.
+//
+// If there are no extra writers, TeeFile returns f itself, so that
+// operations on fast paths specific to *File, such as [File.ReadFrom],
+// are kept. Otherwise the returned writer implements [io.StringWriter]
+// as well, and writes strings to f with [File.WriteString].
+func TeeFile(f *File, extra ...io.Writer) io.WriteCloser {
+	if len(extra) == 0 {
+		return f
+	}
+	return &teeFile{f: f, extra: extra}
+}
+
+type teeFile struct {
+	f     *File
+	extra []io.Writer
+}
+
+func (t *teeFile) Write(b []byte) (int, error) {
+	n, err := t.f.Write(b)
+	if err != nil {
+		return n, err
+	}
+	for _, w := range t.extra {
+		n, err = w.Write(b)
+		if err == nil && n != len(b) {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+	return len(b), nil
+}
+
+func (t *teeFile) WriteString(s string) (int, error) {
+	n, err := t.f.WriteString(s)
+	if err != nil {
+		return n, err
+	}
+	var b []byte // allocated only if needed
+	for _, w := range t.extra {
+		if sw, ok := w.(io.StringWriter); ok {
+			n, err = sw.WriteString(s)
+		} else {
+			if b == nil {
+				b = []byte(s)
+			}
+			n, err = w.Write(b)
+		}
+		if err == nil && n != len(s) {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+	return len(s), nil
+}
+
+func (t *teeFile) Close() error {
+	return t.f.Close()
+}
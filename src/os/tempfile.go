@@ -7,6 +7,7 @@ package os
 import (
 	"errors"
 	"internal/bytealg"
+	"internal/filepathlite"
 	"internal/itoa"
 	_ "unsafe" // for go:linkname
 )
@@ -33,6 +34,15 @@ func nextRandom() string {
 // The caller can use the file's Name method to find the pathname of the file.
 // It is the caller's responsibility to remove the file when it is no longer needed.
 func CreateTemp(dir, pattern string) (*File, error) {
+	return CreateTempMode(dir, pattern, 0600)
+}
+
+// CreateTempMode is like [CreateTemp] but creates the file with mode perm
+// (before umask) instead of 0o600. The mode is applied as the file is
+// created, so there is no window in which the file has another mode, as
+// there would be with a later call to [Chmod]. As with [OpenFile], the
+// process umask is cleared from perm; see [Umask].
+func CreateTempMode(dir, pattern string, perm FileMode) (*File, error) {
 	if dir == "" {
 		dir = TempDir()
 	}
@@ -46,7 +56,7 @@ func CreateTemp(dir, pattern string) (*File, error) {
 	try := 0
 	for {
 		name := prefix + nextRandom() + suffix
-		f, err := OpenFile(name, O_RDWR|O_CREATE|O_EXCL, 0600)
+		f, err := OpenFile(name, O_RDWR|O_CREATE|O_EXCL, perm)
 		if IsExist(err) {
 			if try++; try < 10000 {
 				continue
@@ -57,6 +67,96 @@ func CreateTemp(dir, pattern string) (*File, error) {
 	}
 }
 
+// OpenAnonymous creates a new unnamed file in the directory dir with mode
+// perm (before umask), opened for reading and writing. The file does not
+// appear in dir until [File.LinkTo] gives it a name, and it is discarded if
+// it is closed without being linked. This makes it possible to write out the
+// complete contents of a file before putting it into place atomically.
+// If dir is the empty string, OpenAnonymous uses the default directory for
+// temporary files, as returned by [TempDir].
+//
+// On Linux, OpenAnonymous opens dir with O_TMPFILE, and the Name method of
+// the returned File reports dir joined with "(anonymous)". On other systems, or if the file system
+// does not support O_TMPFILE, OpenAnonymous instead creates the file in dir
+// under a random name, which Name reports, and LinkTo renames it.
+func OpenAnonymous(dir string, perm FileMode) (*File, error) {
+	if dir == "" {
+		dir = TempDir()
+	}
+	if f, err := openTmpfile(dir, perm); f != nil || err != nil {
+		return f, err
+	}
+
+	prefix := joinPath(dir, ".anon")
+	try := 0
+	for {
+		name := prefix + nextRandom()
+		f, err := createAnonymousFile(name, perm)
+		if IsExist(err) {
+			if try++; try < 10000 {
+				continue
+			}
+			return nil, &PathError{Op: "openanonymous", Path: dir, Err: ErrExist}
+		}
+		if err != nil {
+			return nil, err
+		}
+		f.getExtra().anon = &anonFile{tmpName: name}
+		return f, nil
+	}
+}
+
+// anonFile describes a File returned by OpenAnonymous
+// that has not yet been linked into place.
+type anonFile struct {
+	// tmpName is the name of the file created when O_TMPFILE
+	// is not available, or "" for an O_TMPFILE file.
+	tmpName string
+}
+
+// LinkTo gives the file f, which must have been returned by [OpenAnonymous]
+// and not yet linked, the name newname, atomically replacing any existing
+// file with that name. newname must be on the same file system as the
+// directory passed to OpenAnonymous. After LinkTo succeeds, f remains open
+// and is an ordinary File.
+// If f was not returned by OpenAnonymous or has already been linked,
+// LinkTo returns an error wrapping [ErrInvalid].
+// If there is an error, it will be of type [*LinkError].
+func (f *File) LinkTo(newname string) error {
+	if err := f.checkValid("linkto"); err != nil {
+		return err
+	}
+	e := f.extra.Load()
+	if e == nil || e.anon == nil {
+		return &LinkError{Op: "linkto", Old: f.name, New: newname, Err: ErrInvalid}
+	}
+	a := e.anon
+	var err error
+	if a.tmpName != "" {
+		err = Rename(a.tmpName, newname)
+	} else {
+		err = f.linkTmpfile(newname)
+	}
+	if err != nil {
+		return err
+	}
+	e.anon = nil
+	return nil
+}
+
+// removeAnonymous removes the file created in place of an O_TMPFILE file
+// by OpenAnonymous if it was closed without being linked.
+func (f *File) removeAnonymous() {
+	e := f.extra.Load()
+	if e == nil {
+		return
+	}
+	if a := e.anon; a != nil && a.tmpName != "" {
+		Remove(a.tmpName)
+	}
+	e.anon = nil
+}
+
 var errPatternHasSeparator = errors.New("pattern contains path separator")
 
 // prefixAndSuffix splits pattern by the last wildcard "*", if applicable,
@@ -84,6 +184,13 @@ func prefixAndSuffix(pattern string) (prefix, suffix string, err error) {
 // Multiple programs or goroutines calling MkdirTemp simultaneously will not choose the same directory.
 // It is the caller's responsibility to remove the directory when it is no longer needed.
 func MkdirTemp(dir, pattern string) (string, error) {
+	return MkdirTempMode(dir, pattern, 0700)
+}
+
+// MkdirTempMode is like [MkdirTemp] but creates the directory with mode
+// perm (before umask) instead of 0o700. As with [Mkdir], the process
+// umask is cleared from perm; see [Umask].
+func MkdirTempMode(dir, pattern string, perm FileMode) (string, error) {
 	if dir == "" {
 		dir = TempDir()
 	}
@@ -97,7 +204,7 @@ func MkdirTemp(dir, pattern string) (string, error) {
 	try := 0
 	for {
 		name := prefix + nextRandom() + suffix
-		err := Mkdir(name, 0700)
+		err := Mkdir(name, perm)
 		if err == nil {
 			return name, nil
 		}
@@ -116,6 +223,54 @@ func MkdirTemp(dir, pattern string) (string, error) {
 	}
 }
 
+// IsCaseSensitive reports whether the file system holding path
+// distinguishes file names that differ only in case. It probes the
+// file system by creating a file with a mixed-case name, in path if
+// path is a directory and otherwise in the directory containing path,
+// and checking whether the lower-case form of the name refers to the
+// same file. The probe file is removed before IsCaseSensitive returns.
+//
+// The probe requires permission to create and remove files in the
+// directory. The answer applies to that directory; on some systems the
+// case sensitivity can vary between directories of the same file system.
+func IsCaseSensitive(path string) (bool, error) {
+	fi, err := Stat(path)
+	if err != nil {
+		return false, err
+	}
+	dir := path
+	if !fi.IsDir() {
+		dir = filepathlite.Dir(path)
+	}
+
+	f, err := CreateTemp(dir, ".CaseProbe")
+	if err != nil {
+		return false, err
+	}
+	name := f.Name()
+	defer Remove(name)
+	probe, err := f.Stat()
+	f.Close()
+	if err != nil {
+		return false, err
+	}
+
+	lower := []byte(filepathlite.Base(name))
+	for i, c := range lower {
+		if 'A' <= c && c <= 'Z' {
+			lower[i] = c + 'a' - 'A'
+		}
+	}
+	fi, err = Lstat(joinPath(dir, string(lower)))
+	if IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !SameFile(probe, fi), nil
+}
+
 func joinPath(dir, name string) string {
 	if len(dir) > 0 && IsPathSeparator(dir[len(dir)-1]) {
 		return dir + name
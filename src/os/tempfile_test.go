@@ -10,6 +10,7 @@ import (
 	. "os"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"strings"
 	"testing"
 )
@@ -203,3 +204,144 @@ func TestMkdirTempBadPattern(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenAnonymous(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	f, err := OpenAnonymous(dir, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	const data = "anonymous contents"
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+
+	// With O_TMPFILE the file has no name until it is linked.
+	entries, err := ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Name() == filepath.Join(dir, "(anonymous)") {
+		if len(entries) != 0 {
+			t.Errorf("dir contains %v before LinkTo; want nothing", entries)
+		}
+	} else if len(entries) != 1 || entries[0].Name() != filepath.Base(f.Name()) {
+		t.Errorf("dir contains %v before LinkTo; want only %s", entries, filepath.Base(f.Name()))
+	}
+
+	// LinkTo replaces an existing file.
+	name := filepath.Join(dir, "linked")
+	if err := WriteFile(name, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.LinkTo(name); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ReadFile(name); err != nil || string(got) != data {
+		t.Errorf("ReadFile after LinkTo = %q, %v; want %q", got, err, data)
+	}
+	entries, err = ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("dir contains %v after LinkTo; want only linked", entries)
+	}
+
+	var le *LinkError
+	if err := f.LinkTo(name + "2"); !errors.As(err, &le) || !errors.Is(err, ErrInvalid) {
+		t.Errorf("second LinkTo: got %v, want LinkError wrapping ErrInvalid", err)
+	}
+
+	// The File stays usable after linking.
+	if _, err := f.WriteString("!"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ReadFile(name); err != nil || string(got) != data+"!" {
+		t.Errorf("ReadFile after Close = %q, %v; want %q", got, err, data+"!")
+	}
+}
+
+func TestOpenAnonymousCloseUnlinked(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	f, err := OpenAnonymous(dir, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("discarded"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("dir contains %v after closing an unlinked file; want nothing", entries)
+	}
+
+	if _, err := OpenAnonymous(filepath.Join(dir, "missing"), 0o600); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("OpenAnonymous in missing dir: got %v, want ErrNotExist", err)
+	}
+}
+
+func TestIsCaseSensitive(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := WriteFile(file, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{dir, file} {
+		sensitive, err := IsCaseSensitive(path)
+		if err != nil {
+			t.Fatalf("IsCaseSensitive(%q): %v", path, err)
+		}
+		// The answer must agree with a lookup of the upper-case name.
+		_, err = Stat(filepath.Join(dir, "FILE"))
+		if exists := err == nil; exists == sensitive {
+			t.Errorf("IsCaseSensitive(%q) = %v, but Stat of %q in upper case succeeded: %v", path, sensitive, file, exists)
+		}
+	}
+
+	if _, err := IsCaseSensitive(filepath.Join(dir, "missing")); !IsNotExist(err) {
+		t.Errorf("IsCaseSensitive of a missing path = %v, want not-exist error", err)
+	}
+
+	if runtime.GOOS != "windows" && runtime.GOOS != "plan9" && Getuid() != 0 {
+		// The probe cannot be created in a read-only directory.
+		if err := Chmod(dir, 0555); err != nil {
+			t.Fatal(err)
+		}
+		_, err := IsCaseSensitive(dir)
+		if err := Chmod(dir, 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err == nil {
+			t.Errorf("IsCaseSensitive of a read-only directory succeeded, want error")
+		}
+	}
+
+	entries, err := ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "file" {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("directory contains %q after IsCaseSensitive, want only the probed file", names)
+	}
+}
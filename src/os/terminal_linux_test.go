@@ -0,0 +1,35 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	"internal/syscall/unix"
+	. "os"
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+func TestTerminalSize(t *testing.T) {
+	t.Parallel()
+
+	f, err := OpenFile("/dev/ptmx", O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("no pseudo-terminal: %v", err)
+	}
+	defer f.Close()
+
+	ws := unix.Winsize{Row: 24, Col: 80}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), syscall.TIOCSWINSZ, uintptr(unsafe.Pointer(&ws))); errno != 0 {
+		t.Fatalf("TIOCSWINSZ: %v", errno)
+	}
+	cols, rows, err := f.TerminalSize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cols != 80 || rows != 24 {
+		t.Errorf("TerminalSize() = %d, %d; want 80, 24", cols, rows)
+	}
+}
@@ -706,3 +706,64 @@ func TestTTYClose(t *testing.T) {
 	// On some systems the goroutines may now be hanging.
 	// There's not much we can do about that.
 }
+
+func TestPipeDeadlineExceeded(t *testing.T) {
+	t.Parallel()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	// Nothing has been written, so the read must time out.
+	if err := r.SetReadDeadline(time.Now().Add(10 * time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	var b [1]byte
+	if n, err := r.Read(b[:]); n != 0 || !isDeadlineExceeded(err) {
+		t.Errorf("Read from empty pipe = %d, %v; want 0, deadline exceeded", n, err)
+	}
+
+	// Fill the pipe, so that a write must time out.
+	if err := w.SetWriteDeadline(time.Now().Add(10 * time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 64<<10)
+	for {
+		if _, err := w.Write(buf); err != nil {
+			if !isDeadlineExceeded(err) {
+				t.Errorf("Write to full pipe: %v; want deadline exceeded", err)
+			}
+			break
+		}
+	}
+}
+
+func TestFIFODeadlineExceeded(t *testing.T) {
+	switch runtime.GOOS {
+	case "darwin", "ios":
+		t.Skipf("FIFOs are not pollable on %s", runtime.GOOS)
+	}
+	t.Parallel()
+
+	name := t.TempDir() + "/fifo"
+	if err := os.Mkfifo(name, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// Opening the read end with O_RDWR keeps open from waiting for a writer.
+	f, err := os.OpenFile(name, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if err := f.SetReadDeadline(time.Now().Add(10 * time.Millisecond)); err != nil {
+		t.Fatalf("SetReadDeadline on FIFO: %v", err)
+	}
+	var b [1]byte
+	if n, err := f.Read(b[:]); n != 0 || !isDeadlineExceeded(err) {
+		t.Errorf("Read from empty FIFO = %d, %v; want 0, deadline exceeded", n, err)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"errors"
+	"internal/itoa"
+	"internal/syscall/unix"
+	"runtime"
+	"syscall"
+)
+
+// openTmpfile opens an unnamed file in dir using O_TMPFILE.
+// It returns nil, nil if the kernel or file system does not support
+// O_TMPFILE, so that OpenAnonymous falls back to a named file.
+func openTmpfile(dir string, perm FileMode) (*File, error) {
+	f, err := openFileNolog(dir, O_RDWR|unix.O_TMPFILE, perm)
+	if err != nil {
+		// Kernels without O_TMPFILE see only O_DIRECTORY and
+		// fail with EISDIR.
+		if errors.Is(err, syscall.EOPNOTSUPP) || errors.Is(err, syscall.EISDIR) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	f.name = joinPath(dir, "(anonymous)")
+	f.getExtra().anon = new(anonFile)
+	return f, nil
+}
+
+// linkTmpfile links the O_TMPFILE file f to newname, replacing any
+// existing file.
+func (f *File) linkTmpfile(newname string) error {
+	err := f.linkat(newname)
+	if err == syscall.EEXIST {
+		// linkat(2) never replaces newname, so link the file under a
+		// temporary name next to it and rename that over newname.
+		for try := 0; try < 10000; try++ {
+			tmp := newname + "." + nextRandom()
+			if err = f.linkat(tmp); err == syscall.EEXIST {
+				continue
+			}
+			if err != nil {
+				break
+			}
+			if err := Rename(tmp, newname); err != nil {
+				Remove(tmp)
+				return err
+			}
+			return nil
+		}
+	}
+	if err != nil {
+		return &LinkError{Op: "linkat", Old: f.name, New: newname, Err: err}
+	}
+	return nil
+}
+
+func (f *File) linkat(newname string) error {
+	var err error
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		err = ignoringEINTR(func() error {
+			return unix.Linkat(int(fd), "", unix.AT_FDCWD, newname, unix.AT_EMPTY_PATH)
+		})
+		if err == syscall.ENOENT {
+			// AT_EMPTY_PATH requires CAP_DAC_READ_SEARCH, and linkat
+			// fails with ENOENT without it. Linking the descriptor's
+			// /proc entry works for unprivileged processes.
+			err = ignoringEINTR(func() error {
+				return unix.Linkat(unix.AT_FDCWD, "/proc/self/fd/"+itoa.Itoa(int(fd)), unix.AT_FDCWD, newname, unix.AT_SYMLINK_FOLLOW)
+			})
+		}
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		return cerr
+	}
+	return err
+}
+
+func createAnonymousFile(name string, perm FileMode) (*File, error) {
+	return OpenFile(name, O_RDWR|O_CREATE|O_EXCL, perm)
+}
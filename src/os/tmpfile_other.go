@@ -0,0 +1,23 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux && !windows
+
+package os
+
+import "errors"
+
+func openTmpfile(dir string, perm FileMode) (*File, error) {
+	return nil, nil
+}
+
+// linkTmpfile is not called, because openTmpfile never returns
+// an O_TMPFILE file.
+func (f *File) linkTmpfile(newname string) error {
+	return &LinkError{Op: "linkat", Old: f.name, New: newname, Err: errors.ErrUnsupported}
+}
+
+func createAnonymousFile(name string, perm FileMode) (*File, error) {
+	return OpenFile(name, O_RDWR|O_CREATE|O_EXCL, perm)
+}
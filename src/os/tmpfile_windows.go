@@ -0,0 +1,42 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"errors"
+	"syscall"
+)
+
+func openTmpfile(dir string, perm FileMode) (*File, error) {
+	return nil, nil
+}
+
+// linkTmpfile is not called, because openTmpfile never returns
+// an O_TMPFILE file.
+func (f *File) linkTmpfile(newname string) error {
+	return &LinkError{Op: "linkat", Old: f.name, New: newname, Err: errors.ErrUnsupported}
+}
+
+// createAnonymousFile creates name like OpenFile with O_RDWR|O_CREATE|O_EXCL,
+// but shares the handle for deletion so that LinkTo can rename the file
+// while it is open.
+func createAnonymousFile(name string, perm FileMode) (*File, error) {
+	pathp, err := syscall.UTF16PtrFromString(fixLongPath(name))
+	if err != nil {
+		return nil, &PathError{Op: "open", Path: name, Err: err}
+	}
+	var attrs uint32 = syscall.FILE_ATTRIBUTE_NORMAL
+	if perm&0o200 == 0 {
+		attrs = syscall.FILE_ATTRIBUTE_READONLY
+	}
+	h, err := syscall.CreateFile(pathp,
+		syscall.GENERIC_READ|syscall.GENERIC_WRITE,
+		syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE,
+		nil, syscall.CREATE_NEW, attrs, 0)
+	if err != nil {
+		return nil, &PathError{Op: "open", Path: name, Err: err}
+	}
+	return newFile(h, name, "file"), nil
+}
@@ -74,3 +74,50 @@ func SameFile(fi1, fi2 FileInfo) bool {
 	}
 	return sameFile(fs1, fs2)
 }
+
+// SameFileEntry reports whether the directory entries a and b describe
+// the same file, as [SameFile] would for the results of their Info methods.
+// Like [DirEntry.Info], it does not follow symbolic links. SameFileEntry
+// calls Info on both entries and returns any error it encounters.
+// SameFileEntry only applies to entries returned by this package's
+// [ReadDir] functions and methods. It returns false in other cases.
+func SameFileEntry(a, b DirEntry) (bool, error) {
+	fi1, err := a.Info()
+	if err != nil {
+		return false, err
+	}
+	fi2, err := b.Info()
+	if err != nil {
+		return false, err
+	}
+	return SameFile(fi1, fi2), nil
+}
+
+// SameFileInfoEntry reports whether fi and the directory entry de
+// describe the same file, as [SameFile] would for fi and the result
+// of de's Info method. It calls de.Info and returns any error it
+// encounters.
+func SameFileInfoEntry(fi FileInfo, de DirEntry) (bool, error) {
+	fi2, err := de.Info()
+	if err != nil {
+		return false, err
+	}
+	return SameFile(fi, fi2), nil
+}
+
+// FileID returns the identity of the file described by fi: on Unix
+// systems its device and inode numbers, and on Windows the serial number
+// of its volume and its file index. Two [FileInfo] values describe the
+// same file, as reported by [SameFile], if and only if they have the same
+// identity, so the identity can serve as a map key for detecting hard
+// links. On Windows, FileID may need to open the file to get the file
+// index. FileID only applies to results returned by this package's
+// [Stat] and similar functions; ok is false in other cases, and if the
+// identity is not available.
+func FileID(fi FileInfo) (dev, ino uint64, ok bool) {
+	fs, ok := fi.(*fileStat)
+	if !ok {
+		return 0, 0, false
+	}
+	return fileID(fs)
+}
@@ -28,3 +28,10 @@ func sameFile(fs1, fs2 *fileStat) bool {
 	b := fs2.sys.(*syscall.Dir)
 	return a.Qid.Path == b.Qid.Path && a.Type == b.Type && a.Dev == b.Dev
 }
+
+func fileID(fs *fileStat) (dev, ino uint64, ok bool) {
+	d := fs.sys.(*syscall.Dir)
+	// The device is identified by both the type of its server
+	// and its number.
+	return uint64(d.Type)<<32 | uint64(d.Dev), d.Qid.Path, true
+}
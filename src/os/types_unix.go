@@ -18,6 +18,7 @@ type fileStat struct {
 	mode    FileMode
 	modTime time.Time
 	sys     syscall.Stat_t
+	btime   time.Time // birth time, on systems where sys lacks it
 }
 
 func (fs *fileStat) Size() int64        { return fs.size }
@@ -28,3 +29,7 @@ func (fs *fileStat) Sys() any           { return &fs.sys }
 func sameFile(fs1, fs2 *fileStat) bool {
 	return fs1.sys.Dev == fs2.sys.Dev && fs1.sys.Ino == fs2.sys.Ino
 }
+
+func fileID(fs *fileStat) (dev, ino uint64, ok bool) {
+	return uint64(fs.sys.Dev), uint64(fs.sys.Ino), true
+}
@@ -32,6 +32,10 @@ type fileStat struct {
 	// what syscall.GetFileType returns
 	filetype uint32
 
+	// permission bits derived from the file's ACL by StatACL
+	aclPerm    FileMode
+	hasACLPerm bool
+
 	// used to implement SameFile
 	sync.Mutex
 	path             string
@@ -171,6 +175,9 @@ func (fs *fileStat) Mode() FileMode {
 			m = old
 		}
 	}
+	if fs.hasACLPerm {
+		m = m&^ModePerm | fs.aclPerm
+	}
 	return m
 }
 
@@ -362,6 +369,13 @@ func sameFile(fs1, fs2 *fileStat) bool {
 	return fs1.vol == fs2.vol && fs1.idxhi == fs2.idxhi && fs1.idxlo == fs2.idxlo
 }
 
+func fileID(fs *fileStat) (dev, ino uint64, ok bool) {
+	if fs.loadFileId() != nil {
+		return 0, 0, false
+	}
+	return uint64(fs.vol), uint64(fs.idxhi)<<32 | uint64(fs.idxlo), true
+}
+
 // For testing.
 func atime(fi FileInfo) time.Time {
 	return time.Unix(0, fi.Sys().(*syscall.Win32FileAttributeData).LastAccessTime.Nanoseconds())
@@ -9,6 +9,10 @@
 
 package os
 
+// canBlockUntilWaitable reports whether blockUntilWaitable is
+// implemented on this system.
+const canBlockUntilWaitable = false
+
 // blockUntilWaitable attempts to block until a call to p.Wait will
 // succeed immediately, and reports whether it has done so.
 // It does not actually call p.Wait.
@@ -11,6 +11,10 @@ import (
 	"syscall"
 )
 
+// canBlockUntilWaitable reports whether blockUntilWaitable is
+// implemented on this system.
+const canBlockUntilWaitable = true
+
 // blockUntilWaitable attempts to block until a call to p.Wait will
 // succeed immediately, and reports whether it has done so.
 // It does not actually call p.Wait.
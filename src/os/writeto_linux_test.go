@@ -91,6 +91,47 @@ func testSendFile(t *testing.T, proto string, size int64) {
 	}
 }
 
+func TestSendFileToSocketFile(t *testing.T) {
+	for _, proto := range []string{"unix", "tcp"} {
+		t.Run(proto, func(t *testing.T) {
+			client, server := createSocketPair(t, proto)
+			src, data := createTempFile(t, 32769)
+			hook := hookSendFile(t)
+
+			// Turn the client end into a *File, as a program
+			// handed a socket by its parent might.
+			dst, err := client.(interface{ File() (*File, error) }).File()
+			if err != nil {
+				t.Fatal(err)
+			}
+			client.Close()
+
+			n, err := io.Copy(dst, src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != int64(len(data)) {
+				t.Fatalf("copied %d bytes, want %d", n, len(data))
+			}
+			if !hook.called || hook.dstfd != int(dst.Fd()) || hook.srcfd != int(src.Fd()) {
+				t.Fatalf("poll.SendFile not called with the expected descriptors: %+v", hook)
+			}
+			if got := src.CopyStats().Method; got != "sendfile" {
+				t.Errorf("CopyStats().Method = %q, want %q", got, "sendfile")
+			}
+			dst.Close()
+
+			got, err := io.ReadAll(server)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Error("received data differs from the file contents")
+			}
+		})
+	}
+}
+
 // newSendFileTest initializes a new test for sendfile.
 //
 // It creates source file and destination sockets, and populates the source file
@@ -0,0 +1,113 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// Getxattr returns the value of the extended attribute name of the named file.
+// If the file is a symbolic link, Getxattr reads the attribute of the link's target.
+// If there is an error, it will be of type [*PathError].
+//
+// Extended attributes are supported on Linux and Darwin. On other
+// systems Getxattr returns an error wrapping [errors.ErrUnsupported],
+// as do all of the other xattr functions and methods in this package.
+func Getxattr(path, name string) ([]byte, error) {
+	data, err := getxattr(path, name)
+	if err != nil {
+		return nil, &PathError{Op: "getxattr", Path: path, Err: err}
+	}
+	return data, nil
+}
+
+// Setxattr sets the value of the extended attribute name of the named file
+// to data. flags is passed to the underlying system call unchanged;
+// it is zero or a combination of the system's XATTR_CREATE and
+// XATTR_REPLACE values, which differ between systems.
+// If there is an error, it will be of type [*PathError].
+func Setxattr(path, name string, data []byte, flags int) error {
+	if err := setxattr(path, name, data, flags); err != nil {
+		return &PathError{Op: "setxattr", Path: path, Err: err}
+	}
+	return nil
+}
+
+// Listxattr returns the names of the extended attributes of the named file.
+// If there is an error, it will be of type [*PathError].
+func Listxattr(path string) ([]string, error) {
+	buf, err := listxattr(path)
+	if err != nil {
+		return nil, &PathError{Op: "listxattr", Path: path, Err: err}
+	}
+	return splitXattrNames(buf), nil
+}
+
+// Removexattr removes the extended attribute name from the named file.
+// If there is an error, it will be of type [*PathError].
+func Removexattr(path, name string) error {
+	if err := removexattr(path, name); err != nil {
+		return &PathError{Op: "removexattr", Path: path, Err: err}
+	}
+	return nil
+}
+
+// Getxattr is like [Getxattr] but operates on the open file f.
+func (f *File) Getxattr(name string) ([]byte, error) {
+	if err := f.checkValid("getxattr"); err != nil {
+		return nil, err
+	}
+	data, err := f.getxattr(name)
+	if err != nil {
+		return nil, f.wrapErr("getxattr", err)
+	}
+	return data, nil
+}
+
+// Setxattr is like [Setxattr] but operates on the open file f.
+func (f *File) Setxattr(name string, data []byte, flags int) error {
+	if err := f.checkValid("setxattr"); err != nil {
+		return err
+	}
+	if err := f.setxattr(name, data, flags); err != nil {
+		return f.wrapErr("setxattr", err)
+	}
+	return nil
+}
+
+// Listxattr is like [Listxattr] but operates on the open file f.
+func (f *File) Listxattr() ([]string, error) {
+	if err := f.checkValid("listxattr"); err != nil {
+		return nil, err
+	}
+	buf, err := f.listxattr()
+	if err != nil {
+		return nil, f.wrapErr("listxattr", err)
+	}
+	return splitXattrNames(buf), nil
+}
+
+// Removexattr is like [Removexattr] but operates on the open file f.
+func (f *File) Removexattr(name string) error {
+	if err := f.checkValid("removexattr"); err != nil {
+		return err
+	}
+	if err := f.removexattr(name); err != nil {
+		return f.wrapErr("removexattr", err)
+	}
+	return nil
+}
+
+// splitXattrNames splits the NUL-terminated list of names
+// returned by listxattr(2).
+func splitXattrNames(buf []byte) []string {
+	var names []string
+	start := 0
+	for i, c := range buf {
+		if c == 0 {
+			if i > start {
+				names = append(names, string(buf[start:i]))
+			}
+			start = i + 1
+		}
+	}
+	return names
+}
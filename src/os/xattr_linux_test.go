@@ -0,0 +1,86 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	"bytes"
+	"errors"
+	. "os"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"testing"
+)
+
+func TestXattr(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "xattr")
+	if err := WriteFile(name, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	const attr = "user.go-test"
+	value := []byte("some value\x00with a NUL")
+	if err := Setxattr(name, attr, value, 0); err != nil {
+		if errors.Is(err, syscall.ENOTSUP) {
+			t.Skipf("user xattrs not supported in %s: %v", filepath.Dir(name), err)
+		}
+		t.Fatal(err)
+	}
+	got, err := Getxattr(name, attr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, value) {
+		t.Errorf("Getxattr = %q; want %q", got, value)
+	}
+	names, err := Listxattr(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(names, attr) {
+		t.Errorf("Listxattr = %q; want it to contain %q", names, attr)
+	}
+
+	f, err := OpenFile(name, O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := f.Setxattr(attr+"2", []byte{}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := f.Getxattr(attr + "2"); err != nil || len(got) != 0 {
+		t.Errorf("File.Getxattr of empty value = %q, %v; want empty, nil", got, err)
+	}
+	if got, err := f.Getxattr(attr); err != nil || !bytes.Equal(got, value) {
+		t.Errorf("File.Getxattr = %q, %v; want %q, nil", got, err, value)
+	}
+	names, err = f.Listxattr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(names, attr) || !slices.Contains(names, attr+"2") {
+		t.Errorf("File.Listxattr = %q; want it to contain %q and %q", names, attr, attr+"2")
+	}
+	if err := f.Setxattr(attr+"2", value, 0x1 /* XATTR_CREATE */); !errors.Is(err, ErrExist) {
+		t.Errorf("File.Setxattr with XATTR_CREATE of existing attribute: got %v, want ErrExist", err)
+	}
+
+	if err := f.Removexattr(attr + "2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Removexattr(name, attr); err != nil {
+		t.Fatal(err)
+	}
+	var pe *PathError
+	if _, err := Getxattr(name, attr); !errors.As(err, &pe) || pe.Op != "getxattr" || pe.Err != syscall.ENODATA {
+		t.Errorf("Getxattr of removed attribute: got %v, want getxattr ENODATA PathError", err)
+	}
+	if names, err := Listxattr(name); err != nil || slices.Contains(names, attr) {
+		t.Errorf("Listxattr after Removexattr = %q, %v", names, err)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !darwin && !linux
+
+package os
+
+import "errors"
+
+func getxattr(path, name string) ([]byte, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func setxattr(path, name string, data []byte, flags int) error {
+	return errors.ErrUnsupported
+}
+
+func listxattr(path string) ([]byte, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func removexattr(path, name string) error {
+	return errors.ErrUnsupported
+}
+
+func (f *File) getxattr(name string) ([]byte, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func (f *File) setxattr(name string, data []byte, flags int) error {
+	return errors.ErrUnsupported
+}
+
+func (f *File) listxattr() ([]byte, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func (f *File) removexattr(name string) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || linux
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+	"syscall"
+)
+
+// readXattr calls get first to learn the size of the value and then
+// to read it, retrying if the value grows between the two calls.
+func readXattr(get func([]byte) (int, error)) ([]byte, error) {
+	for {
+		var n int
+		err := ignoringEINTR(func() (err error) {
+			n, err = get(nil)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			// get(nil) would only report the size again.
+			return []byte{}, nil
+		}
+		buf := make([]byte, n)
+		err = ignoringEINTR(func() (err error) {
+			n, err = get(buf)
+			return err
+		})
+		if err == syscall.ERANGE {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if n > len(buf) {
+			// Some systems report the full size of a value that
+			// grew instead of failing with ERANGE.
+			continue
+		}
+		return buf[:n], nil
+	}
+}
+
+func getxattr(path, name string) ([]byte, error) {
+	return readXattr(func(buf []byte) (int, error) {
+		return unix.Getxattr(path, name, buf)
+	})
+}
+
+func setxattr(path, name string, data []byte, flags int) error {
+	return ignoringEINTR(func() error {
+		return unix.Setxattr(path, name, data, flags)
+	})
+}
+
+func listxattr(path string) ([]byte, error) {
+	return readXattr(func(buf []byte) (int, error) {
+		return unix.Listxattr(path, buf)
+	})
+}
+
+func removexattr(path, name string) error {
+	return ignoringEINTR(func() error {
+		return unix.Removexattr(path, name)
+	})
+}
+
+// fdXattr runs fn with the file's descriptor.
+func (f *File) fdXattr(fn func(fd int) error) error {
+	var err error
+	cerr := f.pfd.RawControl(func(fd uintptr) {
+		err = fn(int(fd))
+	})
+	runtime.KeepAlive(f)
+	if cerr != nil {
+		return cerr
+	}
+	return err
+}
+
+func (f *File) getxattr(name string) (data []byte, err error) {
+	cerr := f.fdXattr(func(fd int) error {
+		data, err = readXattr(func(buf []byte) (int, error) {
+			return unix.Fgetxattr(fd, name, buf)
+		})
+		return err
+	})
+	return data, cerr
+}
+
+func (f *File) setxattr(name string, data []byte, flags int) error {
+	return f.fdXattr(func(fd int) error {
+		return ignoringEINTR(func() error {
+			return unix.Fsetxattr(fd, name, data, flags)
+		})
+	})
+}
+
+func (f *File) listxattr() (buf []byte, err error) {
+	cerr := f.fdXattr(func(fd int) error {
+		buf, err = readXattr(func(b []byte) (int, error) {
+			return unix.Flistxattr(fd, b)
+		})
+		return err
+	})
+	return buf, cerr
+}
+
+func (f *File) removexattr(name string) error {
+	return f.fdXattr(func(fd int) error {
+		return ignoringEINTR(func() error {
+			return unix.Fremovexattr(fd, name)
+		})
+	})
+}
@@ -6,6 +6,7 @@ package os
 
 import (
 	"internal/poll"
+	"internal/syscall/unix"
 	"io"
 	"syscall"
 )
@@ -13,21 +14,20 @@ import (
 var (
 	pollCopyFileRange = poll.CopyFileRange
 	pollSplice        = poll.Splice
+	unixFiclone       = unix.Ficlone
 )
 
-// wrapSyscallError takes an error and a syscall name. If the error is
-// a syscall.Errno, it wraps it in an os.SyscallError using the syscall name.
-func wrapSyscallError(name string, err error) error {
-	if _, ok := err.(syscall.Errno); ok {
-		err = NewSyscallError(name, err)
-	}
-	return err
-}
-
 func (f *File) writeTo(w io.Writer) (written int64, handled bool, err error) {
 	pfd, network := getPollFDAndNetwork(w)
-	// TODO(panjf2000): same as File.spliceToFile.
-	if pfd == nil || !pfd.IsStream || !isUnixOrTCP(string(network)) {
+	if pfd == nil {
+		// w may be a File referring to a socket, such as one
+		// returned by the File method of a net.Conn.
+		pfd = getStreamSocketPollFD(w)
+	} else if !pfd.IsStream || !isUnixOrTCP(string(network)) {
+		// TODO(panjf2000): same as File.spliceToFile.
+		pfd = nil
+	}
+	if pfd == nil {
 		return
 	}
 
@@ -44,6 +44,9 @@ func (f *File) writeTo(w io.Writer) (written int64, handled bool, err error) {
 	if err == nil {
 		err = rerr
 	}
+	if handled {
+		f.recordCopy(copyMethodSendfile, written)
+	}
 
 	return written, handled, wrapSyscallError("sendfile", err)
 }
@@ -58,11 +61,338 @@ func (f *File) readFrom(r io.Reader) (written int64, handled bool, err error) {
 		return 0, false, nil
 	}
 
+	written, handled, err = f.ficlone(r)
+	if handled {
+		f.recordCopy(copyMethodFiclone, written)
+		return
+	}
+	written, handled, err = f.copySparse(r)
+	if handled {
+		f.recordCopy(copyMethodSparse, written)
+		return
+	}
 	written, handled, err = f.copyFileRange(r)
 	if handled {
+		f.recordCopy(copyMethodCopyFileRange, written)
 		return
 	}
-	return f.spliceToFile(r)
+	written, handled, err = f.spliceFileToFile(r)
+	if handled {
+		f.recordCopy(copyMethodSplice, written)
+		return
+	}
+	written, handled, err = f.spliceToFile(r)
+	if handled {
+		f.recordCopy(copyMethodSplice, written)
+	}
+	return
+}
+
+// cloneFrom is like readFrom, but only tries to make f a clone of src.
+func (f *File) cloneFrom(src *File) (written int64, handled bool, err error) {
+	if f.appendMode {
+		return 0, false, nil
+	}
+	written, handled, err = f.ficlone(src)
+	if handled {
+		f.recordCopy(copyMethodFiclone, written)
+	}
+	return
+}
+
+// ficlone makes the empty regular file f a copy-on-write clone of the
+// regular file r with the FICLONE ioctl, which file systems such as
+// btrfs and XFS complete without copying any data. As the ioctl always
+// clones the whole file, ficlone is only used when it is equivalent to
+// copying: both offsets are at the start, f is empty, and all of r is
+// to be read.
+//
+// ficlone reports handled as false, leaving both files unchanged, if
+// those conditions do not hold, if the files are on different devices,
+// or if the ioctl fails, typically with EOPNOTSUPP or EXDEV.
+func (f *File) ficlone(r io.Reader) (written int64, handled bool, err error) {
+	var (
+		remain int64
+		lr     *io.LimitedReader
+	)
+	if lr, r, remain = tryLimitedReader(r); remain <= 0 {
+		return 0, false, nil
+	}
+
+	var src *File
+	switch v := r.(type) {
+	case *File:
+		src = v
+	case fileWithoutWriteTo:
+		src = v.File
+	default:
+		return 0, false, nil
+	}
+	if src.checkValid("ReadFrom") != nil || src.file == f.file {
+		return 0, false, nil
+	}
+
+	var sst, dst syscall.Stat_t
+	if src.pfd.Fstat(&sst) != nil || f.pfd.Fstat(&dst) != nil {
+		return 0, false, nil
+	}
+	if sst.Mode&syscall.S_IFMT != syscall.S_IFREG || dst.Mode&syscall.S_IFMT != syscall.S_IFREG {
+		return 0, false, nil
+	}
+	if sst.Dev != dst.Dev || dst.Size != 0 || sst.Size == 0 || sst.Size > remain {
+		return 0, false, nil
+	}
+	if off, err := src.pfd.Seek(0, io.SeekCurrent); err != nil || off != 0 {
+		return 0, false, nil
+	}
+	if off, err := f.pfd.Seek(0, io.SeekCurrent); err != nil || off != 0 {
+		return 0, false, nil
+	}
+
+	var serr, ierr error
+	derr := f.pfd.RawControl(func(dfd uintptr) {
+		serr = src.pfd.RawControl(func(sfd uintptr) {
+			ierr = unixFiclone(int(dfd), int(sfd))
+		})
+	})
+	if derr != nil || serr != nil || ierr != nil {
+		// Let copy_file_range or the generic copy take over.
+		return 0, false, nil
+	}
+
+	// src may have grown since we looked at it.
+	if err := f.pfd.Fstat(&dst); err != nil {
+		return 0, true, wrapSyscallError("fstat", err)
+	}
+	written = dst.Size
+	if written > remain {
+		written = remain
+		if err := f.pfd.Ftruncate(written); err != nil {
+			return 0, true, wrapSyscallError("ftruncate", err)
+		}
+	}
+	src.pfd.Seek(written, io.SeekStart)
+	f.pfd.Seek(written, io.SeekStart)
+	if lr != nil {
+		lr.N -= written
+	}
+	return written, true, nil
+}
+
+// getStreamSocketPollFD returns the poll.FD of w if w is a File
+// referring to a stream socket, or nil otherwise. It only asks the
+// kernel for the socket type of a File that may be a socket, so that
+// copies between ordinary files make no extra system call.
+func getStreamSocketPollFD(w io.Writer) *poll.FD {
+	var f *File
+	switch v := w.(type) {
+	case *File:
+		f = v
+	case fileWithoutReadFrom:
+		f = v.File
+	default:
+		return nil
+	}
+	if f.checkValid("WriteTo") != nil || !f.maybeSock {
+		return nil
+	}
+	typ, err := f.pfd.GetsockoptInt(syscall.SOL_SOCKET, syscall.SO_TYPE)
+	if err != nil || typ != syscall.SOCK_STREAM {
+		return nil
+	}
+	return &f.pfd
+}
+
+// spliceFileToFile copies from a File to f through a pipe using splice(2).
+// This is used when copy_file_range(2) is not available for the pair, for
+// example across file systems on kernels older than 5.3, and still avoids
+// copying the data through user space. The pipe comes from the pool kept
+// by poll.Splice, which sizes it to hold a whole splice(2) call. The files
+// are not checked to be regular files: splice fails with EINVAL, without
+// consuming any data, for a source that does not support it, and ReadFrom
+// then falls back to a generic copy.
+func (f *File) spliceFileToFile(r io.Reader) (written int64, handled bool, err error) {
+	var (
+		remain int64
+		lr     *io.LimitedReader
+	)
+	if lr, r, remain = tryLimitedReader(r); remain <= 0 {
+		return 0, true, nil
+	}
+
+	var src *File
+	switch v := r.(type) {
+	case *File:
+		src = v
+	case fileWithoutWriteTo:
+		src = v.File
+	default:
+		return 0, false, nil
+	}
+	if src.checkValid("ReadFrom") != nil || src.file == f.file {
+		return 0, false, nil
+	}
+
+	written, handled, err = pollSplice(&f.pfd, &src.pfd, remain)
+	if lr != nil {
+		lr.N -= written
+	}
+	return written, handled, wrapSyscallError("splice", err)
+}
+
+const (
+	seekData = 3 // SEEK_DATA
+	seekHole = 4 // SEEK_HOLE
+)
+
+// copySparse copies from a regular file containing holes to the regular
+// file f, using lseek(2) with SEEK_DATA and SEEK_HOLE to copy only the data
+// regions so that the holes are preserved in f. It is tried before
+// copy_file_range(2), which writes the holes out as zeros on file systems
+// that cannot share extents, such as ext4, as splice(2) and a plain copy
+// do. Each data region is copied with copy_file_range where it is
+// available, and through a buffer otherwise.
+//
+// copySparse reports handled as false, leaving both offsets unchanged, if
+// the source file has all the blocks its size needs allocated, if the
+// range to copy contains no hole, if the file system cannot report holes,
+// or if f already has data past its offset, which skipping a hole would
+// leave in place instead of zeroing.
+func (f *File) copySparse(r io.Reader) (written int64, handled bool, err error) {
+	var (
+		remain int64
+		lr     *io.LimitedReader
+	)
+	if lr, r, remain = tryLimitedReader(r); remain <= 0 {
+		return 0, true, nil
+	}
+
+	var src *File
+	switch v := r.(type) {
+	case *File:
+		src = v
+	case fileWithoutWriteTo:
+		src = v.File
+	default:
+		return 0, false, nil
+	}
+	if src.checkValid("ReadFrom") != nil || src.file == f.file {
+		return 0, false, nil
+	}
+
+	var sst, dst syscall.Stat_t
+	if src.pfd.Fstat(&sst) != nil || sst.Mode&syscall.S_IFMT != syscall.S_IFREG {
+		return 0, false, nil
+	}
+	// A file with a block allocated for every part of its size has no
+	// holes, which spares most copies the probing below.
+	if int64(sst.Blocks)*512 >= sst.Size {
+		return 0, false, nil
+	}
+	if f.pfd.Fstat(&dst) != nil || dst.Mode&syscall.S_IFMT != syscall.S_IFREG {
+		return 0, false, nil
+	}
+	start, err := src.pfd.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, false, nil
+	}
+	dstart, err := f.pfd.Seek(0, io.SeekCurrent)
+	if err != nil || dstart < dst.Size {
+		return 0, false, nil
+	}
+	end := sst.Size
+	if remain < end-start {
+		end = start + remain
+	}
+	if start >= end {
+		return 0, false, nil
+	}
+
+	// Seeking moves the offset of src, so restore it if we back out.
+	// A file system without hole support reports a single hole at the
+	// end of the file, and EINVAL or ENXIO means there is nothing for us
+	// to do; either way the generic paths are at least as good.
+	hole, err := src.pfd.Seek(start, seekHole)
+	if err != nil || hole >= end {
+		src.pfd.Seek(start, io.SeekStart)
+		return 0, false, nil
+	}
+
+	var buf []byte
+	useCopyFileRange := true
+	off := start
+	for off < end {
+		data, serr := src.pfd.Seek(off, seekData)
+		if serr == syscall.ENXIO {
+			// The rest of the file is a hole.
+			break
+		}
+		if serr != nil {
+			err = wrapSyscallError("seek", serr)
+			break
+		}
+		if data >= end {
+			break
+		}
+		hole, serr := src.pfd.Seek(data, seekHole)
+		if serr != nil {
+			err = wrapSyscallError("seek", serr)
+			break
+		}
+		hole = min(hole, end)
+		off = data
+		if useCopyFileRange {
+			src.pfd.Seek(off, io.SeekStart)
+			f.pfd.Seek(dstart+off-start, io.SeekStart)
+			n, handled, cerr := pollCopyFileRange(&f.pfd, &src.pfd, hole-off)
+			if cerr != nil {
+				err = wrapSyscallError("copy_file_range", cerr)
+				break
+			}
+			useCopyFileRange = handled
+			off += n
+		}
+		if off < hole && buf == nil {
+			buf = make([]byte, 128<<10)
+		}
+		for off < hole {
+			n, rerr := src.pfd.Pread(buf[:min(int64(len(buf)), hole-off)], off)
+			if n > 0 {
+				if _, werr := f.pfd.Pwrite(buf[:n], dstart+off-start); werr != nil {
+					err = wrapSyscallError("pwrite", werr)
+					break
+				}
+				off += int64(n)
+			}
+			if rerr == io.EOF || rerr == nil && n == 0 {
+				// The file was truncated underneath us;
+				// stop at the new end.
+				hole, end = off, off
+				break
+			}
+			if rerr != nil {
+				err = wrapSyscallError("pread", rerr)
+				break
+			}
+		}
+		if err != nil || off < hole {
+			break
+		}
+	}
+	if err == nil {
+		// Extend f over any trailing hole.
+		off = end
+		if terr := f.pfd.Ftruncate(dstart + end - start); terr != nil {
+			err = wrapSyscallError("ftruncate", terr)
+		}
+	}
+	written = off - start
+	src.pfd.Seek(off, io.SeekStart)
+	f.pfd.Seek(dstart+written, io.SeekStart)
+	if lr != nil {
+		lr.N -= written
+	}
+	return written, true, err
 }
 
 func (f *File) spliceToFile(r io.Reader) (written int64, handled bool, err error) {
@@ -126,49 +456,3 @@ func (f *File) copyFileRange(r io.Reader) (written int64, handled bool, err erro
 	}
 	return written, handled, wrapSyscallError("copy_file_range", err)
 }
-
-// getPollFDAndNetwork tries to get the poll.FD and network type from the given interface
-// by expecting the underlying type of i to be the implementation of syscall.Conn
-// that contains a *net.rawConn.
-func getPollFDAndNetwork(i any) (*poll.FD, poll.String) {
-	sc, ok := i.(syscall.Conn)
-	if !ok {
-		return nil, ""
-	}
-	rc, err := sc.SyscallConn()
-	if err != nil {
-		return nil, ""
-	}
-	irc, ok := rc.(interface {
-		PollFD() *poll.FD
-		Network() poll.String
-	})
-	if !ok {
-		return nil, ""
-	}
-	return irc.PollFD(), irc.Network()
-}
-
-// tryLimitedReader tries to assert the io.Reader to io.LimitedReader, it returns the io.LimitedReader,
-// the underlying io.Reader and the remaining amount of bytes if the assertion succeeds,
-// otherwise it just returns the original io.Reader and the theoretical unlimited remaining amount of bytes.
-func tryLimitedReader(r io.Reader) (*io.LimitedReader, io.Reader, int64) {
-	var remain int64 = 1<<63 - 1 // by default, copy until EOF
-
-	lr, ok := r.(*io.LimitedReader)
-	if !ok {
-		return nil, r, remain
-	}
-
-	remain = lr.N
-	return lr, lr.R, remain
-}
-
-func isUnixOrTCP(network string) bool {
-	switch network {
-	case "tcp", "tcp4", "tcp6", "unix":
-		return true
-	default:
-		return false
-	}
-}
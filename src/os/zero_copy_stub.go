@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-//go:build !linux
+//go:build !linux && !windows
 
 package os
 
@@ -15,3 +15,7 @@ func (f *File) writeTo(w io.Writer) (written int64, handled bool, err error) {
 func (f *File) readFrom(r io.Reader) (n int64, handled bool, err error) {
 	return 0, false, nil
 }
+
+func (f *File) cloneFrom(src *File) (n int64, handled bool, err error) {
+	return 0, false, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/poll"
+	"io"
+	"syscall"
+)
+
+func (f *File) writeTo(w io.Writer) (written int64, handled bool, err error) {
+	pfd, network := getPollFDAndNetwork(w)
+	// TransmitFile only sends on connected stream sockets.
+	if pfd == nil || !pfd.IsStream || !isTCP(string(network)) {
+		return
+	}
+
+	sc, err := f.SyscallConn()
+	if err != nil {
+		return
+	}
+
+	rerr := sc.Read(func(fd uintptr) (done bool) {
+		h := syscall.Handle(fd)
+		// TransmitFile needs a file it can read at an offset,
+		// so leave pipes and consoles to the generic path.
+		if ft, _ := syscall.GetFileType(h); ft != syscall.FILE_TYPE_DISK {
+			return true
+		}
+		// A count of 0 asks poll.SendFile to send everything from
+		// the current file offset to the end of the file, in
+		// chunks small enough for a single TransmitFile call.
+		written, err = poll.SendFile(pfd, h, 0)
+		handled = written > 0
+		return true
+	})
+
+	if err == nil {
+		err = rerr
+	}
+	if handled {
+		f.recordCopy(copyMethodSendfile, written)
+	}
+
+	return written, handled, wrapSyscallError("transmitfile", err)
+}
+
+func (f *File) readFrom(r io.Reader) (n int64, handled bool, err error) {
+	return 0, false, nil
+}
+
+func (f *File) cloneFrom(src *File) (n int64, handled bool, err error) {
+	return 0, false, nil
+}
+
+func isTCP(network string) bool {
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+		return true
+	default:
+		return false
+	}
+}
@@ -11,6 +11,7 @@ package syscall
 import (
 	"runtime"
 	"sync"
+	_ "unsafe" // for go:linkname
 )
 
 var (
@@ -90,6 +91,42 @@ func Getenv(key string) (value string, found bool) {
 
 func Setenv(key, value string) error {
 	envOnce.Do(copyenv)
+	if err := checkSetenv(key, value); err != nil {
+		return err
+	}
+
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	setenvLocked(key, value)
+	return nil
+}
+
+// os_setenvBatch sets each keys[i] to values[i] while holding envLock, so
+// that a concurrent Environ or Getenv observes either all of the
+// assignments or none of them. If any assignment is invalid, none are
+// made.
+//
+//go:linkname os_setenvBatch os.setenvBatch
+func os_setenvBatch(keys, values []string) error {
+	envOnce.Do(copyenv)
+	for i, key := range keys {
+		if err := checkSetenv(key, values[i]); err != nil {
+			return err
+		}
+	}
+
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	for i, key := range keys {
+		setenvLocked(key, values[i])
+	}
+	return nil
+}
+
+// checkSetenv reports whether key and value may be passed to Setenv.
+func checkSetenv(key, value string) error {
 	if len(key) == 0 {
 		return EINVAL
 	}
@@ -106,10 +143,11 @@ func Setenv(key, value string) error {
 			}
 		}
 	}
+	return nil
+}
 
-	envLock.Lock()
-	defer envLock.Unlock()
-
+// setenvLocked sets key to value. envLock must be held.
+func setenvLocked(key, value string) {
 	i, ok := env[key]
 	kv := key + "=" + value
 	if ok {
@@ -120,7 +158,6 @@ func Setenv(key, value string) error {
 	}
 	env[key] = i
 	runtimeSetenv(key, value)
-	return nil
 }
 
 func Clearenv() {
@@ -7,17 +7,33 @@
 package syscall
 
 import (
+	"sync"
 	"unsafe"
 )
 
+// envLock serializes changes to the environment made through this
+// package with reads of it, so that os.SetenvBatch is atomic with
+// respect to Getenv and Environ. Code that uses the system environment
+// block directly is not affected.
+var envLock sync.RWMutex
+
 func Getenv(key string) (value string, found bool) {
 	keyp, err := UTF16PtrFromString(key)
 	if err != nil {
 		return "", false
 	}
+	envLock.RLock()
+	defer envLock.RUnlock()
+	return getenvLocked(keyp)
+}
+
+// getenvLocked returns the value of the variable named by keyp.
+// envLock must be held.
+func getenvLocked(keyp *uint16) (value string, found bool) {
 	n := uint32(100)
 	for {
 		b := make([]uint16, n)
+		var err error
 		n, err = GetEnvironmentVariable(keyp, &b[0], uint32(len(b)))
 		if n == 0 && err == ERROR_ENVVAR_NOT_FOUND {
 			return "", false
@@ -29,6 +45,74 @@ func Getenv(key string) (value string, found bool) {
 }
 
 func Setenv(key, value string) error {
+	envLock.Lock()
+	defer envLock.Unlock()
+	return setenvLocked(key, value)
+}
+
+// os_setenvBatch sets each keys[i] to values[i] while holding envLock, so
+// that a concurrent Environ observes either all of the assignments or
+// none of them. Invalid assignments are rejected before any are made, and
+// if the system still fails to make one, those already made are undone.
+//
+//go:linkname os_setenvBatch os.setenvBatch
+func os_setenvBatch(keys, values []string) error {
+	for i, key := range keys {
+		if err := checkSetenv(key); err != nil {
+			return err
+		}
+		if _, err := UTF16PtrFromString(key); err != nil {
+			return err
+		}
+		if _, err := UTF16PtrFromString(values[i]); err != nil {
+			return err
+		}
+	}
+
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	type oldValue struct {
+		value string
+		found bool
+	}
+	olds := make([]oldValue, len(keys))
+	for i, key := range keys {
+		keyp, _ := UTF16PtrFromString(key)
+		olds[i].value, olds[i].found = getenvLocked(keyp)
+	}
+	for i, key := range keys {
+		if err := setenvLocked(key, values[i]); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				if olds[j].found {
+					setenvLocked(keys[j], olds[j].value)
+				} else {
+					unsetenvLocked(keys[j])
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSetenv reports EINVAL for a key that SetEnvironmentVariable
+// would reject: an empty one, or one with '=' after its first byte.
+// The names of the per-drive directories, such as "=C:", start with '='.
+func checkSetenv(key string) error {
+	if len(key) == 0 {
+		return EINVAL
+	}
+	for i := 1; i < len(key); i++ {
+		if key[i] == '=' {
+			return EINVAL
+		}
+	}
+	return nil
+}
+
+// setenvLocked sets key to value. envLock must be held.
+func setenvLocked(key, value string) error {
 	v, err := UTF16PtrFromString(value)
 	if err != nil {
 		return err
@@ -46,6 +130,13 @@ func Setenv(key, value string) error {
 }
 
 func Unsetenv(key string) error {
+	envLock.Lock()
+	defer envLock.Unlock()
+	return unsetenvLocked(key)
+}
+
+// unsetenvLocked unsets key. envLock must be held.
+func unsetenvLocked(key string) error {
 	keyp, err := UTF16PtrFromString(key)
 	if err != nil {
 		return err
@@ -59,13 +150,16 @@ func Unsetenv(key string) error {
 }
 
 func Clearenv() {
-	for _, s := range Environ() {
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	for _, s := range environLocked() {
 		// Environment variables can begin with =
 		// so start looking for the separator = at j=1.
 		// https://devblogs.microsoft.com/oldnewthing/20100506-00/?p=14133
 		for j := 1; j < len(s); j++ {
 			if s[j] == '=' {
-				Unsetenv(s[0:j])
+				unsetenvLocked(s[0:j])
 				break
 			}
 		}
@@ -73,6 +167,13 @@ func Clearenv() {
 }
 
 func Environ() []string {
+	envLock.RLock()
+	defer envLock.RUnlock()
+	return environLocked()
+}
+
+// environLocked returns the environment. envLock must be held.
+func environLocked() []string {
 	envp, e := GetEnvironmentStrings()
 	if e != nil {
 		return nil
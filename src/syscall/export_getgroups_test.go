@@ -0,0 +1,27 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package syscall
+
+import "unsafe"
+
+// GetgroupList calls getgroupList with getgroups in place of the system
+// call. getgroups is passed nil for a size query, and a buffer to fill
+// otherwise.
+func GetgroupList(getgroups func(list []int) (int, error), max int) ([]int, error) {
+	return getgroupList(func(n int, p *_Gid_t) (int, error) {
+		if p == nil {
+			return getgroups(nil)
+		}
+		list := make([]int, n)
+		m, err := getgroups(list)
+		a := unsafe.Slice(p, n)
+		for i := range min(m, n) {
+			a[i] = _Gid_t(list[i])
+		}
+		return m, err
+	}, max)
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package syscall
+
+// getgroupsRetries is the number of times getgroupList asks for the
+// size of the group list again after it changed between calls.
+const getgroupsRetries = 10
+
+// getgroupList returns the supplementary group IDs reported by
+// getgroups, checking the group count against max.
+//
+// The list is fetched by asking getgroups for its size and then
+// filling a buffer of that size. If the list grows in between, for
+// example because another thread called setgroups, the second call
+// fails with EINVAL rather than truncating the list, so getgroupList
+// asks for the size again and retries with a larger buffer.
+func getgroupList(getgroups func(n int, list *_Gid_t) (int, error), max int) ([]int, error) {
+	var a []_Gid_t
+	for range getgroupsRetries {
+		n, err := getgroups(0, nil)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, nil
+		}
+
+		// Sanity check group count.
+		if n < 0 || n > max {
+			return nil, EINVAL
+		}
+
+		if n > len(a) {
+			a = make([]_Gid_t, n)
+		}
+		n, err = getgroups(len(a), &a[0])
+		if err == EINVAL {
+			// The list grew since we asked for its size.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 || n > len(a) {
+			return nil, EINVAL
+		}
+		gids := make([]int, n)
+		for i, v := range a[:n] {
+			gids[i] = int(v)
+		}
+		return gids, nil
+	}
+	return nil, EINVAL
+}
@@ -393,29 +393,8 @@ func Getwd() (wd string, err error) {
 }
 
 func Getgroups() (gids []int, err error) {
-	n, err := getgroups(0, nil)
-	if err != nil {
-		return nil, err
-	}
-	if n == 0 {
-		return nil, nil
-	}
-
-	// Sanity check group count. Max is 1<<16 on Linux.
-	if n < 0 || n > 1<<20 {
-		return nil, EINVAL
-	}
-
-	a := make([]_Gid_t, n)
-	n, err = getgroups(n, &a[0])
-	if err != nil {
-		return nil, err
-	}
-	gids = make([]int, n)
-	for i, v := range a[0:n] {
-		gids[i] = int(v)
-	}
-	return
+	// Max is 1<<16 on Linux.
+	return getgroupList(getgroups, 1<<20)
 }
 
 var cgo_libc_setgroups unsafe.Pointer // non-nil if cgo linked.
@@ -16,6 +16,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"syscall"
 	"testing"
@@ -351,3 +352,61 @@ func TestENFILETemporary(t *testing.T) {
 		t.Error("ENFILE is not treated as a temporary error")
 	}
 }
+
+func TestGetgroupListGrowth(t *testing.T) {
+	groups := []int{1, 2, 3}
+	calls := 0
+	getgroups := func(list []int) (int, error) {
+		calls++
+		if list == nil {
+			return len(groups), nil
+		}
+		// Simulate another thread adding groups between the
+		// size query and the first attempt to fill the buffer.
+		if calls == 2 {
+			groups = append(groups, 4, 5)
+		}
+		if len(list) < len(groups) {
+			return -1, syscall.EINVAL
+		}
+		return copy(list, groups), nil
+	}
+	gids, err := syscall.GetgroupList(getgroups, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(gids, groups) {
+		t.Errorf("GetgroupList = %v, want %v", gids, groups)
+	}
+	if calls != 4 {
+		t.Errorf("getgroups called %d times, want 4", calls)
+	}
+
+	// A list that keeps changing eventually fails instead of looping.
+	calls = 0
+	_, err = syscall.GetgroupList(func(list []int) (int, error) {
+		calls++
+		if list == nil {
+			return 1, nil
+		}
+		return -1, syscall.EINVAL
+	}, 1000)
+	if err != syscall.EINVAL {
+		t.Errorf("GetgroupList with a changing list: got error %v, want EINVAL", err)
+	}
+
+	// Too many groups.
+	_, err = syscall.GetgroupList(func(list []int) (int, error) {
+		return 2000, nil
+	}, 1000)
+	if err != syscall.EINVAL {
+		t.Errorf("GetgroupList with 2000 groups: got error %v, want EINVAL", err)
+	}
+
+	gids, err = syscall.GetgroupList(func(list []int) (int, error) {
+		return 0, nil
+	}, 1000)
+	if gids != nil || err != nil {
+		t.Errorf("GetgroupList with no groups = %v, %v; want nil, nil", gids, err)
+	}
+}